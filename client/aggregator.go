@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// ErrToolNotFound is returned by Aggregator.CallTool for tool names that no
+// aggregated server provides.
+var ErrToolNotFound = errors.New("tool not found")
+
+// Aggregator presents the tools of several MCP servers, each reached through
+// its own client, as a single set, e.g. to hand all of them to one LLM.
+//
+// Tools are listed under their own name when only one server provides them.
+// When several servers provide a tool with the same name, each is listed as
+// "<server>_<tool>" instead, where <server> is the name given to Add, so the
+// result does not depend on the order in which servers answer. CallTool
+// accepts the listed names as well as "<server>_<tool>" for any tool.
+//
+// The clients must be started and initialized before they are added.
+type Aggregator struct {
+	mu      sync.RWMutex
+	servers []aggregatedServer
+	routes  map[string]toolRoute
+}
+
+// aggregatedServer is a client added to an Aggregator under a name.
+type aggregatedServer struct {
+	name   string
+	client MCPClient
+}
+
+// toolRoute tells CallTool which client serves a listed tool and under which
+// name the server knows it.
+type toolRoute struct {
+	client MCPClient
+	tool   string
+}
+
+// NewAggregator creates an Aggregator without any servers.
+func NewAggregator() *Aggregator {
+	return &Aggregator{}
+}
+
+// Add adds the server reached through client under name, which must be
+// unique within the aggregator and is used to prefix its tools. It is listed
+// by the next call to ListTools.
+func (a *Aggregator) Add(name string, client MCPClient) error {
+	if name == "" {
+		return errors.New("server name must not be empty")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, server := range a.servers {
+		if server.name == name {
+			return fmt.Errorf("server %q already added", name)
+		}
+	}
+	a.servers = append(a.servers, aggregatedServer{name: name, client: client})
+	return nil
+}
+
+// ListTools lists the tools of all servers, sorted by name, and remembers
+// which server provides each of them for CallTool.
+func (a *Aggregator) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	a.mu.RLock()
+	servers := a.servers
+	a.mu.RUnlock()
+
+	type serverTool struct {
+		server aggregatedServer
+		tool   mcp.Tool
+	}
+	var all []serverTool
+	count := make(map[string]int)
+	for _, server := range servers {
+		result, err := server.client.ListTools(ctx, mcp.ListToolsRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("list tools of %s: %w", server.name, err)
+		}
+		for _, tool := range result.Tools {
+			all = append(all, serverTool{server: server, tool: tool})
+			count[tool.Name]++
+		}
+	}
+
+	tools := make([]mcp.Tool, 0, len(all))
+	routes := make(map[string]toolRoute, len(all))
+	for _, entry := range all {
+		route := toolRoute{client: entry.server.client, tool: entry.tool.Name}
+		tool := entry.tool
+		if count[tool.Name] > 1 {
+			tool.Name = entry.server.name + "_" + tool.Name
+		}
+		routes[tool.Name] = route
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+
+	a.mu.Lock()
+	a.routes = routes
+	a.mu.Unlock()
+	return tools, nil
+}
+
+// CallTool calls the tool with the requested name on the server providing
+// it. Tools are looked up among the names returned by the last ListTools,
+// which is called first if it never was, and then as "<server>_<tool>".
+func (a *Aggregator) CallTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	a.mu.RLock()
+	listed := a.routes != nil
+	a.mu.RUnlock()
+	if !listed {
+		if _, err := a.ListTools(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	route, ok := a.route(request.Params.Name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, request.Params.Name)
+	}
+	request.Params.Name = route.tool
+	return route.client.CallTool(ctx, request)
+}
+
+// route finds the server providing the tool listed as name.
+func (a *Aggregator) route(name string) (toolRoute, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if route, ok := a.routes[name]; ok {
+		return route, true
+	}
+
+	// Prefer the longest matching server name, so that a server named
+	// "a_b" wins over a server named "a" for "a_b_tool".
+	var match *aggregatedServer
+	for i, server := range a.servers {
+		if strings.HasPrefix(name, server.name+"_") && (match == nil || len(server.name) > len(match.name)) {
+			match = &a.servers[i]
+		}
+	}
+	if match == nil {
+		return toolRoute{}, false
+	}
+	return toolRoute{client: match.client, tool: strings.TrimPrefix(name, match.name+"_")}, true
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/zillow/mcp-go/mcp"
+	"github.com/zillow/mcp-go/server"
+)
+
+func TestAggregator(t *testing.T) {
+	newClient := func(name string, tools ...string) *Client {
+		mcpServer := server.NewMCPServer(name, "1.0.0", server.WithToolCapabilities(true))
+		for _, tool := range tools {
+			mcpServer.AddTool(mcp.NewTool(tool), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText(name + "/" + request.Params.Name), nil
+			})
+		}
+
+		client, err := NewInProcessClient(mcpServer)
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		t.Cleanup(func() { client.Close() })
+		if err := client.Start(context.Background()); err != nil {
+			t.Fatalf("Failed to start client: %v", err)
+		}
+		initRequest := mcp.InitializeRequest{}
+		initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+		initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+		if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+			t.Fatalf("Failed to initialize: %v", err)
+		}
+		return client
+	}
+
+	aggregator := NewAggregator()
+	if err := aggregator.Add("weather", newClient("weather", "forecast", "search")); err != nil {
+		t.Fatalf("Failed to add weather: %v", err)
+	}
+	if err := aggregator.Add("docs", newClient("docs", "search")); err != nil {
+		t.Fatalf("Failed to add docs: %v", err)
+	}
+	if err := aggregator.Add("docs", newClient("docs", "search")); err == nil {
+		t.Error("Expected an error when adding a server name twice")
+	}
+
+	call := func(name string) (string, error) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		result, err := aggregator.CallTool(context.Background(), request)
+		if err != nil {
+			return "", err
+		}
+		text, _ := result.FirstText()
+		return text, nil
+	}
+
+	t.Run("Routes before listing", func(t *testing.T) {
+		text, err := call("forecast")
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if text != "weather/forecast" {
+			t.Errorf("Expected weather/forecast, got %q", text)
+		}
+	})
+
+	t.Run("Prefixes colliding names", func(t *testing.T) {
+		tools, err := aggregator.ListTools(context.Background())
+		if err != nil {
+			t.Fatalf("ListTools failed: %v", err)
+		}
+		var names []string
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+		expected := []string{"docs_search", "forecast", "weather_search"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected tools %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("Routes to the providing server", func(t *testing.T) {
+		for name, expected := range map[string]string{
+			"forecast":         "weather/forecast",
+			"weather_forecast": "weather/forecast",
+			"weather_search":   "weather/search",
+			"docs_search":      "docs/search",
+		} {
+			text, err := call(name)
+			if err != nil {
+				t.Errorf("CallTool(%s) failed: %v", name, err)
+				continue
+			}
+			if text != expected {
+				t.Errorf("CallTool(%s): expected %q, got %q", name, expected, text)
+			}
+		}
+	})
+
+	t.Run("Unknown tools", func(t *testing.T) {
+		for _, name := range []string{"search", "translate"} {
+			if _, err := call(name); !errors.Is(err, ErrToolNotFound) {
+				t.Errorf("CallTool(%s): expected ErrToolNotFound, got %v", name, err)
+			}
+		}
+	})
+}
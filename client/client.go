@@ -3,10 +3,10 @@ package client
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/zillow/mcp-go/client/transport"
 	"github.com/zillow/mcp-go/mcp"
@@ -16,23 +16,69 @@ import (
 type Client struct {
 	transport transport.Interface
 
-	initialized        bool
+	initialized        atomic.Bool
 	notifications      []func(mcp.JSONRPCNotification)
+	disconnectHandlers []func(err error)
 	notifyMu           sync.RWMutex
+	connectionState    atomic.Int32
 	requestID          atomic.Int64
 	clientCapabilities mcp.ClientCapabilities
 	serverCapabilities mcp.ServerCapabilities
+	expectedServerInfo *mcp.Implementation
+	logger             transport.Logger
+	defaultTimeout     time.Duration
+
+	skipInitializedNotification bool
+
+	requestMu       sync.RWMutex
+	requestHandlers map[string]transport.RequestHandler
+
+	progressID       atomic.Int64
+	progressMu       sync.RWMutex
+	progressHandlers map[string]ProgressFunc
 }
 
 type ClientOption func(*Client)
 
-// WithClientCapabilities sets the client capabilities for the client.
+// WithClientCapabilities sets the client capabilities for the client. They
+// are declared in the initialize request for any capability the request
+// itself leaves unset.
 func WithClientCapabilities(capabilities mcp.ClientCapabilities) ClientOption {
 	return func(c *Client) {
 		c.clientCapabilities = capabilities
 	}
 }
 
+// WithLogger sets the Logger receiving the client's diagnostics. It is also
+// passed on to the transport if the transport accepts one, e.g. for
+// transport.Stdio, transport.SSE and transport.StreamableHTTP.
+func WithLogger(logger transport.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithPingHandler replaces the handler answering ping requests from the
+// server, which by default responds with an empty result.
+func WithPingHandler(handler transport.RequestHandler) ClientOption {
+	return func(c *Client) {
+		c.requestHandlers[string(mcp.MethodPing)] = handler
+	}
+}
+
+// WithoutInitializedNotification stops Initialize from sending the
+// notifications/initialized notification, for servers that do not expect it.
+func WithoutInitializedNotification() ClientOption {
+	return func(c *Client) {
+		c.skipInitializedNotification = true
+	}
+}
+
+// loggerSetter is implemented by transports accepting a transport.Logger.
+type loggerSetter interface {
+	SetLogger(logger transport.Logger)
+}
+
 // NewClient creates a new MCP client with the given transport.
 // Usage:
 //
@@ -43,13 +89,20 @@ func WithClientCapabilities(capabilities mcp.ClientCapabilities) ClientOption {
 //	}
 func NewClient(transport transport.Interface, options ...ClientOption) *Client {
 	client := &Client{
-		transport: transport,
+		transport:       transport,
+		requestHandlers: defaultRequestHandlers(),
 	}
 
 	for _, opt := range options {
 		opt(client)
 	}
 
+	if client.logger != nil {
+		if t, ok := transport.(loggerSetter); ok {
+			t.SetLogger(client.logger)
+		}
+	}
+
 	return client
 }
 
@@ -59,23 +112,38 @@ func (c *Client) Start(ctx context.Context) error {
 	if c.transport == nil {
 		return fmt.Errorf("transport is nil")
 	}
+	if t, ok := c.transport.(transport.ConnectionStateNotifier); ok {
+		t.SetConnectionStateHandler(c.handleConnectionStateChange)
+	}
+	// Set before starting so a connection lost right away is not
+	// overwritten.
+	c.connectionState.Store(int32(StateConnected))
 	err := c.transport.Start(ctx)
 	if err != nil {
+		c.connectionState.Store(int32(StateDisconnected))
 		return err
 	}
 
 	c.transport.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+		if notification.Method == mcp.MethodNotificationProgress {
+			c.handleProgress(notification)
+		}
+
 		c.notifyMu.RLock()
 		defer c.notifyMu.RUnlock()
 		for _, handler := range c.notifications {
 			handler(notification)
 		}
 	})
+	if t, ok := c.transport.(transport.BidirectionalInterface); ok {
+		t.SetRequestHandler(c.handleServerRequest)
+	}
 	return nil
 }
 
 // Close shuts down the client and closes the transport.
 func (c *Client) Close() error {
+	c.connectionState.Store(int32(StateDisconnected))
 	return c.transport.Close()
 }
 
@@ -96,11 +164,12 @@ func (c *Client) sendRequest(
 	method string,
 	params any,
 ) (*json.RawMessage, error) {
-	if !c.initialized && method != "initialize" {
+	if !c.initialized.Load() && method != "initialize" {
 		return nil, fmt.Errorf("client not initialized")
 	}
 
-	id := c.requestID.Add(1)
+	id := c.nextRequestID()
+	notifyRequestID(ctx, method, id)
 
 	request := transport.JSONRPCRequest{
 		JSONRPC: mcp.JSONRPC_VERSION,
@@ -109,13 +178,20 @@ func (c *Client) sendRequest(
 		Params:  params,
 	}
 
+	ctx, cancel := c.requestContext(ctx)
+	defer cancel()
+
 	response, err := c.transport.SendRequest(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("transport error: %w", err)
 	}
 
 	if response.Error != nil {
-		return nil, errors.New(response.Error.Message)
+		return nil, &mcp.JSONRPCErrorError{
+			Code:    response.Error.Code,
+			Message: response.Error.Message,
+			Data:    response.Error.Data,
+		}
 	}
 
 	return &response.Result, nil
@@ -132,12 +208,37 @@ func (c *Client) Initialize(
 		ProtocolVersion string                 `json:"protocolVersion"`
 		ClientInfo      mcp.Implementation     `json:"clientInfo"`
 		Capabilities    mcp.ClientCapabilities `json:"capabilities"`
+		Meta            *mcp.Meta              `json:"_meta,omitempty"`
 	}{
 		ProtocolVersion: request.Params.ProtocolVersion,
 		ClientInfo:      request.Params.ClientInfo,
 		Capabilities:    request.Params.Capabilities, // Will be empty struct if not set
+		Meta:            request.Params.Meta,
+	}
+
+	// Capabilities set with WithClientCapabilities fill in those the
+	// request leaves unset.
+	if params.Capabilities.Experimental == nil {
+		params.Capabilities.Experimental = c.clientCapabilities.Experimental
+	}
+	if params.Capabilities.Roots == nil {
+		params.Capabilities.Roots = c.clientCapabilities.Roots
+	}
+	if params.Capabilities.Sampling == nil {
+		params.Capabilities.Sampling = c.clientCapabilities.Sampling
 	}
 
+	c.requestMu.RLock()
+	if c.requestHandlers[string(mcp.MethodSamplingCreateMessage)] != nil && params.Capabilities.Sampling == nil {
+		params.Capabilities.Sampling = &struct{}{}
+	}
+	if c.requestHandlers[string(mcp.MethodRootsList)] != nil && params.Capabilities.Roots == nil {
+		params.Capabilities.Roots = &struct {
+			ListChanged bool `json:"listChanged,omitempty"`
+		}{}
+	}
+	c.requestMu.RUnlock()
+
 	response, err := c.sendRequest(ctx, "initialize", params)
 	if err != nil {
 		return nil, err
@@ -148,27 +249,65 @@ func (c *Client) Initialize(
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
+	if err := verifyServerInfo(c.expectedServerInfo, result.ServerInfo); err != nil {
+		return nil, err
+	}
+
 	// Store serverCapabilities
 	c.serverCapabilities = result.Capabilities
 
 	// Send initialized notification
+	if !c.skipInitializedNotification {
+		notification := mcp.JSONRPCNotification{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			Notification: mcp.Notification{
+				Method: "notifications/initialized",
+			},
+		}
+
+		err = c.transport.SendNotification(ctx, notification)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to send initialized notification: %w",
+				err,
+			)
+		}
+	}
+
+	c.initialized.Store(true)
+	return &result, nil
+}
+
+// SendNotification sends a notification with the given method to the server,
+// e.g. notifications/cancelled or notifications/roots/list_changed. params
+// must marshal to a JSON object, or be nil for a notification without params.
+// Must be called after Initialize.
+func (c *Client) SendNotification(ctx context.Context, method string, params any) error {
+	if !c.initialized.Load() {
+		return fmt.Errorf("client not initialized")
+	}
+
 	notification := mcp.JSONRPCNotification{
 		JSONRPC: mcp.JSONRPC_VERSION,
 		Notification: mcp.Notification{
-			Method: "notifications/initialized",
+			Method: method,
 		},
 	}
 
-	err = c.transport.SendNotification(ctx, notification)
-	if err != nil {
-		return nil, fmt.Errorf(
-			"failed to send initialized notification: %w",
-			err,
-		)
+	if params != nil {
+		paramBytes, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal notification params: %w", err)
+		}
+		if err := json.Unmarshal(paramBytes, &notification.Params); err != nil {
+			return fmt.Errorf("notification params must be a JSON object: %w", err)
+		}
 	}
 
-	c.initialized = true
-	return &result, nil
+	if err := c.transport.SendNotification(ctx, notification); err != nil {
+		return fmt.Errorf("transport error: %w", err)
+	}
+	return nil
 }
 
 func (c *Client) Ping(ctx context.Context) error {
@@ -176,6 +315,22 @@ func (c *Client) Ping(ctx context.Context) error {
 	return err
 }
 
+// defaultHealthCheckTimeout bounds HealthCheck when it is given no timeout.
+const defaultHealthCheckTimeout = 5 * time.Second
+
+// HealthCheck pings the server and reports whether it answered within
+// timeout, or within five seconds if timeout is not positive, for use in
+// readiness probes. The ping is sent with a context of its own, so it is not
+// affected by the cancellation of other requests and does not affect them.
+func (c *Client) HealthCheck(timeout time.Duration) bool {
+	if timeout <= 0 {
+		timeout = defaultHealthCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return c.Ping(ctx) == nil
+}
+
 // ListResourcesByPage manually list resources by page.
 func (c *Client) ListResourcesByPage(
 	ctx context.Context,
@@ -261,6 +416,23 @@ func (c *Client) ReadResource(
 	return mcp.ParseReadResourceResult(response)
 }
 
+// ReadResourceRange reads length bytes of the resource at uri, starting at
+// offset. A length of zero reads until the end of the resource.
+// The server's handler must support range reads for the range to be honored.
+func (c *Client) ReadResourceRange(
+	ctx context.Context,
+	uri string,
+	offset, length int64,
+) (*mcp.ReadResourceResult, error) {
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	request.Params.Range = &mcp.ResourceRange{
+		Offset: offset,
+		Length: length,
+	}
+	return c.ReadResource(ctx, request)
+}
+
 func (c *Client) Subscribe(
 	ctx context.Context,
 	request mcp.SubscribeRequest,
@@ -423,6 +595,12 @@ func (c *Client) GetTransport() transport.Interface {
 	return c.transport
 }
 
+// IsInitialized reports whether Initialize has completed successfully, so
+// request methods can be called.
+func (c *Client) IsInitialized() bool {
+	return c.initialized.Load()
+}
+
 // GetServerCapabilities returns the server capabilities.
 func (c *Client) GetServerCapabilities() mcp.ServerCapabilities {
 	return c.serverCapabilities
@@ -0,0 +1,249 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zillow/mcp-go/client/transport"
+	"github.com/zillow/mcp-go/mcp"
+	"github.com/zillow/mcp-go/server"
+)
+
+func TestClient_ErrorData(t *testing.T) {
+	mock := transport.NewMock()
+	mock.On("initialize").Return(mcp.InitializeResult{
+		ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		ServerInfo:      mcp.Implementation{Name: "mock-server", Version: "1.0.0"},
+	})
+	mock.On("tools/call").ReturnErrorWithData(-32001, "quota exceeded", map[string]any{
+		"retryAfter": 30,
+		"limit":      "requests-per-minute",
+	})
+	mock.On("ping").ReturnError(mcp.INTERNAL_ERROR, "boom")
+
+	client := NewClient(mock)
+	ctx := context.Background()
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	defer client.Close()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	callRequest := mcp.CallToolRequest{}
+	callRequest.Params.Name = "search"
+	_, err := client.CallTool(ctx, callRequest)
+
+	var rpcErr *mcp.JSONRPCErrorError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("Expected a *mcp.JSONRPCErrorError, got %T: %v", err, err)
+	}
+	if rpcErr.Code != -32001 || rpcErr.Message != "quota exceeded" || err.Error() != "quota exceeded" {
+		t.Errorf("Unexpected error: %+v", rpcErr)
+	}
+
+	var data struct {
+		RetryAfter int    `json:"retryAfter"`
+		Limit      string `json:"limit"`
+	}
+	if err := json.Unmarshal(rpcErr.Data, &data); err != nil {
+		t.Fatalf("Failed to decode error data %s: %v", rpcErr.Data, err)
+	}
+	if data.RetryAfter != 30 || data.Limit != "requests-per-minute" {
+		t.Errorf("Unexpected error data: %s", rpcErr.Data)
+	}
+
+	err = client.Ping(ctx)
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("Expected a *mcp.JSONRPCErrorError, got %T: %v", err, err)
+	}
+	if rpcErr.Code != mcp.INTERNAL_ERROR || len(rpcErr.Data) != 0 {
+		t.Errorf("Expected an error without data, got %+v", rpcErr)
+	}
+}
+
+func TestClient_CompletePagination(t *testing.T) {
+	values := make([]string, 100)
+	for i := range values {
+		values[i] = fmt.Sprintf("value-%d", i)
+	}
+	mock := transport.NewMock()
+	mock.On("initialize").Return(mcp.InitializeResult{
+		ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		ServerInfo:      mcp.Implementation{Name: "mock-server", Version: "1.0.0"},
+	})
+	mock.On("completion/complete").Return(mcp.CompleteResult{
+		Completion: mcp.Completion{Values: values, Total: 500, HasMore: true},
+	})
+
+	client := NewClient(mock)
+	ctx := context.Background()
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	defer client.Close()
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	request := mcp.CompleteRequest{}
+	request.Params.Ref = mcp.ResourceReference{Type: "ref/resource", URI: "paged://{id}"}
+	request.Params.Argument.Name = "id"
+	result, err := client.Complete(ctx, request)
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	if len(result.Completion.Values) != 100 {
+		t.Errorf("Expected 100 values, got %d", len(result.Completion.Values))
+	}
+	if result.Completion.Total != 500 || !result.Completion.HasMore {
+		t.Errorf("Expected total 500 and hasMore, got %d and %v", result.Completion.Total, result.Completion.HasMore)
+	}
+}
+
+func TestClient_InitializedNotification(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []ClientOption
+		want    int
+	}{
+		{name: "Sent after initialize", want: 1},
+		{name: "Disabled", options: []ClientOption{WithoutInitializedNotification()}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := transport.NewMock()
+			mock.On("initialize").Return(mcp.InitializeResult{
+				ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+				ServerInfo:      mcp.Implementation{Name: "mock-server", Version: "1.0.0"},
+			})
+
+			client := NewClient(mock, tt.options...)
+			ctx := context.Background()
+			if err := client.Start(ctx); err != nil {
+				t.Fatalf("Failed to start client: %v", err)
+			}
+			defer client.Close()
+
+			initRequest := mcp.InitializeRequest{}
+			initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+			initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+			if _, err := client.Initialize(ctx, initRequest); err != nil {
+				t.Fatalf("Failed to initialize: %v", err)
+			}
+
+			var sent int
+			for _, notification := range mock.Notifications() {
+				if notification.Method == "notifications/initialized" {
+					sent++
+				}
+			}
+			if sent != tt.want {
+				t.Errorf("Expected %d initialized notifications, got %d", tt.want, sent)
+			}
+			if !client.IsInitialized() {
+				t.Errorf("Expected client to be initialized")
+			}
+		})
+	}
+}
+
+func TestClient_OnDisconnect(t *testing.T) {
+	serverOutput, clientInput := io.Pipe()
+	stdio := transport.NewIO(serverOutput, nopWriteCloser{io.Discard}, io.NopCloser(strings.NewReader("")))
+
+	client := NewClient(stdio)
+	if state := client.ConnectionState(); state != StateDisconnected {
+		t.Errorf("Expected %s before Start, got %s", StateDisconnected, state)
+	}
+
+	disconnected := make(chan error, 1)
+	client.OnDisconnect(func(err error) {
+		disconnected <- err
+	})
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	defer client.Close()
+	if state := client.ConnectionState(); state != StateConnected {
+		t.Errorf("Expected %s after Start, got %s", StateConnected, state)
+	}
+
+	// The server going away ends the stream the transport reads from.
+	clientInput.Close()
+
+	select {
+	case err := <-disconnected:
+		if !errors.Is(err, transport.ErrConnectionLost) {
+			t.Errorf("Expected ErrConnectionLost, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for OnDisconnect")
+	}
+	if state := client.ConnectionState(); state != StateDisconnected {
+		t.Errorf("Expected %s after disconnect, got %s", StateDisconnected, state)
+	}
+}
+
+func TestClient_HealthCheck(t *testing.T) {
+	client, err := NewInProcessClient(server.NewMCPServer("test-server", "1.0.0"))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	if !client.HealthCheck(time.Second) {
+		t.Error("Expected a live server to be healthy")
+	}
+	if !client.HealthCheck(0) {
+		t.Error("Expected a live server to be healthy with the default timeout")
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Failed to close client: %v", err)
+	}
+	if client.HealthCheck(time.Second) {
+		t.Error("Expected a closed client to be unhealthy")
+	}
+}
+
+func TestClient_HealthCheckTimeout(t *testing.T) {
+	client := NewClient(&hangingTransport{deadlines: make(chan time.Time, 1)})
+	client.initialized.Store(true)
+
+	start := time.Now()
+	if client.HealthCheck(50 * time.Millisecond) {
+		t.Error("Expected an unresponsive server to be unhealthy")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected HealthCheck to give up after its timeout, took %v", elapsed)
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
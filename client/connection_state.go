@@ -0,0 +1,55 @@
+package client
+
+import (
+	"github.com/zillow/mcp-go/client/transport"
+)
+
+// State is the state of the client's connection to the server, as reported
+// by ConnectionState.
+type State = transport.ConnectionState
+
+const (
+	// StateDisconnected means the client has not been started, was closed,
+	// or lost its connection.
+	StateDisconnected = transport.ConnectionDisconnected
+	// StateConnected means the client can exchange messages with the server.
+	StateConnected = transport.ConnectionConnected
+	// StateReconnecting means the connection was lost and the transport is
+	// trying to re-establish it, e.g. a stdio transport created with
+	// transport.WithStdioAutoRestart relaunching its subprocess.
+	StateReconnecting = transport.ConnectionReconnecting
+)
+
+// ConnectionState returns the state of the client's connection to the
+// server. Transports that do not implement transport.ConnectionStateNotifier
+// are reported as connected from a successful Start until Close.
+func (c *Client) ConnectionState() State {
+	return State(c.connectionState.Load())
+}
+
+// OnDisconnect registers a handler called with the cause when the
+// transport loses its connection to the server, e.g. when the SSE stream
+// ends or the stdio subprocess dies. It is not called when the connection
+// is closed with Close. Multiple handlers can be registered and will be
+// called in the order they were added.
+func (c *Client) OnDisconnect(handler func(err error)) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+	c.disconnectHandlers = append(c.disconnectHandlers, handler)
+}
+
+// handleConnectionStateChange records a state change reported by the
+// transport and calls the OnDisconnect handlers when the connection is lost.
+func (c *Client) handleConnectionStateChange(state transport.ConnectionState, err error) {
+	c.connectionState.Store(int32(state))
+	if state != StateDisconnected {
+		return
+	}
+
+	c.notifyMu.RLock()
+	handlers := c.disconnectHandlers
+	c.notifyMu.RUnlock()
+	for _, handler := range handlers {
+		handler(err)
+	}
+}
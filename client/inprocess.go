@@ -6,7 +6,7 @@ import (
 )
 
 // NewInProcessClient connect directly to a mcp server object in the same process
-func NewInProcessClient(server *server.MCPServer) (*Client, error) {
+func NewInProcessClient(server *server.MCPServer, options ...ClientOption) (*Client, error) {
 	inProcessTransport := transport.NewInProcessTransport(server)
-	return NewClient(inProcessTransport), nil
+	return NewClient(inProcessTransport, options...), nil
 }
@@ -2,8 +2,12 @@ package client
 
 import (
 	"context"
+	"encoding/base64"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/zillow/mcp-go/client/transport"
 	"github.com/zillow/mcp-go/mcp"
 	"github.com/zillow/mcp-go/server"
 )
@@ -419,3 +423,490 @@ func TestInProcessMCPClient(t *testing.T) {
 		}
 	})
 }
+
+func TestInProcessMCPClient_ReadResourceRange(t *testing.T) {
+	mcpServer := server.NewMCPServer(
+		"test-server",
+		"1.0.0",
+		server.WithResourceCapabilities(false, false),
+	)
+
+	data := []byte("0123456789abcdef")
+	mcpServer.AddResource(
+		mcp.NewResource("resource://blob", "Blob", mcp.WithMIMEType("application/octet-stream")),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return mcp.ApplyResourceRange([]mcp.ResourceContents{
+				mcp.BlobResourceContents{
+					URI:      "resource://blob",
+					MIMEType: "application/octet-stream",
+					Blob:     base64.StdEncoding.EncodeToString(data),
+				},
+			}, request.Params.Range)
+		},
+	)
+
+	client, err := NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		offset int64
+		length int64
+		want   string
+	}{
+		{name: "middle slice", offset: 4, length: 6, want: "456789"},
+		{name: "until end", offset: 10, length: 0, want: "abcdef"},
+		{name: "length past end", offset: 12, length: 100, want: "cdef"},
+		{name: "offset past end", offset: 100, length: 4, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.ReadResourceRange(context.Background(), "resource://blob", tt.offset, tt.length)
+			if err != nil {
+				t.Fatalf("ReadResourceRange failed: %v", err)
+			}
+			if len(result.Contents) != 1 {
+				t.Fatalf("Expected 1 content item, got %d", len(result.Contents))
+			}
+			blob, ok := mcp.AsBlobResourceContents(result.Contents[0])
+			if !ok {
+				t.Fatalf("Expected blob contents, got %T", result.Contents[0])
+			}
+			got, err := base64.StdEncoding.DecodeString(blob.Blob)
+			if err != nil {
+				t.Fatalf("Failed to decode blob: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInProcessMCPClient_SendNotification(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+
+	received := make(chan mcp.JSONRPCNotification, 1)
+	mcpServer.AddNotificationHandler("notifications/cancelled", func(ctx context.Context, notification mcp.JSONRPCNotification) {
+		received <- notification
+	})
+
+	client, err := NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	if err := client.SendNotification(context.Background(), "notifications/cancelled", nil); err == nil {
+		t.Errorf("Expected an error when sending a notification before Initialize")
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	err = client.SendNotification(context.Background(), "notifications/cancelled", map[string]any{
+		"requestId": 7,
+		"reason":    "user aborted",
+	})
+	if err != nil {
+		t.Fatalf("SendNotification failed: %v", err)
+	}
+
+	select {
+	case notification := <-received:
+		if notification.Method != "notifications/cancelled" {
+			t.Errorf("Expected method notifications/cancelled, got %s", notification.Method)
+		}
+		if reason := notification.Params.AdditionalFields["reason"]; reason != "user aborted" {
+			t.Errorf("Expected reason 'user aborted', got %v", reason)
+		}
+		if requestID := notification.Params.AdditionalFields["requestId"]; requestID != float64(7) {
+			t.Errorf("Expected requestId 7, got %v", requestID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the notification")
+	}
+
+	if err := client.SendNotification(context.Background(), "notifications/cancelled", []int{1}); err == nil {
+		t.Errorf("Expected an error for non-object params")
+	}
+}
+
+func TestInProcessMCPClient_IsInitialized(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+
+	client, err := NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	if client.IsInitialized() {
+		t.Error("Expected client not to be initialized before Initialize")
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	if !client.IsInitialized() {
+		t.Error("Expected client to be initialized after Initialize")
+	}
+}
+
+func TestInProcessMCPClient_Meta(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(
+		mcp.NewTool("traced-tool"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result := mcp.NewToolResultText(fmt.Sprint(request.Params.Meta.GetProgressToken()))
+			result.Meta = map[string]any{"traceId": request.Params.Meta.AdditionalFields["traceId"]}
+			return result, nil
+		},
+	)
+
+	client, err := NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "traced-tool"
+	request.Params.Meta = &mcp.Meta{
+		ProgressToken:    "progress-1",
+		AdditionalFields: map[string]any{"traceId": "trace-1"},
+	}
+	result, err := client.CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	if text := result.Content[0].(mcp.TextContent).Text; text != "progress-1" {
+		t.Errorf("Expected the handler to see progress token progress-1, got %s", text)
+	}
+	if traceID := result.Meta["traceId"]; traceID != "trace-1" {
+		t.Errorf("Expected result _meta traceId trace-1, got %v", traceID)
+	}
+}
+
+func TestInProcessMCPClient_Sampling(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(
+		mcp.NewTool("ask-llm"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			samplingRequest := mcp.CreateMessageRequest{}
+			samplingRequest.Params.Messages = []mcp.SamplingMessage{
+				{Role: mcp.RoleUser, Content: mcp.NewTextContent("Capital of France?")},
+			}
+			samplingRequest.Params.SystemPrompt = "Answer in one word."
+			samplingRequest.Params.ModelPreferences = &mcp.ModelPreferences{
+				Hints: []mcp.ModelHint{{Name: "small-model"}},
+			}
+			samplingRequest.Params.MaxTokens = 10
+
+			result, err := server.ServerFromContext(ctx).RequestSampling(ctx, samplingRequest)
+			if err != nil {
+				return nil, err
+			}
+			return mcp.NewToolResultText(result.Model + ": " + result.Content.(mcp.TextContent).Text), nil
+		},
+	)
+
+	var received mcp.CreateMessageRequest
+	client, err := NewInProcessClient(mcpServer, WithSamplingHandler(
+		func(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			received = request
+			return &mcp.CreateMessageResult{
+				SamplingMessage: mcp.SamplingMessage{
+					Role:    mcp.RoleAssistant,
+					Content: mcp.NewTextContent("Paris"),
+				},
+				Model:      "small-model",
+				StopReason: "endTurn",
+			}, nil
+		},
+	))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "ask-llm"
+	result, err := client.CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	if text := result.Content[0].(mcp.TextContent).Text; text != "small-model: Paris" {
+		t.Errorf("Expected the sampled reply, got %q", text)
+	}
+	if received.Params.SystemPrompt != "Answer in one word." {
+		t.Errorf("Expected the system prompt to reach the handler, got %q", received.Params.SystemPrompt)
+	}
+	if prefs := received.Params.ModelPreferences; prefs == nil || len(prefs.Hints) != 1 || prefs.Hints[0].Name != "small-model" {
+		t.Errorf("Expected the model preferences to reach the handler, got %+v", prefs)
+	}
+	if len(received.Params.Messages) != 1 || received.Params.Messages[0].Role != mcp.RoleUser {
+		t.Errorf("Expected the messages to reach the handler, got %+v", received.Params.Messages)
+	}
+}
+
+func TestInProcessMCPClient_Progress(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(
+		mcp.NewTool("long-running"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			for i := 1; i <= 3; i++ {
+				if err := server.SendProgressNotification(ctx, float64(i), 3, fmt.Sprintf("step %d", i)); err != nil {
+					return nil, err
+				}
+			}
+			return mcp.NewToolResultText("done"), nil
+		},
+	)
+
+	client, err := NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	progress := make(chan mcp.JSONRPCNotification, 10)
+	client.OnNotification(func(notification mcp.JSONRPCNotification) {
+		if notification.Method == "notifications/progress" {
+			progress <- notification
+		}
+	})
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	// Without a progress token, no notifications are sent
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "long-running"
+	if _, err := client.CallTool(context.Background(), request); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	request.Params.Meta = &mcp.Meta{ProgressToken: "call-2"}
+	if _, err := client.CallTool(context.Background(), request); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		select {
+		case notification := <-progress:
+			params := notification.Params.AdditionalFields
+			if params["progressToken"] != "call-2" {
+				t.Errorf("Expected progress token call-2, got %v", params["progressToken"])
+			}
+			if params["progress"] != float64(i) || params["total"] != float64(3) {
+				t.Errorf("Expected progress %d of 3, got %v of %v", i, params["progress"], params["total"])
+			}
+			if params["message"] != fmt.Sprintf("step %d", i) {
+				t.Errorf("Expected message %q, got %v", fmt.Sprintf("step %d", i), params["message"])
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for progress notification %d", i)
+		}
+	}
+
+	select {
+	case notification := <-progress:
+		t.Errorf("Unexpected progress notification: %v", notification)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInProcessMCPClient_ServerRequest(t *testing.T) {
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(
+		mcp.NewTool("list-roots"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := server.ServerFromContext(ctx).SendRequestToClient(ctx, "roots/list", nil)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return mcp.NewToolResultText(string(result)), nil
+		},
+	)
+
+	client, err := NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list-roots"
+
+	// Without a handler the client answers with METHOD_NOT_FOUND
+	result, err := client.CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if !result.IsError {
+		t.Errorf("Expected an error result, got %v", result.Content)
+	}
+
+	client.OnRequest("roots/list", func(ctx context.Context, request transport.IncomingRequest) (any, error) {
+		return map[string]any{"roots": []map[string]any{{"uri": "file:///tmp"}}}, nil
+	})
+	result, err = client.CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; text != `{"roots":[{"uri":"file:///tmp"}]}` {
+		t.Errorf("Expected the client's roots, got %s", text)
+	}
+}
+
+func TestInProcessMCPClient_ExperimentalCapabilities(t *testing.T) {
+	var clientCapabilities mcp.ClientCapabilities
+	hooks := &server.Hooks{}
+	hooks.AddAfterInitialize(func(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult) {
+		clientCapabilities = message.Params.Capabilities
+	})
+
+	mcpServer := server.NewMCPServer("test-server", "1.0.0",
+		server.WithHooks(hooks),
+		server.WithExperimentalCapabilities(map[string]any{
+			"batching": map[string]any{"maxSize": 10},
+		}),
+	)
+
+	client, err := NewInProcessClient(mcpServer, WithClientCapabilities(mcp.ClientCapabilities{
+		Experimental: map[string]any{"streamingResults": true},
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}
+	result, err := client.Initialize(context.Background(), initRequest)
+	if err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	if !result.Capabilities.HasExperimental("batching") || !client.GetServerCapabilities().HasExperimental("batching") {
+		t.Errorf("Expected the server to advertise batching, got %v", result.Capabilities.Experimental)
+	}
+	if result.Capabilities.HasExperimental("streamingResults") {
+		t.Errorf("Expected the server not to echo the client's capabilities")
+	}
+	batching, _ := result.Capabilities.Experimental["batching"].(map[string]any)
+	if batching["maxSize"] != float64(10) {
+		t.Errorf("Expected batching maxSize 10, got %v", batching)
+	}
+
+	if !clientCapabilities.HasExperimental("streamingResults") {
+		t.Errorf("Expected the client to declare streamingResults, got %v", clientCapabilities.Experimental)
+	}
+	if clientCapabilities.HasExperimental("batching") {
+		t.Errorf("Expected the client not to declare batching")
+	}
+}
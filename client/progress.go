@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// ProgressFunc receives the progress notifications of a request. total is
+// zero if the server did not report one, and message is empty if it sent
+// none.
+type ProgressFunc func(progress, total float64, message string)
+
+// CallToolWithProgress invokes a tool like CallTool, asking the server for
+// progress notifications with a generated progress token and calling
+// onProgress for each of them until the call returns. A progress token the
+// request already carries is replaced. onProgress runs on the goroutine
+// delivering notifications, so it should not block.
+func (c *Client) CallToolWithProgress(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	onProgress ProgressFunc,
+) (*mcp.CallToolResult, error) {
+	token := fmt.Sprintf("progress-%d", c.progressID.Add(1))
+
+	c.progressMu.Lock()
+	if c.progressHandlers == nil {
+		c.progressHandlers = make(map[string]ProgressFunc)
+	}
+	c.progressHandlers[token] = onProgress
+	c.progressMu.Unlock()
+	defer func() {
+		c.progressMu.Lock()
+		delete(c.progressHandlers, token)
+		c.progressMu.Unlock()
+	}()
+
+	// Copy the meta so the caller's request is left unchanged
+	meta := mcp.Meta{}
+	if request.Params.Meta != nil {
+		meta = *request.Params.Meta
+	}
+	meta.ProgressToken = token
+	request.Params.Meta = &meta
+
+	return c.CallTool(ctx, request)
+}
+
+// handleProgress passes a notifications/progress notification to the
+// ProgressFunc registered for its token, if any.
+func (c *Client) handleProgress(notification mcp.JSONRPCNotification) {
+	params := notification.Params.AdditionalFields
+	token, ok := params["progressToken"].(string)
+	if !ok {
+		return
+	}
+
+	c.progressMu.RLock()
+	onProgress := c.progressHandlers[token]
+	c.progressMu.RUnlock()
+	if onProgress == nil {
+		return
+	}
+
+	progress, _ := params["progress"].(float64)
+	total, _ := params["total"].(float64)
+	message, _ := params["message"].(string)
+	onProgress(progress, total, message)
+}
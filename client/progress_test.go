@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zillow/mcp-go/mcp"
+	"github.com/zillow/mcp-go/server"
+)
+
+func TestClient_CallToolWithProgress(t *testing.T) {
+	type progressUpdate struct {
+		progress, total float64
+		message         string
+	}
+	updates := make(chan progressUpdate, 10)
+
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("long-running"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.Params.Meta.GetProgressToken() == nil {
+			return nil, errors.New("missing progress token")
+		}
+		if request.Params.Meta.AdditionalFields["trace"] != "abc" {
+			return nil, errors.New("missing caller meta")
+		}
+		// Wait for each notification to arrive, as they are delivered
+		// asynchronously and would otherwise race with the result.
+		for i, message := range []string{"started", ""} {
+			if err := server.SendProgressNotification(ctx, float64(i+1), 2, message); err != nil {
+				return nil, err
+			}
+			select {
+			case <-updates:
+			case <-time.After(time.Second):
+				return nil, errors.New("progress notification not received")
+			}
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	client, err := NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	var received []progressUpdate
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "long-running"
+	request.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{"trace": "abc"}}
+	result, err := client.CallToolWithProgress(context.Background(), request, func(progress, total float64, message string) {
+		update := progressUpdate{progress: progress, total: total, message: message}
+		received = append(received, update)
+		updates <- update
+	})
+	if err != nil {
+		t.Fatalf("CallToolWithProgress failed: %v", err)
+	}
+	if text, _ := result.FirstText(); text != "done" {
+		t.Errorf("Expected result done, got %q", text)
+	}
+
+	expected := []progressUpdate{{1, 2, "started"}, {2, 2, ""}}
+	if len(received) != len(expected) {
+		t.Fatalf("Expected %d progress updates, got %v", len(expected), received)
+	}
+	for i := range expected {
+		if received[i] != expected[i] {
+			t.Errorf("Progress update %d: expected %+v, got %+v", i, expected[i], received[i])
+		}
+	}
+
+	if request.Params.Meta.ProgressToken != nil {
+		t.Error("Expected the caller's request to be left unchanged")
+	}
+	client.progressMu.RLock()
+	registered := len(client.progressHandlers)
+	client.progressMu.RUnlock()
+	if registered != 0 {
+		t.Errorf("Expected the progress handler to be removed, %d left", registered)
+	}
+}
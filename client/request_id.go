@@ -0,0 +1,47 @@
+package client
+
+import "context"
+
+type requestIDFuncKey struct{}
+
+// WithRequestIDFunc returns a copy of ctx that makes the client call fn with
+// the method and JSON-RPC id of each request sent with it, before the request
+// is handed to the transport. This lets callers correlate their calls with
+// transport logs or server-side records:
+//
+//	var id int64
+//	ctx = client.WithRequestIDFunc(ctx, func(method string, requestID int64) {
+//	    id = requestID
+//	})
+//	result, err := c.CallTool(ctx, request)
+//	log.Printf("tools/call used request id %d", id)
+//
+// fn runs on the calling goroutine.
+func WithRequestIDFunc(ctx context.Context, fn func(method string, id int64)) context.Context {
+	return context.WithValue(ctx, requestIDFuncKey{}, fn)
+}
+
+// nextRequestID returns the id for the next request. Ids are positive and
+// increase by one per request, starting at 1. After math.MaxInt64 they wrap
+// around to 1 rather than going negative; by then the earlier requests with
+// the same ids have long completed.
+func (c *Client) nextRequestID() int64 {
+	for {
+		id := c.requestID.Add(1)
+		if id > 0 {
+			return id
+		}
+		// Several callers may observe the overflow; the first to get
+		// here resets the counter and the rest retry.
+		if current := c.requestID.Load(); current < 0 {
+			c.requestID.CompareAndSwap(current, 0)
+		}
+	}
+}
+
+// notifyRequestID calls the function set with WithRequestIDFunc, if any.
+func notifyRequestID(ctx context.Context, method string, id int64) {
+	if fn, ok := ctx.Value(requestIDFuncKey{}).(func(string, int64)); ok && fn != nil {
+		fn(method, id)
+	}
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/zillow/mcp-go/client/transport"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestClient_RequestIDs(t *testing.T) {
+	mock := transport.NewMock()
+	mock.On("initialize").Return(mcp.InitializeResult{
+		ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		ServerInfo:      mcp.Implementation{Name: "mock-server", Version: "1.0.0"},
+	})
+	mock.On("ping").Return(struct{}{})
+
+	client := NewClient(mock)
+	ctx := context.Background()
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	defer client.Close()
+
+	type call struct {
+		method string
+		id     int64
+	}
+	var calls []call
+	ctx = WithRequestIDFunc(ctx, func(method string, id int64) {
+		calls = append(calls, call{method, id})
+	})
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := client.Ping(ctx); err != nil {
+			t.Fatalf("Ping failed: %v", err)
+		}
+	}
+
+	want := []call{{"initialize", 1}, {"ping", 2}, {"ping", 3}}
+	if len(calls) != len(want) {
+		t.Fatalf("Expected %d reported ids, got %v", len(want), calls)
+	}
+	requests := mock.Requests()
+	for i, w := range want {
+		if calls[i] != w {
+			t.Errorf("Call %d: expected %v, got %v", i, w, calls[i])
+		}
+		if requests[i].ID != w.id {
+			t.Errorf("Call %d: reported id %d but sent %v", i, w.id, requests[i].ID)
+		}
+	}
+
+	// Ids wrap around to 1 instead of going negative
+	client.requestID.Store(math.MaxInt64 - 1)
+	calls = nil
+	for i := 0; i < 2; i++ {
+		if err := client.Ping(ctx); err != nil {
+			t.Fatalf("Ping failed: %v", err)
+		}
+	}
+	if len(calls) != 2 || calls[0].id != math.MaxInt64 || calls[1].id != 1 {
+		t.Errorf("Expected ids %d and 1, got %v", int64(math.MaxInt64), calls)
+	}
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/zillow/mcp-go/client/transport"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// SamplingHandlerFunc answers sampling/createMessage requests from the server.
+type SamplingHandlerFunc func(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
+
+// WithSamplingHandler registers the handler answering sampling requests from
+// the server, like OnSamplingRequest, and makes the client advertise the
+// sampling capability on Initialize. The handler receives the messages, model
+// preferences and system prompt chosen by the server and returns the model's
+// reply.
+func WithSamplingHandler(handler SamplingHandlerFunc) ClientOption {
+	return func(c *Client) {
+		c.OnSamplingRequest(handler)
+	}
+}
+
+// OnSamplingRequest registers the handler answering sampling requests from
+// the server. If it is registered before Initialize, the client advertises
+// the sampling capability. Server requests are only received over transports
+// implementing transport.BidirectionalInterface.
+func (c *Client) OnSamplingRequest(handler SamplingHandlerFunc) {
+	c.OnRequest(string(mcp.MethodSamplingCreateMessage), func(ctx context.Context, request transport.IncomingRequest) (any, error) {
+		var createMessage mcp.CreateMessageRequest
+		createMessage.Method = request.Method
+		if err := json.Unmarshal(request.Params, &createMessage.Params); err != nil {
+			return nil, &transport.RequestError{
+				Code:    mcp.INVALID_PARAMS,
+				Message: fmt.Sprintf("invalid sampling request: %v", err),
+			}
+		}
+		return handler(ctx, createMessage)
+	})
+}
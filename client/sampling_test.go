@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/zillow/mcp-go/client/transport"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestClient_OnSamplingRequest(t *testing.T) {
+	mock := transport.NewMock()
+	mock.On("initialize").Return(mcp.InitializeResult{
+		ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		ServerInfo:      mcp.Implementation{Name: "mock-server", Version: "1.0.0"},
+	})
+
+	client := NewClient(mock)
+	ctx := context.Background()
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	defer client.Close()
+
+	var received mcp.CreateMessageRequest
+	client.OnSamplingRequest(func(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		received = request
+		return &mcp.CreateMessageResult{
+			SamplingMessage: mcp.SamplingMessage{
+				Role:    mcp.RoleAssistant,
+				Content: mcp.NewTextContent("Paris"),
+			},
+			Model:      "test-model",
+			StopReason: "endTurn",
+		}, nil
+	})
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	var initParams struct {
+		Capabilities mcp.ClientCapabilities `json:"capabilities"`
+	}
+	data, _ := json.Marshal(mock.Requests()[0].Params)
+	if err := json.Unmarshal(data, &initParams); err != nil {
+		t.Fatalf("Failed to decode initialize params: %v", err)
+	}
+	if initParams.Capabilities.Sampling == nil {
+		t.Error("Expected the sampling capability to be advertised")
+	}
+
+	response, err := mock.InjectRequest(ctx, 7, string(mcp.MethodSamplingCreateMessage), map[string]any{
+		"messages": []any{
+			map[string]any{"role": "user", "content": map[string]any{"type": "text", "text": "Capital of France?"}},
+		},
+		"maxTokens": 16,
+	})
+	if err != nil {
+		t.Fatalf("InjectRequest failed: %v", err)
+	}
+
+	if received.Params.MaxTokens != 16 || len(received.Params.Messages) != 1 {
+		t.Errorf("Unexpected sampling request: %+v", received.Params)
+	}
+
+	var result struct {
+		ID     int64 `json:"id"`
+		Result struct {
+			Role    string `json:"role"`
+			Model   string `json:"model"`
+			Content struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(response, &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.ID != 7 {
+		t.Errorf("Expected response id 7, got %d", result.ID)
+	}
+	if result.Result.Model != "test-model" || result.Result.Content.Text != "Paris" || result.Result.Role != "assistant" {
+		t.Errorf("Unexpected sampling result: %s", response)
+	}
+
+	response, err = mock.InjectRequest(ctx, 8, "roots/list", nil)
+	if err != nil {
+		t.Fatalf("InjectRequest failed: %v", err)
+	}
+	var errResponse mcp.JSONRPCError
+	if err := json.Unmarshal(response, &errResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if errResponse.Error.Code != mcp.METHOD_NOT_FOUND {
+		t.Errorf("Expected METHOD_NOT_FOUND for unhandled request, got %s", response)
+	}
+}
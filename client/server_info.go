@@ -0,0 +1,176 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// ErrServerInfoMismatch is returned by Initialize when the server reports an
+// implementation that does not match the one configured with
+// WithExpectedServerInfo.
+var ErrServerInfoMismatch = errors.New("server info mismatch")
+
+// WithExpectedServerInfo pins the identity of the server the client expects to
+// talk to. After the initialize handshake, Initialize returns an error wrapping
+// ErrServerInfoMismatch if the server's reported Implementation does not match.
+//
+// The name is matched using path.Match glob syntax, so "weather-*" accepts
+// "weather-eu" and "weather-us". The version may be:
+//   - empty or "*" to accept any version
+//   - a glob such as "1.2.*"
+//   - a comma separated list of constraints such as ">=1.2.0, <2.0.0",
+//     where each constraint uses one of =, >, >=, < or <=
+func WithExpectedServerInfo(name, version string) ClientOption {
+	return func(c *Client) {
+		c.expectedServerInfo = &mcp.Implementation{
+			Name:    name,
+			Version: version,
+		}
+	}
+}
+
+// verifyServerInfo checks the reported server implementation against the
+// expected one. A nil expected implementation accepts any server.
+func verifyServerInfo(expected *mcp.Implementation, actual mcp.Implementation) error {
+	if expected == nil {
+		return nil
+	}
+
+	if expected.Name != "" {
+		ok, err := path.Match(expected.Name, actual.Name)
+		if err != nil {
+			return fmt.Errorf("invalid expected server name %q: %w", expected.Name, err)
+		}
+		if !ok {
+			return fmt.Errorf(
+				"%w: expected server name %q, got %q",
+				ErrServerInfoMismatch,
+				expected.Name,
+				actual.Name,
+			)
+		}
+	}
+
+	ok, err := matchVersion(expected.Version, actual.Version)
+	if err != nil {
+		return fmt.Errorf("invalid expected server version %q: %w", expected.Version, err)
+	}
+	if !ok {
+		return fmt.Errorf(
+			"%w: expected server version %q, got %q",
+			ErrServerInfoMismatch,
+			expected.Version,
+			actual.Version,
+		)
+	}
+
+	return nil
+}
+
+// matchVersion reports whether version satisfies the given pattern, which is
+// either a glob or a comma separated list of comparison constraints.
+func matchVersion(pattern, version string) (bool, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || pattern == "*" {
+		return true, nil
+	}
+
+	if !strings.ContainsAny(pattern, "<>=") {
+		return path.Match(pattern, version)
+	}
+
+	for _, constraint := range strings.Split(pattern, ",") {
+		constraint = strings.TrimSpace(constraint)
+		var op string
+		for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+			if strings.HasPrefix(constraint, candidate) {
+				op = candidate
+				break
+			}
+		}
+		if op == "" {
+			return false, fmt.Errorf("missing operator in constraint %q", constraint)
+		}
+
+		cmp, err := compareVersions(version, strings.TrimSpace(constraint[len(op):]))
+		if err != nil {
+			return false, err
+		}
+
+		var ok bool
+		switch op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		case "=":
+			ok = cmp == 0
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// compareVersions compares two dotted numeric versions such as "1.2.3",
+// ignoring a leading "v" and any pre-release or build suffix. Missing
+// components are treated as zero. It returns -1, 0 or 1.
+func compareVersions(a, b string) (int, error) {
+	as, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	bs, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if x < y {
+			return -1, nil
+		}
+		if x > y {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func parseVersion(v string) ([]int, error) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, fmt.Errorf("empty version")
+	}
+
+	parts := strings.Split(v, ".")
+	nums := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", v, err)
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/zillow/mcp-go/client/transport"
+	"github.com/zillow/mcp-go/mcp"
+	"github.com/zillow/mcp-go/server"
+)
+
+func TestClient_WithExpectedServerInfo(t *testing.T) {
+	mcpServer := server.NewMCPServer("weather-server", "1.4.2")
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}
+
+	tests := []struct {
+		name        string
+		expectName  string
+		expectVer   string
+		expectError bool
+	}{
+		{name: "exact match", expectName: "weather-server", expectVer: "1.4.2"},
+		{name: "any version", expectName: "weather-server", expectVer: ""},
+		{name: "name wildcard", expectName: "weather-*", expectVer: "*"},
+		{name: "version glob", expectName: "weather-server", expectVer: "1.4.*"},
+		{name: "version range", expectName: "weather-server", expectVer: ">=1.2.0, <2.0.0"},
+		{name: "name mismatch", expectName: "billing-server", expectVer: "1.4.2", expectError: true},
+		{name: "version glob mismatch", expectName: "weather-server", expectVer: "2.*", expectError: true},
+		{name: "version range mismatch", expectName: "weather-server", expectVer: ">1.4.2", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(
+				transport.NewInProcessTransport(mcpServer),
+				WithExpectedServerInfo(tt.expectName, tt.expectVer),
+			)
+			defer client.Close()
+
+			if err := client.Start(context.Background()); err != nil {
+				t.Fatalf("Failed to start client: %v", err)
+			}
+
+			_, err := client.Initialize(context.Background(), initRequest)
+			if tt.expectError {
+				if !errors.Is(err, ErrServerInfoMismatch) {
+					t.Fatalf("Expected ErrServerInfoMismatch, got %v", err)
+				}
+				// The client must not be usable after a failed identity check
+				if err := client.Ping(context.Background()); err == nil {
+					t.Error("Expected error when making request after failed initialization")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to initialize: %v", err)
+			}
+		})
+	}
+}
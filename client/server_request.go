@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zillow/mcp-go/client/transport"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// OnRequest registers the handler answering requests with the given method
+// sent by the server, e.g. roots/list or elicitation requests, replacing any
+// handler registered for it before. Requests without a registered handler
+// are answered with a METHOD_NOT_FOUND error, except for ping, which is
+// answered by default, see WithPingHandler.
+//
+// Registering a handler for sampling/createMessage or roots/list before
+// Initialize makes the client advertise the matching capability. Server
+// requests are only received over transports implementing
+// transport.BidirectionalInterface.
+func (c *Client) OnRequest(method string, handler transport.RequestHandler) {
+	c.requestMu.Lock()
+	defer c.requestMu.Unlock()
+	c.requestHandlers[method] = handler
+}
+
+// handleServerRequest dispatches a request sent by the server to the
+// handler registered for its method.
+func (c *Client) handleServerRequest(ctx context.Context, request transport.IncomingRequest) (any, error) {
+	c.requestMu.RLock()
+	handler := c.requestHandlers[request.Method]
+	c.requestMu.RUnlock()
+
+	if handler == nil {
+		return nil, &transport.RequestError{
+			Code:    mcp.METHOD_NOT_FOUND,
+			Message: fmt.Sprintf("client does not handle %s requests", request.Method),
+		}
+	}
+	return handler(ctx, request)
+}
+
+// defaultRequestHandlers returns the handlers a new client answers server
+// requests with.
+func defaultRequestHandlers() map[string]transport.RequestHandler {
+	return map[string]transport.RequestHandler{
+		string(mcp.MethodPing): handlePing,
+	}
+}
+
+// handlePing answers ping requests from the server with an empty result.
+func handlePing(ctx context.Context, request transport.IncomingRequest) (any, error) {
+	return struct{}{}, nil
+}
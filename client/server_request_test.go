@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/zillow/mcp-go/client/transport"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestClient_OnRequest(t *testing.T) {
+	mock := transport.NewMock()
+	mock.On("initialize").Return(mcp.InitializeResult{
+		ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		ServerInfo:      mcp.Implementation{Name: "mock-server", Version: "1.0.0"},
+	})
+
+	client := NewClient(mock)
+	ctx := context.Background()
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	defer client.Close()
+
+	client.OnRequest(string(mcp.MethodRootsList), func(ctx context.Context, request transport.IncomingRequest) (any, error) {
+		return mcp.ListRootsResult{Roots: []mcp.Root{{URI: "file:///workspace", Name: "workspace"}}}, nil
+	})
+	client.OnRequest("elicitation/create", func(ctx context.Context, request transport.IncomingRequest) (any, error) {
+		return nil, &transport.RequestError{Code: mcp.INVALID_REQUEST, Message: "declined"}
+	})
+
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(ctx, initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	var initParams struct {
+		Capabilities mcp.ClientCapabilities `json:"capabilities"`
+	}
+	data, _ := json.Marshal(mock.Requests()[0].Params)
+	if err := json.Unmarshal(data, &initParams); err != nil {
+		t.Fatalf("Failed to decode initialize params: %v", err)
+	}
+	if initParams.Capabilities.Roots == nil {
+		t.Error("Expected the roots capability to be advertised")
+	}
+	if initParams.Capabilities.Sampling != nil {
+		t.Error("Expected the sampling capability not to be advertised")
+	}
+
+	response, err := mock.InjectRequest(ctx, 1, string(mcp.MethodRootsList), nil)
+	if err != nil {
+		t.Fatalf("InjectRequest failed: %v", err)
+	}
+	var rootsResponse struct {
+		Result mcp.ListRootsResult `json:"result"`
+	}
+	if err := json.Unmarshal(response, &rootsResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(rootsResponse.Result.Roots) != 1 || rootsResponse.Result.Roots[0].URI != "file:///workspace" {
+		t.Errorf("Unexpected roots response: %s", response)
+	}
+
+	response, err = mock.InjectRequest(ctx, 2, "elicitation/create", map[string]any{"message": "Name?"})
+	if err != nil {
+		t.Fatalf("InjectRequest failed: %v", err)
+	}
+	var errResponse mcp.JSONRPCError
+	if err := json.Unmarshal(response, &errResponse); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if errResponse.Error.Code != mcp.INVALID_REQUEST || errResponse.Error.Message != "declined" {
+		t.Errorf("Expected the handler's error to be sent back, got %s", response)
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []ClientOption
+		expected string
+	}{
+		{
+			name:     "Answered with an empty result by default",
+			expected: `{"jsonrpc":"2.0","id":3,"result":{}}`,
+		},
+		{
+			name: "Answered by WithPingHandler",
+			options: []ClientOption{WithPingHandler(func(ctx context.Context, request transport.IncomingRequest) (any, error) {
+				return nil, &transport.RequestError{Code: mcp.INTERNAL_ERROR, Message: "busy"}
+			})},
+			expected: `{"jsonrpc":"2.0","id":3,"error":{"code":-32603,"message":"busy"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := transport.NewMock()
+			client := NewClient(mock, tt.options...)
+			if err := client.Start(context.Background()); err != nil {
+				t.Fatalf("Failed to start client: %v", err)
+			}
+			defer client.Close()
+
+			response, err := mock.InjectRequest(context.Background(), 3, string(mcp.MethodPing), nil)
+			if err != nil {
+				t.Fatalf("InjectRequest failed: %v", err)
+			}
+			if string(response) != tt.expected {
+				t.Errorf("Expected response %s, got %s", tt.expected, response)
+			}
+		})
+	}
+}
@@ -250,4 +250,51 @@ func TestSSEMCPClient(t *testing.T) {
 			t.Errorf("Expected 1 content item, got %d", len(result.Content))
 		}
 	})
+
+	t.Run("Answers server requests", func(t *testing.T) {
+		mcpServer.AddTool(mcp.NewTool("list-roots"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := mcpServer.SendRequestToClient(ctx, "roots/list", nil)
+			if err != nil {
+				return nil, err
+			}
+			return mcp.NewToolResultText(string(result)), nil
+		})
+
+		client, err := NewSSEMCPClient(testServer.URL + "/sse")
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		defer client.Close()
+
+		client.OnRequest("roots/list", func(ctx context.Context, request transport.IncomingRequest) (any, error) {
+			return map[string]any{"roots": []map[string]any{{"uri": "file:///tmp"}}}, nil
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := client.Start(ctx); err != nil {
+			t.Fatalf("Failed to start client: %v", err)
+		}
+
+		initRequest := mcp.InitializeRequest{}
+		initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+		initRequest.Params.ClientInfo = mcp.Implementation{
+			Name:    "test-client",
+			Version: "1.0.0",
+		}
+		if _, err := client.Initialize(ctx, initRequest); err != nil {
+			t.Fatalf("Failed to initialize: %v", err)
+		}
+
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "list-roots"
+		result, err := client.CallTool(ctx, request)
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		if text := result.Content[0].(mcp.TextContent).Text; text != `{"roots":[{"uri":"file:///tmp"}]}` {
+			t.Errorf("Expected the client's roots, got %s", text)
+		}
+	})
 }
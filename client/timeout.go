@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+type requestTimeoutKey struct{}
+
+// WithDefaultTimeout bounds every request whose context has no deadline to
+// d, so that a hung server cannot block a call forever when the caller did
+// not set a deadline. Contexts that already have a deadline are left
+// unchanged, and WithRequestTimeout overrides the default for single calls.
+// A timeout of zero or less disables the default.
+func WithDefaultTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.defaultTimeout = d
+	}
+}
+
+// WithRequestTimeout returns a copy of ctx that makes the client bound
+// requests sent with it to d, instead of the timeout set with
+// WithDefaultTimeout. Like context.WithTimeout, it never extends a deadline
+// ctx already has. A timeout of zero or less disables the default timeout
+// for requests sent with the returned context.
+func WithRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, d)
+}
+
+// requestContext derives the context a request is sent with from the
+// caller's ctx, applying the timeout set with WithRequestTimeout or
+// WithDefaultTimeout.
+func (c *Client) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout, ok := ctx.Value(requestTimeoutKey{}).(time.Duration)
+	if !ok {
+		if _, hasDeadline := ctx.Deadline(); hasDeadline {
+			return ctx, func() {}
+		}
+		timeout = c.defaultTimeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
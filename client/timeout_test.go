@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zillow/mcp-go/client/transport"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// hangingTransport never answers requests, but returns once their context is
+// done, recording the deadline they were sent with.
+type hangingTransport struct {
+	deadlines chan time.Time
+}
+
+func (t *hangingTransport) Start(ctx context.Context) error { return nil }
+
+func (t *hangingTransport) SendRequest(ctx context.Context, request transport.JSONRPCRequest) (*transport.JSONRPCResponse, error) {
+	deadline, _ := ctx.Deadline()
+	t.deadlines <- deadline
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (t *hangingTransport) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	return nil
+}
+
+func (t *hangingTransport) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+}
+
+func (t *hangingTransport) Close() error { return nil }
+
+func TestClient_DefaultTimeout(t *testing.T) {
+	hanging := &hangingTransport{deadlines: make(chan time.Time, 1)}
+	client := NewClient(hanging, WithDefaultTimeout(50*time.Millisecond))
+	client.initialized.Store(true)
+
+	ping := func(ctx context.Context) (time.Time, error) {
+		err := client.Ping(ctx)
+		return <-hanging.deadlines, err
+	}
+
+	t.Run("Applies the default without a deadline", func(t *testing.T) {
+		start := time.Now()
+		deadline, err := ping(context.Background())
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if deadline.Sub(start) > time.Second {
+			t.Errorf("Expected a deadline about 50ms away, got %v", deadline.Sub(start))
+		}
+	})
+
+	t.Run("Keeps the caller's deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		expected, _ := ctx.Deadline()
+		deadline, err := ping(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if !deadline.Equal(expected) {
+			t.Errorf("Expected the caller's deadline %v, got %v", expected, deadline)
+		}
+	})
+
+	t.Run("Per-call override", func(t *testing.T) {
+		start := time.Now()
+		deadline, err := ping(WithRequestTimeout(context.Background(), 10*time.Millisecond))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if deadline.Sub(start) > 40*time.Millisecond {
+			t.Errorf("Expected a deadline about 10ms away, got %v", deadline.Sub(start))
+		}
+	})
+
+	t.Run("Override never extends the caller's deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		expected, _ := ctx.Deadline()
+		deadline, err := ping(WithRequestTimeout(ctx, time.Hour))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if !deadline.Equal(expected) {
+			t.Errorf("Expected the caller's deadline %v, got %v", expected, deadline)
+		}
+	})
+
+	t.Run("Override disables the default", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+		go func() {
+			errs <- client.Ping(WithRequestTimeout(ctx, 0))
+		}()
+		if deadline := <-hanging.deadlines; !deadline.IsZero() {
+			t.Errorf("Expected no deadline, got %v", deadline)
+		}
+		cancel()
+		if err := <-errs; !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
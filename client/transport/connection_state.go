@@ -0,0 +1,55 @@
+package transport
+
+import "errors"
+
+// ErrConnectionLost is reported when a transport's connection to the server
+// ends without Close having been called.
+var ErrConnectionLost = errors.New("connection lost")
+
+// ConnectionState is the state of a transport's connection to the server.
+type ConnectionState int
+
+const (
+	// ConnectionDisconnected means there is no connection to the server,
+	// either because it was never established, it was closed, or it was
+	// lost.
+	ConnectionDisconnected ConnectionState = iota
+	// ConnectionConnected means messages can be exchanged with the server.
+	ConnectionConnected
+	// ConnectionReconnecting means the connection was lost and the
+	// transport is trying to re-establish it.
+	ConnectionReconnecting
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionDisconnected:
+		return "disconnected"
+	case ConnectionConnected:
+		return "connected"
+	case ConnectionReconnecting:
+		return "reconnecting"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionStateHandler is called when a transport's connection state
+// changes. err describes why the connection was lost when moving to
+// ConnectionReconnecting or ConnectionDisconnected, and is nil otherwise.
+type ConnectionStateHandler func(state ConnectionState, err error)
+
+// ConnectionStateNotifier is implemented by transports that report when
+// their connection to the server is lost or re-established.
+type ConnectionStateNotifier interface {
+	Interface
+
+	// SetConnectionStateHandler sets the handler called when the connection
+	// state changes after Start. It is not called for Close.
+	SetConnectionStateHandler(handler ConnectionStateHandler)
+}
+
+var (
+	_ ConnectionStateNotifier = (*Stdio)(nil)
+	_ ConnectionStateNotifier = (*SSE)(nil)
+)
@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// The transport and mcp packages describe the same JSON-RPC 2.0 messages;
+// both encode to identical JSON, which is the canonical form on the wire.
+// The transport types are what an Interface exchanges with the client: they
+// use int64 ids, as the client assigns all request ids, and keep results as
+// raw JSON for the client to decode. The mcp types are what the server
+// handles and allow any id the peer chose. The converters below translate
+// between the two for custom transports.
+
+// FromMCPRequest converts an mcp.JSONRPCRequest into the request type passed
+// to Interface.SendRequest. It fails if the request id is not an integer.
+func FromMCPRequest(request mcp.JSONRPCRequest) (JSONRPCRequest, error) {
+	id, err := requestIDToInt64(request.ID)
+	if err != nil {
+		return JSONRPCRequest{}, err
+	}
+
+	jsonrpc := request.JSONRPC
+	if jsonrpc == "" {
+		jsonrpc = mcp.JSONRPC_VERSION
+	}
+	return JSONRPCRequest{
+		JSONRPC: jsonrpc,
+		ID:      id,
+		Method:  request.Method,
+		Params:  request.Params,
+	}, nil
+}
+
+// ToMCPNotification decodes a message received from the server into an
+// mcp.JSONRPCNotification, for passing to the handler set with
+// SetNotificationHandler. It fails if the message is not a notification,
+// i.e. if it has an id or no method.
+func ToMCPNotification(message json.RawMessage) (mcp.JSONRPCNotification, error) {
+	var base struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if err := json.Unmarshal(message, &base); err != nil {
+		return mcp.JSONRPCNotification{}, fmt.Errorf("failed to unmarshal notification: %w", err)
+	}
+	if len(base.ID) != 0 && string(base.ID) != "null" {
+		return mcp.JSONRPCNotification{}, fmt.Errorf("message with id %s is not a notification", base.ID)
+	}
+	if base.Method == "" {
+		return mcp.JSONRPCNotification{}, fmt.Errorf("notification has no method")
+	}
+
+	var notification mcp.JSONRPCNotification
+	if err := json.Unmarshal(message, &notification); err != nil {
+		return mcp.JSONRPCNotification{}, fmt.Errorf("failed to unmarshal notification: %w", err)
+	}
+	return notification, nil
+}
+
+// requestIDToInt64 converts an id held in an mcp.RequestId, including
+// numbers decoded from JSON as float64 or json.Number, to an int64.
+func requestIDToInt64(id mcp.RequestId) (int64, error) {
+	switch id := id.(type) {
+	case int64:
+		return id, nil
+	case int:
+		return int64(id), nil
+	case int32:
+		return int64(id), nil
+	case float64:
+		if id == math.Trunc(id) && id >= math.MinInt64 && id < math.MaxInt64 {
+			return int64(id), nil
+		}
+	case json.Number:
+		if n, err := id.Int64(); err == nil {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("request id %v (%T) is not an integer", id, id)
+}
@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestFromMCPRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      mcp.RequestId
+		wantID  int64
+		wantErr bool
+	}{
+		{name: "int64 id", id: int64(7), wantID: 7},
+		{name: "int id", id: 7, wantID: 7},
+		{name: "id decoded from JSON", id: float64(7), wantID: 7},
+		{name: "json.Number id", id: json.Number("7"), wantID: 7},
+		{name: "fractional id", id: 7.5, wantErr: true},
+		{name: "string id", id: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := mcp.JSONRPCRequest{ID: tt.id, Params: map[string]any{"name": "echo"}}
+			request.Method = string(mcp.MethodToolsCall)
+
+			converted, err := FromMCPRequest(request)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", converted)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if converted.ID != tt.wantID || converted.Method != "tools/call" || converted.JSONRPC != mcp.JSONRPC_VERSION {
+				t.Errorf("Unexpected request: %+v", converted)
+			}
+
+			// Both forms are identical on the wire.
+			request.JSONRPC = mcp.JSONRPC_VERSION
+			request.ID = tt.wantID
+			want, _ := json.Marshal(request)
+			got, _ := json.Marshal(converted)
+			var wantFields, gotFields map[string]any
+			_ = json.Unmarshal(want, &wantFields)
+			_ = json.Unmarshal(got, &gotFields)
+			if !reflect.DeepEqual(gotFields, wantFields) {
+				t.Errorf("Expected %s on the wire, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestToMCPNotification(t *testing.T) {
+	notification, err := ToMCPNotification(json.RawMessage(
+		`{"jsonrpc":"2.0","method":"notifications/progress","params":{"progress":1,"_meta":{"k":"v"}}}`,
+	))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if notification.Method != "notifications/progress" {
+		t.Errorf("Expected method notifications/progress, got %q", notification.Method)
+	}
+	if notification.Params.AdditionalFields["progress"] != float64(1) {
+		t.Errorf("Expected progress 1, got %v", notification.Params.AdditionalFields)
+	}
+	if notification.Params.Meta["k"] != "v" {
+		t.Errorf("Expected meta to be kept, got %v", notification.Params.Meta)
+	}
+
+	for _, message := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":1,"result":{}}`,
+		`{"jsonrpc":"2.0"}`,
+		`{not json`,
+	} {
+		if _, err := ToMCPNotification(json.RawMessage(message)); err == nil {
+			t.Errorf("Expected an error for %s", message)
+		}
+	}
+}
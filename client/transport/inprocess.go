@@ -4,17 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
 
+	"github.com/google/uuid"
 	"github.com/zillow/mcp-go/mcp"
 	"github.com/zillow/mcp-go/server"
 )
 
 type InProcessTransport struct {
-	server *server.MCPServer
+	server  *server.MCPServer
+	session *inProcessSession
 
 	onNotification func(mcp.JSONRPCNotification)
 	notifyMu       sync.RWMutex
+	onRequest      RequestHandler
+	closed         atomic.Bool
 }
 
 func NewInProcessTransport(server *server.MCPServer) *InProcessTransport {
@@ -23,18 +29,35 @@ func NewInProcessTransport(server *server.MCPServer) *InProcessTransport {
 	}
 }
 
+// Start registers a session with the server, through which the server sends
+// notifications and requests, e.g. sampling requests, to the client.
 func (c *InProcessTransport) Start(ctx context.Context) error {
+	session := &inProcessSession{
+		transport:     c,
+		sessionID:     uuid.New().String(),
+		notifications: make(chan mcp.JSONRPCNotification, 100),
+		done:          make(chan struct{}),
+	}
+	if err := c.server.RegisterSession(ctx, session); err != nil {
+		return fmt.Errorf("failed to register session: %w", err)
+	}
+	c.session = session
+
+	go session.forwardNotifications()
 	return nil
 }
 
 func (c *InProcessTransport) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+	if c.closed.Load() {
+		return nil, ErrTransportClosed
+	}
 	requestBytes, err := json.Marshal(request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 	requestBytes = append(requestBytes, '\n')
 
-	respMessage := c.server.HandleMessage(ctx, requestBytes)
+	respMessage := c.server.HandleMessage(c.withSession(ctx), requestBytes)
 	respByte, err := json.Marshal(respMessage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal response message: %w", err)
@@ -49,12 +72,15 @@ func (c *InProcessTransport) SendRequest(ctx context.Context, request JSONRPCReq
 }
 
 func (c *InProcessTransport) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	if c.closed.Load() {
+		return ErrTransportClosed
+	}
 	notificationBytes, err := json.Marshal(notification)
 	if err != nil {
 		return fmt.Errorf("failed to marshal notification: %w", err)
 	}
 	notificationBytes = append(notificationBytes, '\n')
-	c.server.HandleMessage(ctx, notificationBytes)
+	c.server.HandleMessage(c.withSession(ctx), notificationBytes)
 
 	return nil
 }
@@ -65,6 +91,139 @@ func (c *InProcessTransport) SetNotificationHandler(handler func(notification mc
 	c.onNotification = handler
 }
 
-func (*InProcessTransport) Close() error {
+// SetRequestHandler sets the handler for requests sent by the server.
+func (c *InProcessTransport) SetRequestHandler(handler RequestHandler) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+	c.onRequest = handler
+}
+
+// Close unregisters the session registered by Start. Requests and
+// notifications sent afterwards fail with ErrTransportClosed.
+func (c *InProcessTransport) Close() error {
+	c.closed.Store(true)
+	if c.session != nil {
+		c.server.UnregisterSession(context.Background(), c.session.SessionID())
+		c.session.close()
+	}
 	return nil
 }
+
+// withSession adds the transport's session, if started, to ctx.
+func (c *InProcessTransport) withSession(ctx context.Context) context.Context {
+	if c.session == nil {
+		return ctx
+	}
+	return c.server.WithContext(ctx, c.session)
+}
+
+// inProcessSession is the server session of an InProcessTransport.
+type inProcessSession struct {
+	transport     *InProcessTransport
+	sessionID     string
+	initialized   atomic.Bool
+	notifications chan mcp.JSONRPCNotification
+	requestID     atomic.Int64
+	done          chan struct{}
+	closeOnce     sync.Once
+}
+
+func (s *inProcessSession) SessionID() string {
+	return s.sessionID
+}
+
+func (s *inProcessSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func (s *inProcessSession) Initialize() {
+	s.initialized.Store(true)
+}
+
+func (s *inProcessSession) Initialized() bool {
+	return s.initialized.Load()
+}
+
+// SendRequest passes a request from the server to the client's request
+// handler as if it had been received over the wire, and returns the result
+// of the response.
+func (s *inProcessSession) SendRequest(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request params: %w", err)
+	}
+
+	s.transport.notifyMu.RLock()
+	handler := s.transport.onRequest
+	s.transport.notifyMu.RUnlock()
+
+	request := IncomingRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      json.RawMessage(strconv.FormatInt(s.requestID.Add(1), 10)),
+		Method:  method,
+		Params:  paramsBytes,
+	}
+
+	type answer struct {
+		response []byte
+		err      error
+	}
+	answered := make(chan answer, 1)
+	go func() {
+		response, err := answerRequest(ctx, handler, request)
+		answered <- answer{response, err}
+	}()
+
+	var responseBytes []byte
+	select {
+	case a := <-answered:
+		if a.err != nil {
+			return nil, fmt.Errorf("failed to marshal response: %w", a.err)
+		}
+		responseBytes = a.response
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.done:
+		return nil, ErrTransportClosed
+	}
+
+	var response JSONRPCResponse
+	if err := json.Unmarshal(responseBytes, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if response.Error != nil {
+		return nil, &mcp.JSONRPCErrorError{
+			Code:    response.Error.Code,
+			Message: response.Error.Message,
+			Data:    response.Error.Data,
+		}
+	}
+	return response.Result, nil
+}
+
+// forwardNotifications delivers the notifications the server sends to the
+// session to the transport's notification handler until the session is
+// closed.
+func (s *inProcessSession) forwardNotifications() {
+	for {
+		select {
+		case notification := <-s.notifications:
+			s.transport.notifyMu.RLock()
+			handler := s.transport.onNotification
+			s.transport.notifyMu.RUnlock()
+			if handler != nil {
+				handler(notification)
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *inProcessSession) close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+var _ server.SessionWithRequests = (*inProcessSession)(nil)
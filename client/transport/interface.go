@@ -3,10 +3,15 @@ package transport
 import (
 	"context"
 	"encoding/json"
+	"errors"
 
 	"github.com/zillow/mcp-go/mcp"
 )
 
+// ErrTransportClosed is returned for requests that were still waiting for a
+// response when the transport was closed.
+var ErrTransportClosed = errors.New("transport closed")
+
 // Interface for the transport layer.
 type Interface interface {
 	// Start the connection. Start should only be called once.
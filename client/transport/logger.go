@@ -0,0 +1,23 @@
+package transport
+
+// Logger receives diagnostics from the transports, such as malformed messages
+// or broken streams, that cannot be returned to a caller as an error.
+// Implementations must be safe for concurrent use.
+type Logger interface {
+	Debugf(format string, v ...any)
+	Errorf(format string, v ...any)
+}
+
+// noopLogger discards everything. It is the default for all transports.
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...any) {}
+func (noopLogger) Errorf(string, ...any) {}
+
+// orNoopLogger returns l, or a Logger discarding everything if l is nil.
+func orNoopLogger(l Logger) Logger {
+	if l == nil {
+		return noopLogger{}
+	}
+	return l
+}
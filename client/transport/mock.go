@@ -0,0 +1,243 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// Mock is a transport with programmable responses for unit testing client
+// code without a server. Responses are registered per method with On, and
+// notifications and requests from the "server" are delivered with
+// InjectNotification and InjectRequest.
+//
+//	mock := transport.NewMock()
+//	mock.On("tools/list").Return(mcp.ListToolsResult{Tools: tools})
+//	c := client.NewClient(mock)
+//
+// Requests for methods without a registered response fail with a
+// METHOD_NOT_FOUND JSON-RPC error.
+type Mock struct {
+	mu            sync.Mutex
+	calls         []*MockCall
+	requests      []JSONRPCRequest
+	notifications []mcp.JSONRPCNotification
+
+	onNotification func(mcp.JSONRPCNotification)
+	onRequest      RequestHandler
+	notifyMu       sync.RWMutex
+}
+
+// MockCall is a programmed response of a Mock, created with Mock.On.
+type MockCall struct {
+	method  string
+	matches func(params map[string]any) bool
+	result  json.RawMessage
+	errCode int
+	errMsg  string
+	errData json.RawMessage
+	isError bool
+	err     error
+}
+
+// NewMock creates a Mock transport without any programmed responses.
+func NewMock() *Mock {
+	return &Mock{}
+}
+
+// On registers a response for requests with the given method. When several
+// registered calls match a request, the one registered first is used.
+func (m *Mock) On(method string) *MockCall {
+	call := &MockCall{method: method}
+	m.mu.Lock()
+	m.calls = append(m.calls, call)
+	m.mu.Unlock()
+	return call
+}
+
+// Matching restricts the call to requests whose params, decoded from JSON,
+// match returns true for, e.g. to respond differently depending on the tool
+// name.
+func (c *MockCall) Matching(match func(params map[string]any) bool) *MockCall {
+	c.matches = match
+	return c
+}
+
+// Return sets the result sent back for matching requests. It panics if the
+// result cannot be marshaled to JSON.
+func (c *MockCall) Return(result any) *MockCall {
+	data, err := json.Marshal(result)
+	if err != nil {
+		panic(fmt.Sprintf("mock: failed to marshal result for %s: %v", c.method, err))
+	}
+	c.result = data
+	return c
+}
+
+// ReturnError makes matching requests fail with a JSON-RPC error response.
+func (c *MockCall) ReturnError(code int, message string) *MockCall {
+	c.errCode, c.errMsg, c.isError = code, message, true
+	return c
+}
+
+// ReturnErrorWithData makes matching requests fail with a JSON-RPC error
+// response carrying data as its structured details. It panics if data cannot
+// be marshaled to JSON.
+func (c *MockCall) ReturnErrorWithData(code int, message string, data any) *MockCall {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		panic(fmt.Sprintf("mock: failed to marshal error data for %s: %v", c.method, err))
+	}
+	c.errCode, c.errMsg, c.errData, c.isError = code, message, raw, true
+	return c
+}
+
+// ReturnTransportError makes SendRequest itself fail with err for matching
+// requests, as when the connection to the server is broken.
+func (c *MockCall) ReturnTransportError(err error) *MockCall {
+	c.err = err
+	return c
+}
+
+// InjectNotification delivers a notification to the handler set with
+// SetNotificationHandler, as if the server had sent it.
+func (m *Mock) InjectNotification(notification mcp.JSONRPCNotification) {
+	m.notifyMu.RLock()
+	defer m.notifyMu.RUnlock()
+	if m.onNotification != nil {
+		m.onNotification(notification)
+	}
+}
+
+// InjectRequest delivers a request to the handler set with SetRequestHandler,
+// as if the server had sent it, and returns the JSON-RPC response the client
+// sent back.
+func (m *Mock) InjectRequest(ctx context.Context, id int64, method string, params any) (json.RawMessage, error) {
+	rawID, err := json.Marshal(id)
+	if err != nil {
+		return nil, err
+	}
+	rawParams, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("mock: failed to marshal params for %s: %w", method, err)
+	}
+
+	m.notifyMu.RLock()
+	handler := m.onRequest
+	m.notifyMu.RUnlock()
+
+	return answerRequest(ctx, handler, IncomingRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      rawID,
+		Method:  method,
+		Params:  rawParams,
+	})
+}
+
+// Requests returns the requests sent through the transport so far.
+func (m *Mock) Requests() []JSONRPCRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]JSONRPCRequest(nil), m.requests...)
+}
+
+// Notifications returns the notifications sent through the transport so far.
+func (m *Mock) Notifications() []mcp.JSONRPCNotification {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mcp.JSONRPCNotification(nil), m.notifications...)
+}
+
+func (m *Mock) Start(ctx context.Context) error {
+	return nil
+}
+
+func (m *Mock) SendRequest(ctx context.Context, request JSONRPCRequest) (*JSONRPCResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.requests = append(m.requests, request)
+	params := mockParams(request.Params)
+	var call *MockCall
+	for _, c := range m.calls {
+		if c.method == request.Method && (c.matches == nil || c.matches(params)) {
+			call = c
+			break
+		}
+	}
+	m.mu.Unlock()
+
+	id := request.ID
+	response := &JSONRPCResponse{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      &id,
+	}
+
+	switch {
+	case call == nil:
+		response.Error = newResponseError(mcp.METHOD_NOT_FOUND, fmt.Sprintf("mock: no response for method %s", request.Method))
+	case call.err != nil:
+		return nil, call.err
+	case call.isError:
+		response.Error = newResponseError(call.errCode, call.errMsg)
+		response.Error.Data = call.errData
+	case call.result == nil:
+		response.Result = json.RawMessage("{}")
+	default:
+		response.Result = call.result
+	}
+	return response, nil
+}
+
+func (m *Mock) SendNotification(ctx context.Context, notification mcp.JSONRPCNotification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notifications = append(m.notifications, notification)
+	return nil
+}
+
+func (m *Mock) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+	m.onNotification = handler
+}
+
+func (m *Mock) SetRequestHandler(handler RequestHandler) {
+	m.notifyMu.Lock()
+	defer m.notifyMu.Unlock()
+	m.onRequest = handler
+}
+
+func (m *Mock) Close() error {
+	return nil
+}
+
+// mockParams returns the request params as a generic JSON object, or an
+// empty map if they are not an object.
+func mockParams(params any) map[string]any {
+	decoded := map[string]any{}
+	if data, err := json.Marshal(params); err == nil {
+		_ = json.Unmarshal(data, &decoded)
+	}
+	return decoded
+}
+
+// newResponseError builds the Error field of a JSONRPCResponse.
+func newResponseError(code int, message string) *struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+} {
+	return &struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}{Code: code, Message: message}
+}
+
+var _ BidirectionalInterface = (*Mock)(nil)
@@ -0,0 +1,115 @@
+package transport_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zillow/mcp-go/client"
+	"github.com/zillow/mcp-go/client/transport"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// newInitializedClient returns a client talking to mock, with the initialize
+// handshake already done.
+func newInitializedClient(mock *transport.Mock) (*client.Client, error) {
+	mock.On("initialize").Return(mcp.InitializeResult{
+		ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		ServerInfo:      mcp.Implementation{Name: "mock-server", Version: "1.0.0"},
+	})
+
+	c := client.NewClient(mock)
+	if err := c.Start(context.Background()); err != nil {
+		return nil, err
+	}
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	if _, err := c.Initialize(context.Background(), request); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func ExampleMock_On() {
+	mock := transport.NewMock()
+	mock.On("tools/call").
+		Matching(func(params map[string]any) bool {
+			return params["name"] == "weather"
+		}).
+		Return(mcp.NewToolResultText("sunny"))
+	mock.On("tools/call").ReturnError(mcp.INVALID_PARAMS, "unknown tool")
+
+	c, err := newInitializedClient(mock)
+	if err != nil {
+		panic(err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "weather"
+	result, err := c.CallTool(context.Background(), request)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(result.Content[0].(mcp.TextContent).Text)
+
+	request.Params.Name = "stocks"
+	_, err = c.CallTool(context.Background(), request)
+	fmt.Println(err)
+	// Output:
+	// sunny
+	// unknown tool
+}
+
+func ExampleMock_InjectNotification() {
+	mock := transport.NewMock()
+	c, err := newInitializedClient(mock)
+	if err != nil {
+		panic(err)
+	}
+
+	c.OnNotification(func(notification mcp.JSONRPCNotification) {
+		fmt.Println("received", notification.Method)
+	})
+
+	mock.InjectNotification(mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: "notifications/tools/list_changed",
+		},
+	})
+	// Output:
+	// received notifications/tools/list_changed
+}
+
+func TestMock(t *testing.T) {
+	mock := transport.NewMock()
+	brokenPipe := errors.New("broken pipe")
+	mock.On("ping").ReturnTransportError(brokenPipe)
+
+	c, err := newInitializedClient(mock)
+	if err != nil {
+		t.Fatalf("Failed to initialize client: %v", err)
+	}
+
+	if err := c.Ping(context.Background()); !errors.Is(err, brokenPipe) {
+		t.Errorf("Expected transport error, got %v", err)
+	}
+
+	if _, err := c.ListPrompts(context.Background(), mcp.ListPromptsRequest{}); err == nil {
+		t.Errorf("Expected an error for a method without a programmed response")
+	}
+
+	methods := []string{}
+	for _, request := range mock.Requests() {
+		methods = append(methods, request.Method)
+	}
+	if fmt.Sprint(methods) != "[initialize ping prompts/list]" {
+		t.Errorf("Unexpected requests recorded: %v", methods)
+	}
+
+	notifications := mock.Notifications()
+	if len(notifications) != 1 || notifications[0].Method != "notifications/initialized" {
+		t.Errorf("Expected the initialized notification to be recorded, got %v", notifications)
+	}
+}
@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// IncomingRequest is a JSON-RPC request sent by the server to the client,
+// e.g. sampling/createMessage.
+type IncomingRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	// ID is kept in its wire form, as servers may use strings or numbers.
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// RequestHandler answers a request sent by the server. The returned result is
+// sent back as the response; a returned *RequestError is sent back with its
+// code, and any other error as an internal error.
+type RequestHandler func(ctx context.Context, request IncomingRequest) (any, error)
+
+// RequestError is an error answering a server request with a specific
+// JSON-RPC error code.
+type RequestError struct {
+	Code    int
+	Message string
+}
+
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("request error %d: %s", e.Code, e.Message)
+}
+
+// BidirectionalInterface is implemented by transports that can receive
+// requests from the server and send back responses.
+type BidirectionalInterface interface {
+	Interface
+
+	// SetRequestHandler sets the handler for requests sent by the server.
+	// Requests received while no handler is set are answered with a
+	// METHOD_NOT_FOUND error.
+	SetRequestHandler(handler RequestHandler)
+}
+
+// parseIncomingRequest reports whether data is a request sent by the server,
+// as opposed to a response or notification, and decodes it if so.
+func parseIncomingRequest(data []byte) (IncomingRequest, bool) {
+	var request IncomingRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return request, false
+	}
+	if request.Method == "" || len(request.ID) == 0 || string(request.ID) == "null" {
+		return request, false
+	}
+	return request, true
+}
+
+// answerRequest runs handler for a request sent by the server and returns the
+// JSON-encoded response to send back.
+func answerRequest(ctx context.Context, handler RequestHandler, request IncomingRequest) ([]byte, error) {
+	var result any
+	err := &RequestError{
+		Code:    mcp.METHOD_NOT_FOUND,
+		Message: fmt.Sprintf("client does not handle %s requests", request.Method),
+	}
+	if handler != nil {
+		var handlerErr error
+		result, handlerErr = handler(ctx, request)
+		if handlerErr == nil {
+			err = nil
+		} else if !errors.As(handlerErr, &err) {
+			err = &RequestError{Code: mcp.INTERNAL_ERROR, Message: handlerErr.Error()}
+		}
+	}
+
+	if err != nil {
+		response := mcp.JSONRPCError{JSONRPC: mcp.JSONRPC_VERSION, ID: request.ID}
+		response.Error.Code = err.Code
+		response.Error.Message = err.Message
+		return json.Marshal(response)
+	}
+	if result == nil {
+		result = struct{}{}
+	}
+	return json.Marshal(mcp.JSONRPCResponse{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      request.ID,
+		Result:  result,
+	})
+}
+
+var (
+	_ BidirectionalInterface = (*Stdio)(nil)
+	_ BidirectionalInterface = (*SSE)(nil)
+	_ BidirectionalInterface = (*StreamableHTTP)(nil)
+	_ BidirectionalInterface = (*InProcessTransport)(nil)
+)
@@ -25,15 +25,21 @@ type SSE struct {
 	baseURL        *url.URL
 	endpoint       *url.URL
 	httpClient     *http.Client
+	messageClient  *http.Client
 	responses      map[int64]chan *JSONRPCResponse
 	mu             sync.RWMutex
 	onNotification func(mcp.JSONRPCNotification)
+	onRequest      RequestHandler
+	onStateChange  ConnectionStateHandler
 	notifyMu       sync.RWMutex
 	endpointChan   chan struct{}
 	headers        map[string]string
+	logger         Logger
+	roundTripper   http.RoundTripper
 
 	started         atomic.Bool
 	closed          atomic.Bool
+	streamCtx       context.Context
 	cancelSSEStream context.CancelFunc
 }
 
@@ -45,9 +51,51 @@ func WithHeaders(headers map[string]string) ClientOption {
 	}
 }
 
+// WithHTTPClient sets the http.Client used for both the SSE stream and the
+// message POSTs. Use WithSSEStreamClient and WithSSEMessageClient to
+// configure them separately.
 func WithHTTPClient(httpClient *http.Client) ClientOption {
 	return func(sc *SSE) {
 		sc.httpClient = httpClient
+		sc.messageClient = httpClient
+	}
+}
+
+// WithSSEStreamClient sets the http.Client used for the long-lived SSE stream
+// GET. It should not have a Timeout, as that would tear down the stream once
+// it elapses.
+func WithSSEStreamClient(httpClient *http.Client) ClientOption {
+	return func(sc *SSE) {
+		sc.httpClient = httpClient
+	}
+}
+
+// WithSSEMessageClient sets the http.Client used to POST requests and
+// notifications to the message endpoint, e.g. to give them a timeout without
+// affecting the SSE stream.
+func WithSSEMessageClient(httpClient *http.Client) ClientOption {
+	return func(sc *SSE) {
+		sc.messageClient = httpClient
+	}
+}
+
+// WithRoundTripper sets the http.RoundTripper sending the transport's HTTP
+// requests, e.g. for mutual TLS, a proxy or recording requests. Unlike
+// WithHTTPClient it keeps the http.Client the transport creates itself.
+// Clients set with WithHTTPClient, WithSSEStreamClient or
+// WithSSEMessageClient take precedence and are used unchanged, so the round
+// tripper only applies to the stream or message client left at its default.
+func WithRoundTripper(rt http.RoundTripper) ClientOption {
+	return func(sc *SSE) {
+		sc.roundTripper = rt
+	}
+}
+
+// WithLogger sets the Logger receiving the transport's diagnostics.
+// By default they are discarded.
+func WithLogger(logger Logger) ClientOption {
+	return func(sc *SSE) {
+		sc.SetLogger(logger)
 	}
 }
 
@@ -59,18 +107,30 @@ func NewSSE(baseURL string, options ...ClientOption) (*SSE, error) {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
+	streamClient, messageClient := &http.Client{}, &http.Client{}
 	smc := &SSE{
-		baseURL:      parsedURL,
-		httpClient:   &http.Client{},
-		responses:    make(map[int64]chan *JSONRPCResponse),
-		endpointChan: make(chan struct{}),
-		headers:      make(map[string]string),
+		baseURL:       parsedURL,
+		httpClient:    streamClient,
+		messageClient: messageClient,
+		responses:     make(map[int64]chan *JSONRPCResponse),
+		endpointChan:  make(chan struct{}),
+		headers:       make(map[string]string),
+		logger:        noopLogger{},
 	}
 
 	for _, opt := range options {
 		opt(smc)
 	}
 
+	if smc.roundTripper != nil {
+		if smc.httpClient == streamClient {
+			streamClient.Transport = smc.roundTripper
+		}
+		if smc.messageClient == messageClient {
+			messageClient.Transport = smc.roundTripper
+		}
+	}
+
 	return smc, nil
 }
 
@@ -83,6 +143,7 @@ func (c *SSE) Start(ctx context.Context) error {
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
+	c.streamCtx = ctx
 	c.cancelSSEStream = cancel
 
 	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL.String(), nil)
@@ -133,7 +194,14 @@ func (c *SSE) Start(ctx context.Context) error {
 // It runs until the connection is closed or an error occurs.
 func (c *SSE) readSSE(reader io.ReadCloser) {
 	defer reader.Close()
+	defer func() {
+		if !c.closed.Load() {
+			c.notifyStateChange(ConnectionDisconnected, ErrConnectionLost)
+		}
+	}()
 
+	// A bufio.Reader rather than a Scanner, so that data lines of any
+	// length, e.g. large tool results, are read whole.
 	br := bufio.NewReader(reader)
 	var event, data string
 
@@ -150,7 +218,7 @@ func (c *SSE) readSSE(reader io.ReadCloser) {
 				break
 			}
 			if !c.closed.Load() {
-				fmt.Printf("SSE stream error: %v\n", err)
+				c.logger.Errorf("SSE stream error: %v", err)
 			}
 			return
 		}
@@ -182,27 +250,32 @@ func (c *SSE) handleSSEEvent(event, data string) {
 	case "endpoint":
 		endpoint, err := c.baseURL.Parse(data)
 		if err != nil {
-			fmt.Printf("Error parsing endpoint URL: %v\n", err)
+			c.logger.Errorf("Error parsing endpoint URL: %v", err)
 			return
 		}
 		if endpoint.Host != c.baseURL.Host {
-			fmt.Printf("Endpoint origin does not match connection origin\n")
+			c.logger.Errorf("Endpoint origin does not match connection origin")
 			return
 		}
 		c.endpoint = endpoint
 		close(c.endpointChan)
 
 	case "message":
+		if request, ok := parseIncomingRequest([]byte(data)); ok {
+			go c.handleRequest(request)
+			return
+		}
+
 		var baseMessage JSONRPCResponse
 		if err := json.Unmarshal([]byte(data), &baseMessage); err != nil {
-			fmt.Printf("Error unmarshaling message: %v\n", err)
+			c.logger.Errorf("Error unmarshaling message: %v", err)
 			return
 		}
 
 		// Handle notification
 		if baseMessage.ID == nil {
-			var notification mcp.JSONRPCNotification
-			if err := json.Unmarshal([]byte(data), &notification); err != nil {
+			notification, err := ToMCPNotification([]byte(data))
+			if err != nil {
 				return
 			}
 			c.notifyMu.RLock()
@@ -226,6 +299,75 @@ func (c *SSE) handleSSEEvent(event, data string) {
 	}
 }
 
+// SetLogger sets the Logger receiving the transport's diagnostics. A nil
+// logger discards them. It must be called before Start.
+func (c *SSE) SetLogger(logger Logger) {
+	c.logger = orNoopLogger(logger)
+}
+
+// SetRequestHandler sets the handler answering requests sent by the server.
+func (c *SSE) SetRequestHandler(handler RequestHandler) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+	c.onRequest = handler
+}
+
+// handleRequest answers a request sent by the server with the handler set
+// by SetRequestHandler, posting the response to the message endpoint. The
+// handler's context is canceled when the SSE stream ends.
+func (c *SSE) handleRequest(request IncomingRequest) {
+	c.notifyMu.RLock()
+	handler := c.onRequest
+	c.notifyMu.RUnlock()
+
+	ctx := c.streamCtx
+	response, err := answerRequest(ctx, handler, request)
+	if err != nil {
+		c.logger.Errorf("failed to marshal response to %s request: %v", request.Method, err)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint.String(), bytes.NewReader(response))
+	if err != nil {
+		c.logger.Errorf("failed to create response request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.messageClient.Do(req)
+	if err != nil {
+		c.logger.Errorf("failed to send response to %s request: %v", request.Method, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		c.logger.Errorf("response to %s request failed with status %d: %s", request.Method, resp.StatusCode, body)
+	}
+}
+
+// SetConnectionStateHandler sets the handler called when the SSE stream
+// ends without Close having been called.
+func (c *SSE) SetConnectionStateHandler(handler ConnectionStateHandler) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+	c.onStateChange = handler
+}
+
+// notifyStateChange calls the handler set by SetConnectionStateHandler.
+func (c *SSE) notifyStateChange(state ConnectionState, err error) {
+	c.notifyMu.RLock()
+	handler := c.onStateChange
+	c.notifyMu.RUnlock()
+	if handler != nil {
+		handler(state, err)
+	}
+}
+
 func (c *SSE) SetNotificationHandler(handler func(notification mcp.JSONRPCNotification)) {
 	c.notifyMu.Lock()
 	defer c.notifyMu.Unlock()
@@ -267,9 +409,15 @@ func (c *SSE) SendRequest(
 		req.Header.Set(k, v)
 	}
 
-	// Register response channel
+	// Register response channel. Close sets closed before failing the
+	// registered channels under mu, so checking it again under mu leaves no
+	// window for a request to register after Close and never be answered.
 	responseChan := make(chan *JSONRPCResponse, 1)
 	c.mu.Lock()
+	if c.closed.Load() {
+		c.mu.Unlock()
+		return nil, fmt.Errorf("request %d failed: %w", request.ID, ErrTransportClosed)
+	}
 	c.responses[request.ID] = responseChan
 	c.mu.Unlock()
 	deleteResponseChan := func() {
@@ -279,7 +427,7 @@ func (c *SSE) SendRequest(
 	}
 
 	// Send request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.messageClient.Do(req)
 	if err != nil {
 		deleteResponseChan()
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -290,6 +438,7 @@ func (c *SSE) SendRequest(
 	resp.Body.Close()
 
 	if err != nil {
+		deleteResponseChan()
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
@@ -304,12 +453,17 @@ func (c *SSE) SendRequest(
 	case <-ctx.Done():
 		deleteResponseChan()
 		return nil, ctx.Err()
-	case response := <-responseChan:
+	case response, ok := <-responseChan:
+		if !ok {
+			// Close closes the channels of all pending requests
+			return nil, fmt.Errorf("request %d failed: %w", request.ID, ErrTransportClosed)
+		}
 		return response, nil
 	}
 }
 
-// Close shuts down the SSE client connection and cleans up any pending responses.
+// Close shuts down the SSE client connection. Requests still waiting for a
+// response fail with ErrTransportClosed.
 // Returns an error if the shutdown process fails.
 func (c *SSE) Close() error {
 	if !c.closed.CompareAndSwap(false, true) {
@@ -360,7 +514,7 @@ func (c *SSE) SendNotification(ctx context.Context, notification mcp.JSONRPCNoti
 		req.Header.Set(k, v)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.messageClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -94,6 +95,9 @@ func startMockSSEEchoServer() (string, func()) {
 			fmt.Fprintf(sseWriter, "event: message\ndata: %s\n\n", responseBytes)
 			flush()
 			mu.Unlock()
+		case "debug/slow":
+			// hold the POST open long enough to exceed a short client timeout
+			time.Sleep(200 * time.Millisecond)
 		case "debug/echo_error_string":
 			data, _ := json.Marshal(request)
 			response["error"] = map[string]any{
@@ -203,6 +207,35 @@ func TestSSE(t *testing.T) {
 		}
 	})
 
+	t.Run("LargeMessage", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// Larger than the 64KB default token size of a bufio.Scanner
+		payload := strings.Repeat("x", 100*1024)
+		request := JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "debug/echo",
+			Params:  map[string]any{"payload": payload},
+		}
+
+		response, err := trans.SendRequest(ctx, request)
+		if err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+
+		var result struct {
+			Params map[string]any `json:"params"`
+		}
+		if err := json.Unmarshal(response.Result, &result); err != nil {
+			t.Fatalf("Failed to unmarshal result: %v", err)
+		}
+		if result.Params["payload"] != payload {
+			t.Errorf("Expected the %d byte payload intact, got %d bytes", len(payload), len(fmt.Sprint(result.Params["payload"])))
+		}
+	})
+
 	t.Run("SendRequestWithTimeout", func(t *testing.T) {
 		// Create a context that's already canceled
 		ctx, cancel := context.WithCancel(context.Background())
@@ -440,6 +473,50 @@ func TestSSEErrors(t *testing.T) {
 		trans.Close()
 	})
 
+	t.Run("WithSSEMessageClient", func(t *testing.T) {
+		url, closeF := startMockSSEEchoServer()
+		defer closeF()
+
+		// Only the message POSTs time out; the stream has no timeout
+		trans, err := NewSSE(url,
+			WithSSEStreamClient(&http.Client{}),
+			WithSSEMessageClient(&http.Client{Timeout: 50 * time.Millisecond}),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create SSE with custom clients: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := trans.Start(ctx); err != nil {
+			t.Fatalf("Failed to start transport: %v", err)
+		}
+		defer trans.Close()
+
+		_, err = trans.SendRequest(ctx, JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "debug/slow",
+		})
+		if err == nil {
+			t.Fatal("Expected slow request to time out, got nil")
+		}
+
+		// Outlive the message timeout; the stream must still deliver responses
+		time.Sleep(100 * time.Millisecond)
+		response, err := trans.SendRequest(ctx, JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      2,
+			Method:  "debug/echo",
+		})
+		if err != nil {
+			t.Fatalf("SendRequest after timeout failed: %v", err)
+		}
+		if response.ID == nil || *response.ID != 2 {
+			t.Errorf("Expected response ID 2, got %v", response.ID)
+		}
+	})
+
 	t.Run("RequestBeforeStart", func(t *testing.T) {
 		url, closeF := startMockSSEEchoServer()
 		defer closeF()
@@ -503,3 +580,149 @@ func TestSSEErrors(t *testing.T) {
 	})
 
 }
+
+func TestSSELogger(t *testing.T) {
+	// Sends the endpoint, then drops the connection mid-stream
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: endpoint\ndata: %s\n\n", "/message")
+		w.(http.Flusher).Flush()
+
+		time.Sleep(50 * time.Millisecond)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("Failed to hijack connection: %v", err)
+			return
+		}
+		conn.Close()
+	})
+	testServer := httptest.NewServer(handler)
+	defer testServer.Close()
+
+	logger := &recordingLogger{}
+	trans, err := NewSSE(testServer.URL, WithLogger(logger))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := trans.Start(ctx); err != nil {
+		t.Fatalf("Failed to start transport: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(logger.Errors()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	got := logger.Errors()
+	if len(got) != 1 || !strings.HasPrefix(got[0], "SSE stream error: ") {
+		t.Errorf("Expected the stream error to be logged, got %v", got)
+	}
+}
+
+func TestSSECloseFailsPendingRequests(t *testing.T) {
+	// Accepts messages but never answers them
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sse", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "event: endpoint\ndata: %s\n\n", "/message")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("/message", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	trans, err := NewSSE(testServer.URL + "/sse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start transport: %v", err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := trans.SendRequest(context.Background(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "ping"})
+		errs <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := trans.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrTransportClosed) {
+			t.Errorf("Expected ErrTransportClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendRequest still blocked after Close")
+	}
+}
+
+// recordingRoundTripper records the method of each request it sends.
+type recordingRoundTripper struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+func (rt *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.methods = append(rt.methods, req.Method)
+	rt.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func (rt *recordingRoundTripper) recorded() []string {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return append([]string(nil), rt.methods...)
+}
+
+func TestSSERoundTripper(t *testing.T) {
+	run := func(t *testing.T, options ...ClientOption) {
+		// The mock server serves a single stream, so each run gets its own
+		url, closeF := startMockSSEEchoServer()
+		defer closeF()
+
+		trans, err := NewSSE(url, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := trans.Start(ctx); err != nil {
+			t.Fatalf("Failed to start transport: %v", err)
+		}
+		defer trans.Close()
+		if _, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "debug/echo"}); err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+	}
+
+	t.Run("Used for the stream and messages", func(t *testing.T) {
+		rt := &recordingRoundTripper{}
+		run(t, WithRoundTripper(rt))
+		if got := rt.recorded(); len(got) != 2 || got[0] != http.MethodGet || got[1] != http.MethodPost {
+			t.Errorf("Expected the GET stream and a POST, got %v", got)
+		}
+	})
+
+	t.Run("Custom clients take precedence", func(t *testing.T) {
+		rt, messageRT := &recordingRoundTripper{}, &recordingRoundTripper{}
+		run(t, WithSSEMessageClient(&http.Client{Transport: messageRT}), WithRoundTripper(rt))
+		if got := rt.recorded(); len(got) != 1 || got[0] != http.MethodGet {
+			t.Errorf("Expected only the GET stream, got %v", got)
+		}
+		if got := messageRT.recorded(); len(got) != 1 || got[0] != http.MethodPost {
+			t.Errorf("Expected the message client to send the POST, got %v", got)
+		}
+	})
+}
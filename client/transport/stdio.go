@@ -4,11 +4,14 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/zillow/mcp-go/mcp"
 )
@@ -30,7 +33,85 @@ type Stdio struct {
 	mu             sync.RWMutex
 	done           chan struct{}
 	onNotification func(mcp.JSONRPCNotification)
+	onRequest      RequestHandler
+	onStateChange  ConnectionStateHandler
 	notifyMu       sync.RWMutex
+	logger         Logger
+
+	// procMu guards cmd, stdin, stdout and stderr, which are replaced when
+	// the subprocess is restarted.
+	procMu         sync.RWMutex
+	ctx            context.Context
+	maxRestarts    int
+	restartBackoff time.Duration
+	restarts       int
+	onRestart      func(attempt int)
+	exitErr        error
+	expandArgs     bool
+
+	// readErr is set under mu once readResponses stopped for good without
+	// Close, failing all further requests.
+	readErr error
+
+	// restartCtx lives as long as the transport, unlike the context passed
+	// to Start, and is used to relaunch the subprocess.
+	restartCtx       context.Context
+	cancelRestartCtx context.CancelFunc
+
+	// handshake holds the initialize request and notifications/initialized
+	// sent to the subprocess, guarded by mu and replayed after a restart.
+	handshake [][]byte
+}
+
+// ErrStdioProcessExited is returned for requests that were in flight when
+// the subprocess exited unexpectedly.
+var ErrStdioProcessExited = errors.New("stdio subprocess exited unexpectedly")
+
+// StdioOption configures a Stdio transport created with NewStdioWithOptions.
+type StdioOption func(*Stdio)
+
+// WithStdioAutoRestart relaunches the subprocess when it exits without
+// Close having been called, waiting backoff before each attempt and giving
+// up after maxRestarts restarts. Requests in flight when the subprocess
+// exits fail with ErrStdioProcessExited. After a restart, Stderr returns the
+// new subprocess's stream.
+//
+// The initialize request and notifications/initialized sent to the previous
+// subprocess are replayed to the new one, so it serves later requests
+// without the client initializing again; the replayed initialize response is
+// discarded. Other session state, e.g. subscriptions, is lost and can be
+// restored from the callback set with WithStdioOnRestart. The relaunched
+// subprocess runs until Close, even if the context passed to Start is done.
+func WithStdioAutoRestart(maxRestarts int, backoff time.Duration) StdioOption {
+	return func(s *Stdio) {
+		s.maxRestarts = maxRestarts
+		s.restartBackoff = backoff
+	}
+}
+
+// WithStdioOnRestart sets a callback invoked after the subprocess has been
+// relaunched. attempt starts at 1 and counts restarts over the transport's
+// lifetime.
+func WithStdioOnRestart(onRestart func(attempt int)) StdioOption {
+	return func(s *Stdio) {
+		s.onRestart = onRestart
+	}
+}
+
+// WithArgExpansion expands $VAR and ${VAR} references in the command and its
+// arguments before the subprocess is started. Variables are looked up in the
+// environment the subprocess runs with, i.e. os.Environ overridden by the env
+// passed to NewStdioWithOptions; undefined variables expand to the empty
+// string.
+//
+// No shell is involved: only variable references are expanded, and quotes,
+// globs, ~ and other shell syntax are passed through literally. Without this
+// option, arguments are passed to the subprocess exactly as given, including
+// any literal $.
+func WithArgExpansion() StdioOption {
+	return func(s *Stdio) {
+		s.expandArgs = true
+	}
 }
 
 // NewIO returns a new stdio-based transport using existing input, output, and
@@ -41,6 +122,7 @@ func NewIO(input io.Reader, output io.WriteCloser, logging io.ReadCloser) *Stdio
 		stdin:  output,
 		stdout: bufio.NewReader(input),
 		stderr: logging,
+		logger: noopLogger{},
 
 		responses: make(map[int64]chan *JSONRPCResponse),
 		done:      make(chan struct{}),
@@ -60,6 +142,7 @@ func NewStdio(
 		command: command,
 		args:    args,
 		env:     env,
+		logger:  noopLogger{},
 
 		responses: make(map[int64]chan *JSONRPCResponse),
 		done:      make(chan struct{}),
@@ -68,7 +151,32 @@ func NewStdio(
 	return client
 }
 
+// WithStdioLogger sets the Logger receiving the transport's diagnostics.
+// By default they are discarded.
+func WithStdioLogger(logger Logger) StdioOption {
+	return func(s *Stdio) {
+		s.SetLogger(logger)
+	}
+}
+
+// NewStdioWithOptions is like NewStdio but accepts StdioOption values to
+// customize the transport, e.g. WithStdioAutoRestart.
+func NewStdioWithOptions(
+	command string,
+	env []string,
+	args []string,
+	opts ...StdioOption,
+) *Stdio {
+	client := NewStdio(command, env, args...)
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
 func (c *Stdio) Start(ctx context.Context) error {
+	c.ctx = ctx
+	c.restartCtx, c.cancelRestartCtx = context.WithCancel(context.WithoutCancel(ctx))
 	if err := c.spawnCommand(ctx); err != nil {
 		return err
 	}
@@ -89,11 +197,15 @@ func (c *Stdio) spawnCommand(ctx context.Context) error {
 		return nil
 	}
 
-	cmd := exec.CommandContext(ctx, c.command, c.args...)
-
 	mergedEnv := os.Environ()
 	mergedEnv = append(mergedEnv, c.env...)
 
+	command, args := c.command, c.args
+	if c.expandArgs {
+		command, args = expandCommand(mergedEnv, command, args)
+	}
+
+	cmd := exec.CommandContext(ctx, command, args...)
 	cmd.Env = mergedEnv
 
 	stdin, err := cmd.StdinPipe()
@@ -111,19 +223,39 @@ func (c *Stdio) spawnCommand(ctx context.Context) error {
 		return fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start command: %w", err)
+	}
+
+	// Only keep a command that started, as Close waits for it
 	c.cmd = cmd
 	c.stdin = stdin
 	c.stderr = stderr
 	c.stdout = bufio.NewReader(stdout)
 
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start command: %w", err)
+	return nil
+}
+
+// expandCommand expands variable references in command and args using env,
+// a list of KEY=value pairs in which later entries take precedence.
+func expandCommand(env []string, command string, args []string) (string, []string) {
+	vars := make(map[string]string, len(env))
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			vars[key] = value
+		}
 	}
+	mapping := func(key string) string { return vars[key] }
 
-	return nil
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		expanded[i] = os.Expand(arg, mapping)
+	}
+	return os.Expand(command, mapping), expanded
 }
 
 // Close shuts down the stdio client, closing the stdin pipe and waiting for the subprocess to exit.
+// Requests still waiting for a response fail with ErrTransportClosed.
 // Returns an error if there are issues closing stdin or waiting for the subprocess to terminate.
 func (c *Stdio) Close() error {
 	select {
@@ -133,6 +265,18 @@ func (c *Stdio) Close() error {
 	}
 	// cancel all in-flight request
 	close(c.done)
+	if c.cancelRestartCtx != nil {
+		// After the subprocess has been waited for, so it can exit on its own
+		defer c.cancelRestartCtx()
+	}
+
+	c.procMu.Lock()
+	defer c.procMu.Unlock()
+
+	// The subprocess exited and was already reaped while restarting.
+	if c.command != "" && c.cmd == nil {
+		return nil
+	}
 
 	if err := c.stdin.Close(); err != nil {
 		return fmt.Errorf("failed to close stdin: %w", err)
@@ -148,6 +292,41 @@ func (c *Stdio) Close() error {
 	return nil
 }
 
+// SetLogger sets the Logger receiving the transport's diagnostics. A nil
+// logger discards them. It must be called before Start.
+func (c *Stdio) SetLogger(logger Logger) {
+	c.logger = orNoopLogger(logger)
+}
+
+// SetRequestHandler sets the handler answering requests sent by the server
+// on the subprocess's stdout. The handler's context is canceled on Close.
+func (c *Stdio) SetRequestHandler(handler RequestHandler) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+	c.onRequest = handler
+}
+
+// SetConnectionStateHandler sets the handler called when the subprocess
+// exits without Close having been called. With WithStdioAutoRestart the
+// handler sees ConnectionReconnecting, then ConnectionConnected once the
+// subprocess has been relaunched or ConnectionDisconnected once the restarts
+// are exhausted.
+func (c *Stdio) SetConnectionStateHandler(handler ConnectionStateHandler) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+	c.onStateChange = handler
+}
+
+// notifyStateChange calls the handler set by SetConnectionStateHandler.
+func (c *Stdio) notifyStateChange(state ConnectionState, err error) {
+	c.notifyMu.RLock()
+	handler := c.onStateChange
+	c.notifyMu.RUnlock()
+	if handler != nil {
+		handler(state, err)
+	}
+}
+
 // SetNotificationHandler sets the handler function to be called when a notification is received.
 // Only one handler can be set at a time; setting a new one replaces the previous handler.
 func (c *Stdio) SetNotificationHandler(
@@ -167,14 +346,38 @@ func (c *Stdio) readResponses() {
 		case <-c.done:
 			return
 		default:
-			line, err := c.stdout.ReadString('\n')
+			c.procMu.RLock()
+			stdout := c.stdout
+			c.procMu.RUnlock()
+
+			line, err := stdout.ReadString('\n')
 			if err != nil {
 				if err != io.EOF {
-					fmt.Printf("Error reading response: %v\n", err)
+					c.logger.Errorf("Error reading response: %v", err)
+				}
+				if c.isClosed() {
+					return
+				}
+				lost := fmt.Errorf("%w: %w", ErrConnectionLost, ErrStdioProcessExited)
+				if c.canRestart() {
+					c.notifyStateChange(ConnectionReconnecting, lost)
+				}
+				if c.restart() {
+					c.notifyStateChange(ConnectionConnected, nil)
+					continue
+				}
+				if !c.isClosed() {
+					c.notifyStateChange(ConnectionDisconnected, lost)
+					c.stopReading(fmt.Errorf("%w: %w", ErrTransportClosed, ErrStdioProcessExited))
 				}
 				return
 			}
 
+			if request, ok := parseIncomingRequest([]byte(line)); ok {
+				go c.handleRequest(request)
+				continue
+			}
+
 			var baseMessage JSONRPCResponse
 			if err := json.Unmarshal([]byte(line), &baseMessage); err != nil {
 				continue
@@ -182,8 +385,8 @@ func (c *Stdio) readResponses() {
 
 			// Handle notification
 			if baseMessage.ID == nil {
-				var notification mcp.JSONRPCNotification
-				if err := json.Unmarshal([]byte(line), &notification); err != nil {
+				notification, err := ToMCPNotification([]byte(line))
+				if err != nil {
 					continue
 				}
 				c.notifyMu.RLock()
@@ -208,6 +411,175 @@ func (c *Stdio) readResponses() {
 	}
 }
 
+// handleRequest answers a request sent by the server with the handler set
+// by SetRequestHandler. The handler's context is canceled on Close.
+func (c *Stdio) handleRequest(request IncomingRequest) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	c.notifyMu.RLock()
+	handler := c.onRequest
+	c.notifyMu.RUnlock()
+
+	response, err := answerRequest(ctx, handler, request)
+	if err != nil {
+		c.logger.Errorf("failed to marshal response to %s request: %v", request.Method, err)
+		return
+	}
+
+	c.procMu.RLock()
+	stdin := c.stdin
+	c.procMu.RUnlock()
+	if _, err := stdin.Write(append(response, '\n')); err != nil {
+		c.logger.Errorf("failed to write response to %s request: %v", request.Method, err)
+	}
+}
+
+// restart relaunches the subprocess after it exited unexpectedly, failing
+// the requests that were in flight. It reports whether reading should resume
+// from the new process.
+func (c *Stdio) restart() bool {
+	if c.maxRestarts <= 0 || c.command == "" {
+		return false
+	}
+
+	c.procMu.Lock()
+	select {
+	case <-c.done:
+		c.procMu.Unlock()
+		return false
+	default:
+	}
+	exitErr := ErrStdioProcessExited
+	if c.cmd != nil {
+		_ = c.cmd.Process.Kill()
+		if err := c.cmd.Wait(); err != nil {
+			exitErr = fmt.Errorf("%w: %v", ErrStdioProcessExited, err)
+		}
+		c.cmd = nil
+	}
+	c.procMu.Unlock()
+
+	c.failPendingRequests(exitErr)
+
+	for c.restarts < c.maxRestarts {
+		if c.ctx != nil && c.ctx.Err() != nil {
+			return false
+		}
+
+		select {
+		case <-c.done:
+			return false
+		case <-time.After(c.restartBackoff):
+		}
+
+		c.restarts++
+		c.procMu.Lock()
+		select {
+		case <-c.done:
+			c.procMu.Unlock()
+			return false
+		default:
+		}
+		err := c.spawnCommand(c.restartCtx)
+		if err == nil {
+			err = c.replayHandshake()
+		}
+		c.procMu.Unlock()
+		if err != nil {
+			c.logger.Errorf("Error restarting command: %v", err)
+			continue
+		}
+
+		c.logger.Debugf("Restarted command %s (attempt %d/%d)", c.command, c.restarts, c.maxRestarts)
+		if c.onRestart != nil {
+			c.onRestart(c.restarts)
+		}
+		return true
+	}
+
+	return false
+}
+
+// replayHandshake sends the recorded initialize request and
+// notifications/initialized to a relaunched subprocess. procMu must be held.
+// If writing fails, the subprocess is killed and the restart fails.
+func (c *Stdio) replayHandshake() error {
+	c.mu.RLock()
+	handshake := c.handshake
+	c.mu.RUnlock()
+	for _, message := range handshake {
+		if _, err := c.stdin.Write(message); err != nil {
+			_ = c.cmd.Process.Kill()
+			_ = c.cmd.Wait()
+			c.cmd = nil
+			return fmt.Errorf("failed to replay initialization: %w", err)
+		}
+	}
+	return nil
+}
+
+// recordHandshake keeps message, a marshaled initialize request or
+// notifications/initialized, for replayHandshake. A new initialize request
+// starts a new handshake.
+func (c *Stdio) recordHandshake(method string, message []byte) {
+	if c.maxRestarts <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch method {
+	case string(mcp.MethodInitialize):
+		c.handshake = [][]byte{message}
+	case "notifications/initialized":
+		c.handshake = append(c.handshake, message)
+	}
+}
+
+// canRestart reports whether restart has attempts left to relaunch the
+// subprocess.
+func (c *Stdio) canRestart() bool {
+	return c.maxRestarts > 0 && c.command != "" && c.restarts < c.maxRestarts
+}
+
+// isClosed reports whether Close has been called.
+func (c *Stdio) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// failPendingRequests unblocks all requests waiting for a response, making
+// them return err.
+func (c *Stdio) failPendingRequests(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.exitErr = err
+	for id, ch := range c.responses {
+		close(ch)
+		delete(c.responses, id)
+	}
+}
+
+// stopReading fails the requests waiting for a response, and all requests
+// sent afterwards, with err, once no more responses can arrive.
+func (c *Stdio) stopReading(err error) {
+	c.mu.Lock()
+	c.readErr = err
+	c.mu.Unlock()
+	c.failPendingRequests(err)
+}
+
 // SendRequest sends a JSON-RPC request to the server and waits for a response.
 // It creates a unique request ID, sends the request over stdin, and waits for
 // the corresponding response or context cancellation.
@@ -216,7 +588,10 @@ func (c *Stdio) SendRequest(
 	ctx context.Context,
 	request JSONRPCRequest,
 ) (*JSONRPCResponse, error) {
-	if c.stdin == nil {
+	c.procMu.RLock()
+	stdin := c.stdin
+	c.procMu.RUnlock()
+	if stdin == nil {
 		return nil, fmt.Errorf("stdio client not started")
 	}
 
@@ -230,6 +605,11 @@ func (c *Stdio) SendRequest(
 	// Register response channel
 	responseChan := make(chan *JSONRPCResponse, 1)
 	c.mu.Lock()
+	if c.readErr != nil {
+		err := c.readErr
+		c.mu.Unlock()
+		return nil, fmt.Errorf("request %d failed: %w", request.ID, err)
+	}
 	c.responses[request.ID] = responseChan
 	c.mu.Unlock()
 	deleteResponseChan := func() {
@@ -239,16 +619,25 @@ func (c *Stdio) SendRequest(
 	}
 
 	// Send request
-	if _, err := c.stdin.Write(requestBytes); err != nil {
+	if _, err := stdin.Write(requestBytes); err != nil {
 		deleteResponseChan()
 		return nil, fmt.Errorf("failed to write request: %w", err)
 	}
+	c.recordHandshake(request.Method, requestBytes)
 
 	select {
 	case <-ctx.Done():
 		deleteResponseChan()
 		return nil, ctx.Err()
-	case response := <-responseChan:
+	case <-c.done:
+		deleteResponseChan()
+		return nil, fmt.Errorf("request %d failed: %w", request.ID, ErrTransportClosed)
+	case response, ok := <-responseChan:
+		if !ok {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+			return nil, fmt.Errorf("request %d failed: %w", request.ID, c.exitErr)
+		}
 		return response, nil
 	}
 }
@@ -258,7 +647,10 @@ func (c *Stdio) SendNotification(
 	ctx context.Context,
 	notification mcp.JSONRPCNotification,
 ) error {
-	if c.stdin == nil {
+	c.procMu.RLock()
+	stdin := c.stdin
+	c.procMu.RUnlock()
+	if stdin == nil {
 		return fmt.Errorf("stdio client not started")
 	}
 
@@ -268,9 +660,10 @@ func (c *Stdio) SendNotification(
 	}
 	notificationBytes = append(notificationBytes, '\n')
 
-	if _, err := c.stdin.Write(notificationBytes); err != nil {
+	if _, err := stdin.Write(notificationBytes); err != nil {
 		return fmt.Errorf("failed to write notification: %w", err)
 	}
+	c.recordHandshake(notification.Method, notificationBytes)
 
 	return nil
 }
@@ -278,5 +671,7 @@ func (c *Stdio) SendNotification(
 // Stderr returns a reader for the stderr output of the subprocess.
 // This can be used to capture error messages or logs from the subprocess.
 func (c *Stdio) Stderr() io.Reader {
+	c.procMu.RLock()
+	defer c.procMu.RUnlock()
 	return c.stderr
 }
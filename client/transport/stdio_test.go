@@ -1,12 +1,18 @@
 package transport
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
 	"runtime"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -409,3 +415,386 @@ func TestStdioErrors(t *testing.T) {
 	})
 
 }
+
+func TestStdioAutoRestart(t *testing.T) {
+	// Create a temporary file for the mock server
+	tempFile, err := os.CreateTemp("", "mockstdio_server")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	tempFile.Close()
+	mockServerPath := tempFile.Name()
+
+	// Add .exe suffix on Windows
+	if runtime.GOOS == "windows" {
+		os.Remove(mockServerPath) // Remove the empty file first
+		mockServerPath += ".exe"
+	}
+
+	if compileErr := compileTestServer(mockServerPath); compileErr != nil {
+		t.Fatalf("Failed to compile mock server: %v", compileErr)
+	}
+	defer os.Remove(mockServerPath)
+
+	restarted := make(chan int, 10)
+	stdio := NewStdioWithOptions(mockServerPath, nil, nil,
+		WithStdioAutoRestart(1, 10*time.Millisecond),
+		WithStdioOnRestart(func(attempt int) {
+			restarted <- attempt
+		}),
+	)
+	states := make(chan ConnectionState, 10)
+	stdio.SetConnectionStateHandler(func(state ConnectionState, err error) {
+		if state != ConnectionConnected && !errors.Is(err, ErrConnectionLost) {
+			t.Errorf("Expected ErrConnectionLost for state %s, got: %v", state, err)
+		}
+		states <- state
+	})
+	expectState := func(want ConnectionState) {
+		t.Helper()
+		select {
+		case state := <-states:
+			if state != want {
+				t.Errorf("Expected connection state %s, got %s", want, state)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for connection state %s", want)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The relaunched process must outlive the context passed to Start
+	startCtx, cancelStart := context.WithCancel(ctx)
+	if startErr := stdio.Start(startCtx); startErr != nil {
+		t.Fatalf("Failed to start Stdio transport: %v", startErr)
+	}
+	defer stdio.Close()
+
+	if _, err := stdio.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 10, Method: "initialize"}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if err := stdio.SendNotification(ctx, mcp.JSONRPCNotification{
+		JSONRPC:      "2.0",
+		Notification: mcp.Notification{Method: "notifications/initialized"},
+	}); err != nil {
+		t.Fatalf("SendNotification failed: %v", err)
+	}
+
+	// The mock server exits while handling this request.
+	_, err = stdio.SendRequest(ctx, JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "debug/exit",
+	})
+	if !errors.Is(err, ErrStdioProcessExited) {
+		t.Fatalf("Expected ErrStdioProcessExited, got: %v", err)
+	}
+
+	select {
+	case attempt := <-restarted:
+		if attempt != 1 {
+			t.Errorf("Expected restart attempt 1, got %d", attempt)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for restart")
+	}
+	expectState(ConnectionReconnecting)
+	expectState(ConnectionConnected)
+	cancelStart()
+
+	// The relaunched process was initialized again and serves requests.
+	response, err := stdio.SendRequest(ctx, JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      2,
+		Method:  "debug/initialized",
+	})
+	if err != nil {
+		t.Fatalf("SendRequest after restart failed: %v", err)
+	}
+	if response.Error != nil {
+		t.Fatalf("Unexpected error response after restart: %v", response.Error)
+	}
+	if string(response.Result) != `{"initialized":true}` {
+		t.Errorf("Expected the relaunched process to be initialized, got %s", response.Result)
+	}
+
+	// No restarts are left, so the next exit is final.
+	_, err = stdio.SendRequest(ctx, JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      3,
+		Method:  "debug/exit",
+	})
+	if !errors.Is(err, ErrStdioProcessExited) {
+		t.Fatalf("Expected ErrStdioProcessExited, got: %v", err)
+	}
+
+	select {
+	case attempt := <-restarted:
+		t.Errorf("Unexpected restart attempt %d after exhausting restarts", attempt)
+	case <-time.After(100 * time.Millisecond):
+	}
+	expectState(ConnectionDisconnected)
+}
+
+func TestStdioAutoRestartSpawnFailure(t *testing.T) {
+	tempFile, err := os.CreateTemp("", "mockstdio_server")
+	if err != nil {
+		t.Fatalf("Failed to create temp file: %v", err)
+	}
+	tempFile.Close()
+	mockServerPath := tempFile.Name()
+	if runtime.GOOS == "windows" {
+		os.Remove(mockServerPath)
+		mockServerPath += ".exe"
+	}
+	if compileErr := compileTestServer(mockServerPath); compileErr != nil {
+		t.Fatalf("Failed to compile mock server: %v", compileErr)
+	}
+
+	stdio := NewStdioWithOptions(mockServerPath, nil, nil, WithStdioAutoRestart(1, 10*time.Millisecond))
+	states := make(chan ConnectionState, 10)
+	stdio.SetConnectionStateHandler(func(state ConnectionState, err error) {
+		states <- state
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := stdio.Start(ctx); err != nil {
+		t.Fatalf("Failed to start Stdio transport: %v", err)
+	}
+
+	// Relaunching fails once the binary is gone
+	if err := os.Remove(mockServerPath); err != nil {
+		t.Skipf("Cannot remove the running mock server: %v", err)
+	}
+	_, _ = stdio.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "debug/exit"})
+	for state := range states {
+		if state == ConnectionDisconnected {
+			break
+		}
+	}
+
+	if err := stdio.Close(); err != nil {
+		t.Errorf("Expected Close to succeed after a failed restart, got: %v", err)
+	}
+}
+
+type recordingLogger struct {
+	mu     sync.Mutex
+	errors []string
+}
+
+func (l *recordingLogger) Debugf(string, ...any) {}
+
+func (l *recordingLogger) Errorf(format string, v ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errors = append(l.errors, fmt.Sprintf(format, v...))
+}
+
+func (l *recordingLogger) Errors() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.errors...)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestStdioLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	stdio := NewIO(failingReader{}, nopWriteCloser{io.Discard}, io.NopCloser(strings.NewReader("")))
+	stdio.SetLogger(logger)
+
+	if err := stdio.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start Stdio transport: %v", err)
+	}
+	defer stdio.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for len(logger.Errors()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	got := logger.Errors()
+	if len(got) != 1 || got[0] != "Error reading response: read failed" {
+		t.Errorf("Expected the read error to be logged, got %v", got)
+	}
+}
+
+func TestStdioServerRequest(t *testing.T) {
+	// Pipes standing in for the server's stdout and stdin
+	serverStdout, writeToClient := io.Pipe()
+	readFromClient, serverStdin := io.Pipe()
+	stdio := NewIO(serverStdout, serverStdin, io.NopCloser(strings.NewReader("")))
+
+	stdio.SetRequestHandler(func(ctx context.Context, request IncomingRequest) (any, error) {
+		if request.Method != "sampling/createMessage" {
+			return nil, &RequestError{Code: mcp.METHOD_NOT_FOUND, Message: "unknown method"}
+		}
+		return map[string]any{"model": "test-model"}, nil
+	})
+
+	if err := stdio.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start Stdio transport: %v", err)
+	}
+	defer stdio.Close()
+
+	responses := bufio.NewReader(readFromClient)
+	tests := []struct {
+		request  string
+		expected string
+	}{
+		{
+			request:  `{"jsonrpc":"2.0","id":"srv-1","method":"sampling/createMessage","params":{"maxTokens":1}}`,
+			expected: `{"jsonrpc":"2.0","id":"srv-1","result":{"model":"test-model"}}`,
+		},
+		{
+			request:  `{"jsonrpc":"2.0","id":2,"method":"roots/list"}`,
+			expected: `{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"unknown method"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		if _, err := writeToClient.Write([]byte(tt.request + "\n")); err != nil {
+			t.Fatalf("Failed to write request: %v", err)
+		}
+		line, err := responses.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		if got := strings.TrimSpace(line); got != tt.expected {
+			t.Errorf("Expected response %s, got %s", tt.expected, got)
+		}
+	}
+}
+
+func TestStdioCloseFailsPendingRequests(t *testing.T) {
+	// The server never answers
+	serverStdout, _ := io.Pipe()
+	stdio := NewIO(serverStdout, nopWriteCloser{io.Discard}, io.NopCloser(strings.NewReader("")))
+	if err := stdio.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start Stdio transport: %v", err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := stdio.SendRequest(context.Background(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "ping"})
+		errs <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err := stdio.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrTransportClosed) {
+			t.Errorf("Expected ErrTransportClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendRequest still blocked after Close")
+	}
+}
+
+func TestStdioEOFFailsPendingRequests(t *testing.T) {
+	// The server never answers, then closes its stdout
+	serverStdout, writeToClient := io.Pipe()
+	stdio := NewIO(serverStdout, nopWriteCloser{io.Discard}, io.NopCloser(strings.NewReader("")))
+	if err := stdio.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start Stdio transport: %v", err)
+	}
+	defer stdio.Close()
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := stdio.SendRequest(context.Background(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "ping"})
+		errs <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	writeToClient.Close()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, ErrTransportClosed) {
+			t.Errorf("Expected ErrTransportClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendRequest still blocked after the server closed stdout")
+	}
+
+	// Later requests fail right away instead of waiting for their context
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := stdio.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "ping"})
+	if !errors.Is(err, ErrTransportClosed) {
+		t.Errorf("Expected ErrTransportClosed, got %v", err)
+	}
+}
+
+func TestStdioArgExpansion(t *testing.T) {
+	mockServerPath := filepath.Join(t.TempDir(), "mockstdio_server")
+	if runtime.GOOS == "windows" {
+		mockServerPath += ".exe"
+	}
+	if compileErr := compileTestServer(mockServerPath); compileErr != nil {
+		t.Fatalf("Failed to compile mock server: %v", compileErr)
+	}
+
+	env := []string{"MOCK_SERVER_DIR=" + filepath.Dir(mockServerPath)}
+	command := "${MOCK_SERVER_DIR}/" + filepath.Base(mockServerPath)
+
+	t.Run("Expands command with option", func(t *testing.T) {
+		stdio := NewStdioWithOptions(command, env, nil, WithArgExpansion())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := stdio.Start(ctx); err != nil {
+			t.Fatalf("Failed to start Stdio transport: %v", err)
+		}
+		defer stdio.Close()
+
+		response, err := stdio.SendRequest(ctx, JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "ping",
+		})
+		if err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+		if response.Error != nil {
+			t.Errorf("Unexpected error response: %v", response.Error)
+		}
+	})
+
+	t.Run("Leaves command literal without option", func(t *testing.T) {
+		stdio := NewStdioWithOptions(command, env, nil)
+		if err := stdio.Start(context.Background()); err == nil {
+			stdio.Close()
+			t.Fatal("Expected starting an unexpanded command to fail")
+		}
+	})
+
+	t.Run("Expands args", func(t *testing.T) {
+		env := append(os.Environ(), "NAME=first", "NAME=second")
+		command, args := expandCommand(env, "$NAME", []string{"${NAME}/server", "--flag=$NAME", "$UNSET_MCP_VAR", "plain"})
+		if command != "second" {
+			t.Errorf("Expected command %q, got %q", "second", command)
+		}
+		want := []string{"second/server", "--flag=second", "", "plain"}
+		if !reflect.DeepEqual(args, want) {
+			t.Errorf("Expected args %q, got %q", want, args)
+		}
+	})
+}
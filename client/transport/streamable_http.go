@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -26,6 +27,14 @@ func WithHTTPHeaders(headers map[string]string) StreamableHTTPCOption {
 	}
 }
 
+// WithHTTPLogger sets the Logger receiving the transport's diagnostics.
+// By default they are discarded.
+func WithHTTPLogger(logger Logger) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.SetLogger(logger)
+	}
+}
+
 // WithHTTPTimeout sets the timeout for a HTTP request and stream.
 func WithHTTPTimeout(timeout time.Duration) StreamableHTTPCOption {
 	return func(sc *StreamableHTTP) {
@@ -33,6 +42,90 @@ func WithHTTPTimeout(timeout time.Duration) StreamableHTTPCOption {
 	}
 }
 
+// WithHTTPRoundTripper sets the http.RoundTripper sending the transport's
+// HTTP requests, e.g. for mutual TLS, a proxy or recording requests. It
+// combines with the other options configuring the transport's http.Client,
+// such as WithHTTPTimeout.
+func WithHTTPRoundTripper(rt http.RoundTripper) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.httpClient.Transport = rt
+	}
+}
+
+// WithSessionHeaderName sets the HTTP header carrying the session ID, for
+// deployments where proxies rename the default Mcp-Session-Id header.
+func WithSessionHeaderName(name string) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.sessionHeaderName = name
+	}
+}
+
+// WithSessionCookie carries the session ID in the cookie with the given name
+// instead of a header: it is read from the Set-Cookie header of the
+// initialize response and sent as a Cookie with every later request. Use it
+// for proxies that strip custom headers.
+func WithSessionCookie(name string) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.sessionCookieName = name
+	}
+}
+
+// WithStreamResume makes the transport keep a GET stream open after
+// initialization, on which the server can send notifications and requests
+// outside of any request of the client. When the stream drops, it is
+// reopened with the Last-Event-ID header set to the ID of the last event
+// received, so the server can replay the events sent in between. Servers
+// answering the GET request with 405 Method Not Allowed do not offer the
+// stream, and it is not retried. Nor is it when the server answers 404 Not
+// Found, as the session is terminated: the stream is opened again once the
+// client initializes a new session.
+func WithStreamResume(enabled bool) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.streamResume = enabled
+	}
+}
+
+// WithInitializeRetry resends the initialize request up to maxRetries times
+// when it fails transiently, i.e. the server could not be reached, answered
+// with a 5xx, 408 or 429 status, or sent an unreadable response, waiting
+// backoff before each attempt. Without a successful initialize no session is
+// established, so retrying later requests would not help; they are never
+// retried.
+func WithInitializeRetry(maxRetries int, backoff time.Duration) StreamableHTTPCOption {
+	return func(sc *StreamableHTTP) {
+		sc.initializeRetries = maxRetries
+		sc.initializeBackoff = backoff
+	}
+}
+
+// ErrInitializeRejected is returned when the server answers the initialize
+// request with an HTTP status that retrying would not change, e.g. 401 or
+// 404. JSON-RPC errors the server returns for initialize are passed on as
+// responses like for any other request.
+var ErrInitializeRejected = errors.New("server rejected initialize")
+
+// ErrInitializeFailed is returned when the initialize request could not be
+// completed, after the retries configured with WithInitializeRetry.
+var ErrInitializeFailed = errors.New("initialize request failed")
+
+// statusError reports an HTTP response with an unexpected status that did
+// not carry a JSON-RPC response.
+type statusError struct {
+	statusCode int
+	body       []byte
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.statusCode, e.body)
+}
+
+// transient reports whether the request may succeed if sent again.
+func (e *statusError) transient() bool {
+	return e.statusCode >= 500 ||
+		e.statusCode == http.StatusRequestTimeout ||
+		e.statusCode == http.StatusTooManyRequests
+}
+
 // StreamableHTTP implements Streamable HTTP transport.
 //
 // It transmits JSON-RPC messages over individual HTTP requests. One message per request.
@@ -41,23 +134,36 @@ func WithHTTPTimeout(timeout time.Duration) StreamableHTTPCOption {
 //
 // https://modelcontextprotocol.io/specification/2025-03-26/basic/transports
 //
+// Listening for server messages when no request is in flight, and resuming
+// that stream, are enabled with WithStreamResume:
+// https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#listening-for-messages-from-the-server
+// https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#resumability-and-redelivery
+//
 // The current implementation does not support the following features:
 //   - batching
-//   - continuously listening for server notifications when no request is in flight
-//     (https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#listening-for-messages-from-the-server)
-//   - resuming stream
-//     (https://modelcontextprotocol.io/specification/2025-03-26/basic/transports#resumability-and-redelivery)
-//   - server -> client request
+//   - resuming the response stream of a request
 type StreamableHTTP struct {
 	baseURL    *url.URL
 	httpClient *http.Client
 	headers    map[string]string
+	logger     Logger
 
-	sessionID atomic.Value // string
+	sessionID         atomic.Value // string
+	sessionHeaderName string
+	sessionCookieName string
 
 	notificationHandler func(mcp.JSONRPCNotification)
+	requestHandler      RequestHandler
 	notifyMu            sync.RWMutex
 
+	streamResume     bool
+	streamRetryDelay time.Duration
+	listening        atomic.Bool
+	lastEventID      atomic.Value // string
+
+	initializeRetries int
+	initializeBackoff time.Duration
+
 	closed chan struct{}
 }
 
@@ -70,12 +176,16 @@ func NewStreamableHTTP(baseURL string, options ...StreamableHTTPCOption) (*Strea
 	}
 
 	smc := &StreamableHTTP{
-		baseURL:    parsedURL,
-		httpClient: &http.Client{},
-		headers:    make(map[string]string),
-		closed:     make(chan struct{}),
+		baseURL:           parsedURL,
+		httpClient:        &http.Client{},
+		headers:           make(map[string]string),
+		logger:            noopLogger{},
+		closed:            make(chan struct{}),
+		sessionHeaderName: headerKeySessionID,
+		streamRetryDelay:  time.Second,
 	}
 	smc.sessionID.Store("") // set initial value to simplify later usage
+	smc.lastEventID.Store("")
 
 	for _, opt := range options {
 		opt(smc)
@@ -110,13 +220,13 @@ func (c *StreamableHTTP) Close() error {
 			defer cancel()
 			req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL.String(), nil)
 			if err != nil {
-				fmt.Printf("failed to create close request\n: %v", err)
+				c.logger.Errorf("failed to create close request: %v", err)
 				return
 			}
-			req.Header.Set(headerKeySessionID, sessionId)
+			c.setSessionID(req, sessionId)
 			res, err := c.httpClient.Do(req)
 			if err != nil {
-				fmt.Printf("failed to send close request\n: %v", err)
+				c.logger.Errorf("failed to send close request: %v", err)
 				return
 			}
 			res.Body.Close()
@@ -131,12 +241,79 @@ const (
 	headerKeySessionID = "Mcp-Session-Id"
 )
 
+// setSessionID attaches the session ID to req, in the session cookie if one
+// is configured and in the session header otherwise.
+func (c *StreamableHTTP) setSessionID(req *http.Request, sessionID string) {
+	if c.sessionCookieName != "" {
+		req.AddCookie(&http.Cookie{Name: c.sessionCookieName, Value: sessionID})
+		return
+	}
+	req.Header.Set(c.sessionHeaderName, sessionID)
+}
+
+// sessionIDFromResponse returns the session ID assigned by the server in
+// resp, or "" if there is none.
+func (c *StreamableHTTP) sessionIDFromResponse(resp *http.Response) string {
+	if c.sessionCookieName != "" {
+		for _, cookie := range resp.Cookies() {
+			if cookie.Name == c.sessionCookieName {
+				return cookie.Value
+			}
+		}
+		return ""
+	}
+	return resp.Header.Get(c.sessionHeaderName)
+}
+
 // SendRequest sends a JSON-RPC request to the server and waits for a response.
 // Returns the raw JSON response message or an error if the request fails.
+// Errors for the initialize request wrap ErrInitializeRejected or
+// ErrInitializeFailed.
 func (c *StreamableHTTP) SendRequest(
 	ctx context.Context,
 	request JSONRPCRequest,
 ) (*JSONRPCResponse, error) {
+	if request.Method == initializeMethod {
+		return c.sendInitialize(ctx, request)
+	}
+	return c.sendRequest(ctx, request)
+}
+
+// sendInitialize sends the initialize request, retrying it as configured
+// with WithInitializeRetry when it fails transiently.
+func (c *StreamableHTTP) sendInitialize(
+	ctx context.Context,
+	request JSONRPCRequest,
+) (*JSONRPCResponse, error) {
+	for attempt := 0; ; attempt++ {
+		response, err := c.sendRequest(ctx, request)
+		if err == nil {
+			return response, nil
+		}
+		var statusErr *statusError
+		if errors.As(err, &statusErr) && !statusErr.transient() {
+			return nil, fmt.Errorf("%w: %w", ErrInitializeRejected, err)
+		}
+		if attempt >= c.initializeRetries || ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInitializeFailed, err)
+		}
+
+		c.logger.Debugf("Retrying initialize (attempt %d/%d): %v", attempt+1, c.initializeRetries, err)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", ErrInitializeFailed, ctx.Err())
+		case <-c.closed:
+			return nil, fmt.Errorf("%w: %w", ErrInitializeFailed, ErrTransportClosed)
+		case <-time.After(c.initializeBackoff):
+		}
+	}
+}
+
+// sendRequest sends request once and waits for its response.
+func (c *StreamableHTTP) sendRequest(
+	ctx context.Context,
+	request JSONRPCRequest,
+) (*JSONRPCResponse, error) {
 
 	// Create a combined context that could be canceled when the client is closed
 	newCtx, cancel := context.WithCancel(ctx)
@@ -168,7 +345,7 @@ func (c *StreamableHTTP) SendRequest(
 	req.Header.Set("Accept", "application/json, text/event-stream")
 	sessionID := c.sessionID.Load()
 	if sessionID != "" {
-		req.Header.Set(headerKeySessionID, sessionID.(string))
+		c.setSessionID(req, sessionID.(string))
 	}
 	for k, v := range c.headers {
 		req.Header.Set(k, v)
@@ -184,7 +361,7 @@ func (c *StreamableHTTP) SendRequest(
 	// Check if we got an error response
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		// handle session closed
-		if resp.StatusCode == http.StatusNotFound {
+		if resp.StatusCode == http.StatusNotFound && sessionID != "" {
 			c.sessionID.CompareAndSwap(sessionID, "")
 			return nil, fmt.Errorf("session terminated (404). need to re-initialize")
 		}
@@ -195,15 +372,21 @@ func (c *StreamableHTTP) SendRequest(
 		if err := json.Unmarshal(body, &errResponse); err == nil {
 			return &errResponse, nil
 		}
-		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, body)
+		return nil, &statusError{statusCode: resp.StatusCode, body: body}
 	}
 
 	if request.Method == initializeMethod {
 		// saved the received session ID in the response
 		// empty session ID is allowed
-		if sessionID := resp.Header.Get(headerKeySessionID); sessionID != "" {
+		if sessionID := c.sessionIDFromResponse(resp); sessionID != "" {
 			c.sessionID.Store(sessionID)
 		}
+		if c.streamResume {
+			// Listen for the new session unless still listening
+			if c.listening.CompareAndSwap(false, true) {
+				go c.listen()
+			}
+		}
 	}
 
 	// Handle different response types
@@ -247,32 +430,10 @@ func (c *StreamableHTTP) handleSSEResponse(ctx context.Context, reader io.ReadCl
 		// only close responseChan after readingSSE()
 		defer close(responseChan)
 
-		c.readSSE(ctx, reader, func(event, data string) {
-
-			// (unsupported: batching)
-
-			var message JSONRPCResponse
-			if err := json.Unmarshal([]byte(data), &message); err != nil {
-				fmt.Printf("failed to unmarshal message: %v\n", err)
-				return
+		c.readSSE(ctx, reader, func(event, data, id string) {
+			if message := c.handleSSEMessage(data); message != nil {
+				responseChan <- message
 			}
-
-			// Handle notification
-			if message.ID == nil {
-				var notification mcp.JSONRPCNotification
-				if err := json.Unmarshal([]byte(data), &notification); err != nil {
-					fmt.Printf("failed to unmarshal notification: %v\n", err)
-					return
-				}
-				c.notifyMu.RLock()
-				if c.notificationHandler != nil {
-					c.notificationHandler(notification)
-				}
-				c.notifyMu.RUnlock()
-				return
-			}
-
-			responseChan <- &message
 		})
 	}()
 
@@ -288,13 +449,144 @@ func (c *StreamableHTTP) handleSSEResponse(ctx context.Context, reader io.ReadCl
 	}
 }
 
-// readSSE reads the SSE stream(reader) and calls the handler for each event and data pair.
+// handleSSEMessage dispatches a message received on an SSE stream: requests
+// and notifications from the server are passed to their handlers, and
+// responses are returned.
+func (c *StreamableHTTP) handleSSEMessage(data string) *JSONRPCResponse {
+	// (unsupported: batching)
+
+	if request, ok := parseIncomingRequest([]byte(data)); ok {
+		go c.handleRequest(request)
+		return nil
+	}
+
+	var message JSONRPCResponse
+	if err := json.Unmarshal([]byte(data), &message); err != nil {
+		c.logger.Errorf("failed to unmarshal message: %v", err)
+		return nil
+	}
+
+	// Handle notification
+	if message.ID == nil {
+		notification, err := ToMCPNotification([]byte(data))
+		if err != nil {
+			c.logger.Errorf("failed to handle notification: %v", err)
+			return nil
+		}
+		c.notifyMu.RLock()
+		if c.notificationHandler != nil {
+			c.notificationHandler(notification)
+		}
+		c.notifyMu.RUnlock()
+		return nil
+	}
+
+	return &message
+}
+
+// errListenNotAllowed is returned by listenOnStream when the server does not
+// offer a GET stream.
+var errListenNotAllowed = errors.New("server does not offer a GET stream")
+
+// errListenSessionTerminated is returned by listenOnStream when the server
+// answers 404, as the session is gone until the client initializes again.
+var errListenSessionTerminated = errors.New("session terminated (404)")
+
+// listen keeps a GET stream open for server messages until the transport is
+// closed, reopening it from the last event received when it drops.
+func (c *StreamableHTTP) listen() {
+	defer c.listening.Store(false)
+	for {
+		err := c.listenOnStream()
+		select {
+		case <-c.closed:
+			return
+		default:
+		}
+		if errors.Is(err, errListenNotAllowed) || errors.Is(err, errListenSessionTerminated) {
+			c.logger.Debugf("not listening for server messages: %v", err)
+			return
+		}
+		if err != nil {
+			c.logger.Errorf("GET stream failed: %v", err)
+		}
+
+		select {
+		case <-c.closed:
+			return
+		case <-time.After(c.streamRetryDelay):
+		}
+	}
+}
+
+// listenOnStream opens a GET stream, resuming after the last event received
+// if any, and handles its messages until it ends.
+func (c *StreamableHTTP) listenOnStream() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	sessionID := c.sessionID.Load()
+	if sessionID != "" {
+		c.setSessionID(req, sessionID.(string))
+	}
+	if lastEventID := c.lastEventID.Load(); lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID.(string))
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusMethodNotAllowed:
+		return errListenNotAllowed
+	case http.StatusNotFound:
+		// The session is gone, and the event IDs with it
+		c.sessionID.CompareAndSwap(sessionID, "")
+		c.lastEventID.Store("")
+		return errListenSessionTerminated
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	c.readSSE(ctx, resp.Body, func(event, data, id string) {
+		if id != "" {
+			c.lastEventID.Store(id)
+		}
+		if message := c.handleSSEMessage(data); message != nil {
+			c.logger.Errorf("unexpected response on GET stream: %v", message.ID)
+		}
+	})
+	return nil
+}
+
+// readSSE reads the SSE stream(reader) and calls the handler for each event,
+// with its data and its ID if it has one.
 // It will end when the reader is closed (or the context is done).
-func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, handler func(event, data string)) {
+func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, handler func(event, data, id string)) {
 	defer reader.Close()
 
 	br := bufio.NewReader(reader)
-	var event, data string
+	var event, data, id string
 
 	for {
 		select {
@@ -306,7 +598,7 @@ func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, hand
 				if err == io.EOF {
 					// Process any pending event before exit
 					if event != "" && data != "" {
-						handler(event, data)
+						handler(event, data, id)
 					}
 					return
 				}
@@ -314,7 +606,7 @@ func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, hand
 				case <-ctx.Done():
 					return
 				default:
-					fmt.Printf("SSE stream error: %v\n", err)
+					c.logger.Errorf("SSE stream error: %v", err)
 					return
 				}
 			}
@@ -324,9 +616,10 @@ func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, hand
 			if line == "" {
 				// Empty line means end of event
 				if event != "" && data != "" {
-					handler(event, data)
+					handler(event, data, id)
 					event = ""
 					data = ""
+					id = ""
 				}
 				continue
 			}
@@ -335,6 +628,8 @@ func (c *StreamableHTTP) readSSE(ctx context.Context, reader io.ReadCloser, hand
 				event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
 			} else if strings.HasPrefix(line, "data:") {
 				data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			} else if strings.HasPrefix(line, "id:") {
+				id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
 			}
 		}
 	}
@@ -358,7 +653,7 @@ func (c *StreamableHTTP) SendNotification(ctx context.Context, notification mcp.
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json, text/event-stream")
 	if sessionID := c.sessionID.Load(); sessionID != "" {
-		req.Header.Set(headerKeySessionID, sessionID.(string))
+		c.setSessionID(req, sessionID.(string))
 	}
 	for k, v := range c.headers {
 		req.Header.Set(k, v)
@@ -383,6 +678,71 @@ func (c *StreamableHTTP) SendNotification(ctx context.Context, notification mcp.
 	return nil
 }
 
+// SetLogger sets the Logger receiving the transport's diagnostics. A nil
+// logger discards them. It must be called before Start.
+func (c *StreamableHTTP) SetLogger(logger Logger) {
+	c.logger = orNoopLogger(logger)
+}
+
+// SetRequestHandler sets the handler answering requests sent by the server
+// on the response stream of a request.
+func (c *StreamableHTTP) SetRequestHandler(handler RequestHandler) {
+	c.notifyMu.Lock()
+	defer c.notifyMu.Unlock()
+	c.requestHandler = handler
+}
+
+// handleRequest answers a request sent by the server with the handler set
+// by SetRequestHandler, posting the response back to the server. The
+// handler's context is canceled on Close.
+func (c *StreamableHTTP) handleRequest(request IncomingRequest) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	c.notifyMu.RLock()
+	handler := c.requestHandler
+	c.notifyMu.RUnlock()
+
+	response, err := answerRequest(ctx, handler, request)
+	if err != nil {
+		c.logger.Errorf("failed to marshal response to %s request: %v", request.Method, err)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL.String(), bytes.NewReader(response))
+	if err != nil {
+		c.logger.Errorf("failed to create response request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID := c.sessionID.Load(); sessionID != "" {
+		c.setSessionID(req, sessionID.(string))
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logger.Errorf("failed to send response to %s request: %v", request.Method, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		c.logger.Errorf("response to %s request failed with status %d: %s", request.Method, resp.StatusCode, body)
+	}
+}
+
 func (c *StreamableHTTP) SetNotificationHandler(handler func(mcp.JSONRPCNotification)) {
 	c.notifyMu.Lock()
 	defer c.notifyMu.Unlock()
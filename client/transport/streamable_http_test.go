@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,6 +19,23 @@ import (
 // a minimal Streamable HTTP server for testing purposes.
 // It returns the server URL and a function to close the server.
 func startMockStreamableHTTPServer() (string, func()) {
+	return startMockStreamableHTTPServerWithSession(
+		func(w http.ResponseWriter, sessionID string) {
+			w.Header().Set("Mcp-Session-Id", sessionID)
+		},
+		func(r *http.Request) string {
+			return r.Header.Get("Mcp-Session-Id")
+		},
+	)
+}
+
+// startMockStreamableHTTPServerWithSession is like
+// startMockStreamableHTTPServer but lets the caller choose how the session
+// ID is assigned to the client and read back from its requests.
+func startMockStreamableHTTPServerWithSession(
+	setSession func(w http.ResponseWriter, sessionID string),
+	getSession func(r *http.Request) string,
+) (string, func()) {
 	var sessionID string
 	var mu sync.Mutex
 
@@ -43,7 +61,7 @@ func startMockStreamableHTTPServer() (string, func()) {
 			mu.Lock()
 			sessionID = fmt.Sprintf("test-session-%d", time.Now().UnixNano())
 			mu.Unlock()
-			w.Header().Set("Mcp-Session-Id", sessionID)
+			setSession(w, sessionID)
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusAccepted)
 			if err := json.NewEncoder(w).Encode(map[string]any{
@@ -57,7 +75,7 @@ func startMockStreamableHTTPServer() (string, func()) {
 
 		case "debug/echo":
 			// Check session ID
-			if r.Header.Get("Mcp-Session-Id") != sessionID {
+			if getSession(r) != sessionID {
 				http.Error(w, "Invalid session ID", http.StatusNotFound)
 				return
 			}
@@ -76,7 +94,7 @@ func startMockStreamableHTTPServer() (string, func()) {
 
 		case "debug/echo_notification":
 			// Check session ID
-			if r.Header.Get("Mcp-Session-Id") != sessionID {
+			if getSession(r) != sessionID {
 				http.Error(w, "Invalid session ID", http.StatusNotFound)
 				return
 			}
@@ -101,7 +119,7 @@ func startMockStreamableHTTPServer() (string, func()) {
 
 		case "debug/echo_error_string":
 			// Check session ID
-			if r.Header.Get("Mcp-Session-Id") != sessionID {
+			if getSession(r) != sessionID {
 				http.Error(w, "Invalid session ID", http.StatusNotFound)
 				return
 			}
@@ -432,3 +450,426 @@ func TestStreamableHTTPErrors(t *testing.T) {
 	})
 
 }
+
+func TestStreamableHTTPSessionCarrier(t *testing.T) {
+	echo := func(t *testing.T, trans *StreamableHTTP) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := trans.SendRequest(ctx, JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      1,
+			Method:  "initialize",
+		}); err != nil {
+			t.Fatalf("Failed to initialize: %v", err)
+		}
+		if trans.GetSessionId() == "" {
+			t.Fatal("Expected a session ID after initialize")
+		}
+
+		// The mock answers with 404 unless the session ID comes back the
+		// same way it was handed out.
+		response, err := trans.SendRequest(ctx, JSONRPCRequest{
+			JSONRPC: "2.0",
+			ID:      2,
+			Method:  "debug/echo",
+		})
+		if err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+		if response.Error != nil {
+			t.Fatalf("Unexpected error response: %v", response.Error)
+		}
+	}
+
+	t.Run("Custom header name", func(t *testing.T) {
+		url, closeF := startMockStreamableHTTPServerWithSession(
+			func(w http.ResponseWriter, sessionID string) {
+				w.Header().Set("X-Session", sessionID)
+			},
+			func(r *http.Request) string {
+				if r.Header.Get("Mcp-Session-Id") != "" {
+					return ""
+				}
+				return r.Header.Get("X-Session")
+			},
+		)
+		defer closeF()
+
+		trans, err := NewStreamableHTTP(url, WithSessionHeaderName("X-Session"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		echo(t, trans)
+	})
+
+	t.Run("Cookie", func(t *testing.T) {
+		url, closeF := startMockStreamableHTTPServerWithSession(
+			func(w http.ResponseWriter, sessionID string) {
+				http.SetCookie(w, &http.Cookie{Name: "mcp_session", Value: sessionID})
+			},
+			func(r *http.Request) string {
+				cookie, err := r.Cookie("mcp_session")
+				if err != nil {
+					return ""
+				}
+				return cookie.Value
+			},
+		)
+		defer closeF()
+
+		trans, err := NewStreamableHTTP(url, WithSessionCookie("mcp_session"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+		echo(t, trans)
+	})
+}
+
+func TestStreamableHTTPServerRequest(t *testing.T) {
+	answered := make(chan map[string]any, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		// A message without a method is the client answering our request
+		if _, ok := message["method"]; !ok {
+			answered <- message
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		// Ask the client for sampling before answering its request
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n",
+			`{"jsonrpc":"2.0","id":"srv-1","method":"sampling/createMessage","params":{"maxTokens":1}}`)
+		responseData, _ := json.Marshal(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      message["id"],
+			"result":  map[string]any{},
+		})
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", responseData)
+	})
+	testServer := httptest.NewServer(handler)
+	defer testServer.Close()
+
+	trans, err := NewStreamableHTTP(testServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+
+	trans.SetRequestHandler(func(ctx context.Context, request IncomingRequest) (any, error) {
+		return map[string]any{"model": "test-model", "method": request.Method}, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call"}); err != nil {
+		t.Fatalf("SendRequest failed: %v", err)
+	}
+
+	select {
+	case response := <-answered:
+		if response["id"] != "srv-1" {
+			t.Errorf("Expected response id srv-1, got %v", response["id"])
+		}
+		result, _ := response["result"].(map[string]any)
+		if result["model"] != "test-model" || result["method"] != "sampling/createMessage" {
+			t.Errorf("Unexpected response result: %v", response["result"])
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for the client to answer the server request")
+	}
+}
+
+func TestStreamableHTTPStreamResume(t *testing.T) {
+	lastEventIDs := make(chan string, 10)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if r.Header.Get("Mcp-Session-Id") != "session-1" {
+				http.Error(w, "Invalid session ID", http.StatusNotFound)
+				return
+			}
+			lastEventID := r.Header.Get("Last-Event-ID")
+			lastEventIDs <- lastEventID
+
+			// The first stream drops after one event, the resumed one
+			// replays the event sent in between and stays open
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.WriteHeader(http.StatusOK)
+			switch lastEventID {
+			case "":
+				fmt.Fprintf(w, "id: 1\nevent: message\ndata: %s\n\n", `{"jsonrpc":"2.0","method":"debug/test","params":{"n":1}}`)
+			case "1":
+				fmt.Fprintf(w, "id: 2\nevent: message\ndata: %s\n\n", `{"jsonrpc":"2.0","method":"debug/test","params":{"n":2}}`)
+				w.(http.Flusher).Flush()
+				<-r.Context().Done()
+			}
+			return
+		}
+
+		var request map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Mcp-Session-Id", "session-1")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      request["id"],
+			"result":  map[string]any{},
+		})
+	})
+	testServer := httptest.NewServer(handler)
+	defer testServer.Close()
+
+	newTransport := func(t *testing.T, enabled bool) (*StreamableHTTP, chan mcp.JSONRPCNotification) {
+		trans, err := NewStreamableHTTP(testServer.URL, WithStreamResume(enabled))
+		if err != nil {
+			t.Fatal(err)
+		}
+		trans.streamRetryDelay = 10 * time.Millisecond
+		t.Cleanup(func() { trans.Close() })
+
+		notifications := make(chan mcp.JSONRPCNotification, 10)
+		trans.SetNotificationHandler(func(notification mcp.JSONRPCNotification) {
+			notifications <- notification
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "initialize"}); err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+		return trans, notifications
+	}
+
+	t.Run("Resumes after the last event", func(t *testing.T) {
+		_, notifications := newTransport(t, true)
+
+		for _, expected := range []string{"", "1"} {
+			select {
+			case lastEventID := <-lastEventIDs:
+				if lastEventID != expected {
+					t.Errorf("Expected Last-Event-ID %q, got %q", expected, lastEventID)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Timed out waiting for the GET stream")
+			}
+		}
+		for _, expected := range []float64{1, 2} {
+			select {
+			case notification := <-notifications:
+				if n := notification.Params.AdditionalFields["n"]; n != expected {
+					t.Errorf("Expected notification %v, got %v", expected, n)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Timed out waiting for notifications")
+			}
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		newTransport(t, false)
+		select {
+		case lastEventID := <-lastEventIDs:
+			t.Errorf("Expected no GET stream, got one with Last-Event-ID %q", lastEventID)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("Stops once the session is terminated", func(t *testing.T) {
+		var gets atomic.Int32
+		terminatedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				gets.Add(1)
+				http.Error(w, "Session not found", http.StatusNotFound)
+				return
+			}
+			var request map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Mcp-Session-Id", "session-1")
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"jsonrpc": "2.0",
+				"id":      request["id"],
+				"result":  map[string]any{},
+			})
+		}))
+		defer terminatedServer.Close()
+
+		trans, err := NewStreamableHTTP(terminatedServer.URL, WithStreamResume(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		trans.streamRetryDelay = 10 * time.Millisecond
+		defer trans.Close()
+
+		initialize := func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "initialize"}); err != nil {
+				t.Fatalf("SendRequest failed: %v", err)
+			}
+			// Leave time for several retries
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		initialize()
+		if got := gets.Load(); got != 1 {
+			t.Errorf("Expected 1 GET request, got %d", got)
+		}
+		if sessionID := trans.GetSessionId(); sessionID != "" {
+			t.Errorf("Expected the session ID to be cleared, got %q", sessionID)
+		}
+
+		// A new session is listened on again
+		initialize()
+		if got := gets.Load(); got != 2 {
+			t.Errorf("Expected 2 GET requests after initializing again, got %d", got)
+		}
+	})
+}
+
+func TestStreamableHTTPInitializeRetry(t *testing.T) {
+	// newServer answers the first len(statuses) POST requests with the
+	// given statuses and later ones with a result, counting all attempts
+	// and recording the session ID header they carried.
+	newServer := func(t *testing.T, statuses ...int) (string, *atomic.Int32, chan string) {
+		var attempts atomic.Int32
+		sessionIDs := make(chan string, 10)
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempt := int(attempts.Add(1))
+			var request map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+				return
+			}
+			if request["method"] == "initialize" && attempt <= len(statuses) {
+				http.Error(w, "unavailable", statuses[attempt-1])
+				return
+			}
+			sessionIDs <- r.Header.Get("Mcp-Session-Id")
+			w.Header().Set("Mcp-Session-Id", "session-1")
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"jsonrpc": "2.0",
+				"id":      request["id"],
+				"result":  map[string]any{},
+			})
+		}))
+		t.Cleanup(testServer.Close)
+		return testServer.URL, &attempts, sessionIDs
+	}
+	initialize := func(t *testing.T, trans *StreamableHTTP) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := trans.SendRequest(ctx, JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "initialize"})
+		return err
+	}
+
+	t.Run("Retries transient failures", func(t *testing.T) {
+		url, attempts, sessionIDs := newServer(t, http.StatusServiceUnavailable, http.StatusBadGateway)
+		trans, err := NewStreamableHTTP(url, WithInitializeRetry(2, 10*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+
+		if err := initialize(t, trans); err != nil {
+			t.Fatalf("Initialize failed: %v", err)
+		}
+		if got := attempts.Load(); got != 3 {
+			t.Errorf("Expected 3 attempts, got %d", got)
+		}
+		<-sessionIDs
+
+		_, err = trans.SendRequest(context.Background(), JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "ping"})
+		if err != nil {
+			t.Fatalf("SendRequest failed: %v", err)
+		}
+		if sessionID := <-sessionIDs; sessionID != "session-1" {
+			t.Errorf("Expected the session ID to be reused, got %q", sessionID)
+		}
+	})
+
+	t.Run("Gives up after the retries", func(t *testing.T) {
+		url, attempts, _ := newServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+		trans, err := NewStreamableHTTP(url, WithInitializeRetry(1, 10*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+
+		err = initialize(t, trans)
+		if !errors.Is(err, ErrInitializeFailed) {
+			t.Errorf("Expected ErrInitializeFailed, got %v", err)
+		}
+		if got := attempts.Load(); got != 2 {
+			t.Errorf("Expected 2 attempts, got %d", got)
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		url, attempts, _ := newServer(t, http.StatusUnauthorized)
+		trans, err := NewStreamableHTTP(url, WithInitializeRetry(3, 10*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+
+		err = initialize(t, trans)
+		if !errors.Is(err, ErrInitializeRejected) {
+			t.Errorf("Expected ErrInitializeRejected, got %v", err)
+		}
+		if got := attempts.Load(); got != 1 {
+			t.Errorf("Expected a rejected initialize not to be retried, got %d attempts", got)
+		}
+	})
+
+	t.Run("Unreachable server", func(t *testing.T) {
+		trans, err := NewStreamableHTTP("http://127.0.0.1:1", WithInitializeRetry(1, 10*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer trans.Close()
+
+		if err := initialize(t, trans); !errors.Is(err, ErrInitializeFailed) {
+			t.Errorf("Expected ErrInitializeFailed, got %v", err)
+		}
+	})
+}
+
+func TestStreamableHTTPRoundTripper(t *testing.T) {
+	url, closeF := startMockStreamableHTTPServer()
+	defer closeF()
+
+	rt := &recordingRoundTripper{}
+	trans, err := NewStreamableHTTP(url, WithHTTPTimeout(5*time.Second), WithHTTPRoundTripper(rt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.Close()
+	if trans.httpClient.Timeout != 5*time.Second {
+		t.Errorf("Expected the round tripper to keep the timeout, got %v", trans.httpClient.Timeout)
+	}
+
+	if _, err := trans.SendRequest(context.Background(), JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "initialize"}); err != nil {
+		t.Fatalf("SendRequest failed: %v", err)
+	}
+	if got := rt.recorded(); len(got) != 1 || got[0] != http.MethodPost {
+		t.Errorf("Expected the POST to go through the round tripper, got %v", got)
+	}
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// ErrToolError is returned by CallToolTyped when the tool reports an error
+// in its result.
+var ErrToolError = errors.New("tool returned an error")
+
+// CallToolTyped calls the named tool with args and decodes its result into
+// a T. The result's structured content is decoded if the server set any,
+// otherwise a result made of a single text content is decoded as JSON:
+//
+//	type forecast struct {
+//		Temperature float64 `json:"temperature"`
+//	}
+//	f, err := client.CallToolTyped[forecast](ctx, c, "forecast", map[string]any{"city": "Seattle"})
+//
+// A result with isError set is returned as an error wrapping ErrToolError,
+// carrying the result's text.
+func CallToolTyped[T any](ctx context.Context, c MCPClient, name string, args map[string]any) (T, error) {
+	var value T
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := c.CallTool(ctx, request)
+	if err != nil {
+		return value, err
+	}
+	if result.IsError {
+		return value, fmt.Errorf("%s: %w: %s", name, ErrToolError, strings.Join(result.TextContents(), "\n"))
+	}
+
+	var data []byte
+	if result.StructuredContent != nil {
+		if data, err = json.Marshal(result.StructuredContent); err != nil {
+			return value, fmt.Errorf("%s: failed to encode structured content: %w", name, err)
+		}
+	} else if texts := result.TextContents(); len(texts) == 1 && len(result.Content) == 1 {
+		data = []byte(texts[0])
+	} else {
+		return value, fmt.Errorf("%s: result has neither structured content nor a single text content", name)
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, fmt.Errorf("%s: failed to decode result: %w", name, err)
+	}
+	return value, nil
+}
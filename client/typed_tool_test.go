@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/zillow/mcp-go/mcp"
+	"github.com/zillow/mcp-go/server"
+)
+
+func TestCallToolTyped(t *testing.T) {
+	type forecast struct {
+		City        string  `json:"city"`
+		Temperature float64 `json:"temperature"`
+	}
+
+	mcpServer := server.NewMCPServer("test-server", "1.0.0")
+	mcpServer.AddTool(mcp.NewTool("structured"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		city, _ := request.Params.Arguments["city"].(string)
+		result := forecast{City: city, Temperature: 21.5}
+		text, _ := json.Marshal(result)
+		return mcp.NewToolResultStructured(result, string(text)), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("text"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"city": "Paris", "temperature": 18}`), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("failing"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("city not found"), nil
+	})
+	mcpServer.AddTool(mcp.NewTool("image"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultImage("a map", "aW1hZ2U=", "image/png"), nil
+	})
+
+	client, err := NewInProcessClient(mcpServer)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+	if err := client.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start client: %v", err)
+	}
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "test-client", Version: "1.0.0"}
+	if _, err := client.Initialize(context.Background(), initRequest); err != nil {
+		t.Fatalf("Failed to initialize: %v", err)
+	}
+
+	t.Run("Structured content", func(t *testing.T) {
+		result, err := CallToolTyped[forecast](context.Background(), client, "structured", map[string]any{"city": "Seattle"})
+		if err != nil {
+			t.Fatalf("CallToolTyped failed: %v", err)
+		}
+		if expected := (forecast{City: "Seattle", Temperature: 21.5}); result != expected {
+			t.Errorf("Expected %+v, got %+v", expected, result)
+		}
+	})
+
+	t.Run("JSON text content", func(t *testing.T) {
+		result, err := CallToolTyped[forecast](context.Background(), client, "text", nil)
+		if err != nil {
+			t.Fatalf("CallToolTyped failed: %v", err)
+		}
+		if expected := (forecast{City: "Paris", Temperature: 18}); result != expected {
+			t.Errorf("Expected %+v, got %+v", expected, result)
+		}
+	})
+
+	t.Run("Tool error", func(t *testing.T) {
+		_, err := CallToolTyped[forecast](context.Background(), client, "failing", nil)
+		if !errors.Is(err, ErrToolError) {
+			t.Fatalf("Expected ErrToolError, got %v", err)
+		}
+		if err.Error() != "failing: tool returned an error: city not found" {
+			t.Errorf("Unexpected error message: %v", err)
+		}
+	})
+
+	t.Run("No decodable content", func(t *testing.T) {
+		if _, err := CallToolTyped[forecast](context.Background(), client, "image", nil); err == nil {
+			t.Error("Expected an error for a result without structured or text content")
+		}
+	})
+}
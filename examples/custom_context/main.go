@@ -97,6 +97,18 @@ func handleMakeAuthenticatedRequestTool(
 	return mcp.NewToolResultText(fmt.Sprintf("%+v", resp)), nil
 }
 
+// authorizeTool only lets callers with an auth token use tools that may
+// modify their environment. Read-only tools are open to everyone.
+func authorizeTool(ctx context.Context, tool mcp.Tool, request mcp.CallToolRequest) error {
+	if hint := tool.Annotations.DestructiveHint; hint != nil && !*hint {
+		return nil
+	}
+	if token, err := tokenFromContext(ctx); err != nil || token == "" {
+		return fmt.Errorf("tool %s requires an auth token", tool.Name)
+	}
+	return nil
+}
+
 type MCPServer struct {
 	server *server.MCPServer
 }
@@ -108,6 +120,7 @@ func NewMCPServer() *MCPServer {
 		server.WithResourceCapabilities(true, true),
 		server.WithPromptCapabilities(true),
 		server.WithToolCapabilities(true),
+		server.WithToolAuthorizer(authorizeTool),
 	)
 	mcpServer.AddTool(mcp.NewTool("make_authenticated_request",
 		mcp.WithDescription("Makes an authenticated request"),
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/zillow/mcp-go/mcp"
@@ -61,13 +62,9 @@ func NewMCPServer() *server.MCPServer {
 		fmt.Printf("beforeCallTool: %v, %v\n", id, message)
 	})
 
-	mcpServer := server.NewMCPServer(
+	mcpServer := server.NewMCPServerWithAllCapabilities(
 		"example-servers/everything",
 		"1.0.0",
-		server.WithResourceCapabilities(true, true),
-		server.WithPromptCapabilities(true),
-		server.WithToolCapabilities(true),
-		server.WithLogging(),
 		server.WithHooks(hooks),
 	)
 
@@ -83,15 +80,18 @@ func NewMCPServer() *server.MCPServer {
 		handleResourceTemplate,
 	)
 
-	resources := generateResources()
-	for _, resource := range resources {
-		mcpServer.AddResource(resource, handleGeneratedResource)
-	}
+	mcpServer.AddResourceMatcherWithLister(
+		isGeneratedResource,
+		handleGeneratedResource,
+		func(ctx context.Context) ([]mcp.Resource, error) {
+			return generateResources(), nil
+		},
+	)
 
 	mcpServer.AddPrompt(mcp.NewPrompt(string(SIMPLE),
 		mcp.WithPromptDescription("A simple prompt"),
 	), handleSimplePrompt)
-	mcpServer.AddPrompt(mcp.NewPrompt(string(COMPLEX),
+	mcpServer.AddPrompt(server.NewTypedPrompt(mcp.NewPrompt(string(COMPLEX),
 		mcp.WithPromptDescription("A complex prompt"),
 		mcp.WithArgument("temperature",
 			mcp.ArgumentDescription("The temperature parameter for generation"),
@@ -101,7 +101,7 @@ func NewMCPServer() *server.MCPServer {
 			mcp.ArgumentDescription("The style to use for the response"),
 			mcp.RequiredArgument(),
 		),
-	), handleComplexPrompt)
+	), handleComplexPrompt))
 	mcpServer.AddTool(mcp.NewTool(string(ECHO),
 		mcp.WithDescription("Echoes back the input"),
 		mcp.WithString("message",
@@ -189,6 +189,17 @@ func generateResources() []mcp.Resource {
 	return resources
 }
 
+// isGeneratedResource reports whether uri is one of the resources returned by
+// generateResources.
+func isGeneratedResource(uri string) bool {
+	number, ok := strings.CutPrefix(uri, "test://static/resource/")
+	if !ok {
+		return false
+	}
+	num, err := strconv.Atoi(number)
+	return err == nil && num >= 1 && num <= 100
+}
+
 func handleReadResource(
 	ctx context.Context,
 	request mcp.ReadResourceRequest,
@@ -256,56 +267,43 @@ func handleSimplePrompt(
 	ctx context.Context,
 	request mcp.GetPromptRequest,
 ) (*mcp.GetPromptResult, error) {
-	return &mcp.GetPromptResult{
-		Description: "A simple prompt without arguments",
-		Messages: []mcp.PromptMessage{
-			{
-				Role: mcp.RoleUser,
-				Content: mcp.TextContent{
-					Type: "text",
-					Text: "This is a simple prompt without arguments.",
-				},
-			},
-		},
-	}, nil
+	return mcp.NewGetPromptResult(
+		"A simple prompt without arguments",
+		mcp.NewPromptMessages(mcp.RoleUser,
+			mcp.NewTextContent("This is a simple prompt without arguments."),
+		),
+	), nil
+}
+
+type complexPromptArgs struct {
+	Temperature float64 `json:"temperature"`
+	Style       string  `json:"style"`
 }
 
 func handleComplexPrompt(
 	ctx context.Context,
 	request mcp.GetPromptRequest,
+	args complexPromptArgs,
 ) (*mcp.GetPromptResult, error) {
-	arguments := request.Params.Arguments
-	return &mcp.GetPromptResult{
-		Description: "A complex prompt with arguments",
-		Messages: []mcp.PromptMessage{
-			{
-				Role: mcp.RoleUser,
-				Content: mcp.TextContent{
-					Type: "text",
-					Text: fmt.Sprintf(
-						"This is a complex prompt with arguments: temperature=%s, style=%s",
-						arguments["temperature"],
-						arguments["style"],
-					),
-				},
-			},
-			{
-				Role: mcp.RoleAssistant,
-				Content: mcp.TextContent{
-					Type: "text",
-					Text: "I understand. You've provided a complex prompt with temperature and style arguments. How would you like me to proceed?",
-				},
-			},
-			{
-				Role: mcp.RoleUser,
-				Content: mcp.ImageContent{
-					Type:     "image",
-					Data:     MCP_TINY_IMAGE,
-					MIMEType: "image/png",
-				},
-			},
-		},
-	}, nil
+	messages := []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(fmt.Sprintf(
+			"This is a complex prompt with arguments: temperature=%v, style=%s",
+			args.Temperature,
+			args.Style,
+		))),
+		mcp.NewPromptMessage(mcp.RoleAssistant, mcp.NewTextContent(
+			"I understand. You've provided a complex prompt with temperature and style arguments. How would you like me to proceed?",
+		)),
+	}
+	messages = append(messages, mcp.NewPromptMessages(mcp.RoleUser,
+		mcp.NewTextContent("Use this image as a reference."),
+		mcp.NewImageContent(MCP_TINY_IMAGE, "image/png"),
+	)...)
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("A complex prompt in %s style", args.Style),
+		messages,
+	), nil
 }
 
 func handleEchoTool(
@@ -383,28 +381,20 @@ func handleLongRunningOperationTool(
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
 	arguments := request.Params.Arguments
-	progressToken := request.Params.Meta.ProgressToken
 	duration, _ := arguments["duration"].(float64)
 	steps, _ := arguments["steps"].(float64)
 	stepDuration := duration / steps
-	server := server.ServerFromContext(ctx)
 
 	for i := 1; i < int(steps)+1; i++ {
 		time.Sleep(time.Duration(stepDuration * float64(time.Second)))
-		if progressToken != nil {
-			err := server.SendNotificationToClient(
-				ctx,
-				"notifications/progress",
-				map[string]any{
-					"progress":      i,
-					"total":         int(steps),
-					"progressToken": progressToken,
-					"message":       fmt.Sprintf("Server progress %v%%", int(float64(i)*100/steps)),
-				},
-			)
-			if err != nil {
-				return nil, fmt.Errorf("failed to send notification: %w", err)
-			}
+		err := server.SendProgressNotification(
+			ctx,
+			float64(i),
+			steps,
+			fmt.Sprintf("Server progress %v%%", int(float64(i)*100/steps)),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to send notification: %w", err)
 		}
 	}
 
@@ -481,6 +471,16 @@ func main() {
 
 	mcpServer := NewMCPServer()
 
+	// Tell subscribed clients that one of the generated resources changed
+	// every 10 seconds.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	next := 0
+	mcpServer.StartResourceUpdateTicker(ctx, 10*time.Second, func() []string {
+		next = next%100 + 1
+		return []string{fmt.Sprintf("test://static/resource/%d", next)}
+	})
+
 	// Only check for "sse" since stdio is the default
 	if transport == "sse" {
 		sseServer := server.NewSSEServer(mcpServer, server.WithBaseURL("http://localhost:8080"))
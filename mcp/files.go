@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NewImageContentFromFile reads the image at path and returns it as
+// ImageContent, base64-encoded, with its MIME type detected from the file's
+// contents. It fails if the file cannot be read, wrapping the error from
+// os.ReadFile, or does not hold an image.
+func NewImageContentFromFile(path string) (ImageContent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ImageContent{}, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	mimeType := detectMIMEType(path, data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return ImageContent{}, fmt.Errorf("%s is not an image: detected %s", path, mimeType)
+	}
+	return NewImageContent(base64.StdEncoding.EncodeToString(data), mimeType), nil
+}
+
+// NewResourceFromFile reads the file at path and returns it as the contents
+// of the resource with the given URI, for returning from a resource handler.
+// Text files, including JSON and XML, are returned as TextResourceContents,
+// anything else as base64-encoded BlobResourceContents, with the MIME type
+// detected from the file's contents. An empty uri defaults to the file:// URI
+// of the file. It fails if the file cannot be read, wrapping the error from
+// os.ReadFile.
+func NewResourceFromFile(uri, path string) (ResourceContents, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resource: %w", err)
+	}
+
+	if uri == "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve resource path: %w", err)
+		}
+		uri = "file://" + filepath.ToSlash(absPath)
+	}
+
+	mimeType := detectMIMEType(path, data)
+	if isTextMIMEType(mimeType) {
+		return TextResourceContents{URI: uri, MIMEType: mimeType, Text: string(data)}, nil
+	}
+	return BlobResourceContents{
+		URI:      uri,
+		MIMEType: mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
+
+// detectMIMEType returns the MIME type of a file's contents, without
+// parameters such as the charset. Types http.DetectContentType does not
+// recognize, e.g. SVG images, fall back to the type of the file extension,
+// as do XML documents, which are told apart by their extension only.
+func detectMIMEType(path string, data []byte) string {
+	detected := http.DetectContentType(data)
+	if detected == "application/octet-stream" ||
+		strings.HasPrefix(detected, "text/plain") ||
+		strings.HasPrefix(detected, "text/xml") {
+		if byExtension := mime.TypeByExtension(filepath.Ext(path)); byExtension != "" {
+			detected = byExtension
+		}
+	}
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		return detected
+	}
+	return mediaType
+}
+
+// isTextMIMEType reports whether contents of the MIME type are text.
+func isTextMIMEType(mimeType string) bool {
+	switch {
+	case strings.HasPrefix(mimeType, "text/"),
+		mimeType == "application/json",
+		mimeType == "application/xml",
+		strings.HasSuffix(mimeType, "+json"),
+		strings.HasSuffix(mimeType, "+xml"):
+		return true
+	}
+	return false
+}
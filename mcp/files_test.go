@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewImageContentFromFile(t *testing.T) {
+	png, err := os.ReadFile("testdata/pixel.png")
+	require.NoError(t, err)
+
+	content, err := NewImageContentFromFile("testdata/pixel.png")
+	require.NoError(t, err)
+	assert.Equal(t, "image", content.Type)
+	assert.Equal(t, "image/png", content.MIMEType)
+	decoded, err := base64.StdEncoding.DecodeString(content.Data)
+	require.NoError(t, err)
+	assert.Equal(t, png, decoded, "image data must round-trip byte for byte")
+
+	_, err = NewImageContentFromFile("testdata/missing.png")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, err = NewImageContentFromFile("testdata/hello.txt")
+	assert.ErrorContains(t, err, "not an image")
+
+	// Sniffed as text/xml, recognized by its extension
+	svgPath := filepath.Join(t.TempDir(), "logo.svg")
+	svg := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<svg xmlns="http://www.w3.org/2000/svg" width="1" height="1"/>`
+	require.NoError(t, os.WriteFile(svgPath, []byte(svg), 0o600))
+	content, err = NewImageContentFromFile(svgPath)
+	require.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", content.MIMEType)
+}
+
+func TestNewResourceFromFile(t *testing.T) {
+	t.Run("Text file", func(t *testing.T) {
+		contents, err := NewResourceFromFile("docs://hello", "testdata/hello.txt")
+		require.NoError(t, err)
+		assert.Equal(t, TextResourceContents{
+			URI:      "docs://hello",
+			MIMEType: "text/plain",
+			Text:     "Hello from a file.\n",
+		}, contents)
+	})
+
+	t.Run("Binary file", func(t *testing.T) {
+		png, err := os.ReadFile("testdata/pixel.png")
+		require.NoError(t, err)
+
+		contents, err := NewResourceFromFile("", "testdata/pixel.png")
+		require.NoError(t, err)
+		blob, ok := contents.(BlobResourceContents)
+		require.True(t, ok, "expected blob contents, got %T", contents)
+
+		absPath, err := filepath.Abs("testdata/pixel.png")
+		require.NoError(t, err)
+		assert.Equal(t, "file://"+filepath.ToSlash(absPath), blob.URI)
+		assert.Equal(t, "image/png", blob.MIMEType)
+		decoded, err := base64.StdEncoding.DecodeString(blob.Blob)
+		require.NoError(t, err)
+		assert.Equal(t, png, decoded)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := NewResourceFromFile("docs://missing", "testdata/missing.txt")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+}
@@ -24,6 +24,8 @@ type GetPromptRequest struct {
 		Name string `json:"name"`
 		// Arguments to use for templating the prompt.
 		Arguments map[string]string `json:"arguments,omitempty"`
+		// Metadata attached by the caller under "_meta".
+		Meta *Meta `json:"_meta,omitempty"`
 	} `json:"params"`
 }
 
@@ -77,6 +79,11 @@ const (
 	RoleAssistant Role = "assistant"
 )
 
+// IsValid reports whether r is one of the roles defined by the protocol.
+func (r Role) IsValid() bool {
+	return r == RoleUser || r == RoleAssistant
+}
+
 // PromptMessage describes a message returned as part of a prompt.
 //
 // This is similar to `SamplingMessage`, but also supports the embedding of
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPromptMessages(t *testing.T) {
+	text := NewTextContent("Describe this")
+	image := NewImageContent("aW1hZ2U=", "image/png")
+
+	result := NewGetPromptResult("A prompt", NewPromptMessages(RoleUser, text, image))
+	assert.Equal(t, "A prompt", result.Description)
+	assert.Equal(t, []PromptMessage{
+		{Role: RoleUser, Content: text},
+		{Role: RoleUser, Content: image},
+	}, result.Messages)
+
+	assert.Empty(t, NewPromptMessages(RoleAssistant))
+}
+
+func TestRole_IsValid(t *testing.T) {
+	assert.True(t, RoleUser.IsValid())
+	assert.True(t, RoleAssistant.IsValid())
+	assert.False(t, Role("system").IsValid())
+	assert.False(t, Role("").IsValid())
+}
+
+func TestMultiModalPromptMarshalling(t *testing.T) {
+	result := NewGetPromptResult("Describe an image", []PromptMessage{
+		NewPromptMessage(RoleUser, NewTextContent("What is in this image?")),
+		NewPromptMessage(RoleUser, NewImageContent("aW1hZ2U=", "image/png")),
+		NewPromptMessage(RoleAssistant, NewAudioContent("YXVkaW8=", "audio/wav")),
+	})
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"description": "Describe an image",
+		"messages": [
+			{"role": "user", "content": {"type": "text", "text": "What is in this image?"}},
+			{"role": "user", "content": {"type": "image", "data": "aW1hZ2U=", "mimeType": "image/png"}},
+			{"role": "assistant", "content": {"type": "audio", "data": "YXVkaW8=", "mimeType": "audio/wav"}}
+		]
+	}`, string(data))
+}
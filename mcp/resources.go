@@ -1,6 +1,11 @@
 package mcp
 
-import "github.com/yosida95/uritemplate/v3"
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/yosida95/uritemplate/v3"
+)
 
 // ResourceOption is a function that configures a Resource.
 // It provides a flexible way to set various properties of a Resource using the functional options pattern.
@@ -97,3 +102,42 @@ func WithTemplateAnnotations(audience []Role, priority float64) ResourceTemplate
 		t.Annotations.Priority = priority
 	}
 }
+
+// ApplyResourceRange restricts blob contents to the requested byte range.
+// Text contents are returned unchanged. A nil range returns the contents as is.
+// An offset past the end of a blob yields an empty blob.
+func ApplyResourceRange(contents []ResourceContents, r *ResourceRange) ([]ResourceContents, error) {
+	if r == nil {
+		return contents, nil
+	}
+	if r.Offset < 0 || r.Length < 0 {
+		return nil, fmt.Errorf("invalid resource range: offset %d, length %d", r.Offset, r.Length)
+	}
+
+	result := make([]ResourceContents, 0, len(contents))
+	for _, content := range contents {
+		blob, ok := content.(BlobResourceContents)
+		if !ok {
+			result = append(result, content)
+			continue
+		}
+
+		data, err := base64.StdEncoding.DecodeString(blob.Blob)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode blob for %s: %w", blob.URI, err)
+		}
+
+		start := min(r.Offset, int64(len(data)))
+		end := int64(len(data))
+		// Compare against the remaining length rather than adding, as
+		// start+r.Length overflows for lengths close to math.MaxInt64
+		if r.Length > 0 && r.Length < end-start {
+			end = start + r.Length
+		}
+
+		blob.Blob = base64.StdEncoding.EncodeToString(data[start:end])
+		result = append(result, blob)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyResourceRange(t *testing.T) {
+	blob := BlobResourceContents{
+		URI:  "test://blob",
+		Blob: base64.StdEncoding.EncodeToString([]byte("0123456789")),
+	}
+	text := TextResourceContents{URI: "test://text", Text: "unchanged"}
+	apply := func(t *testing.T, r *ResourceRange) []ResourceContents {
+		contents, err := ApplyResourceRange([]ResourceContents{blob, text}, r)
+		require.NoError(t, err)
+		require.Len(t, contents, 2)
+		assert.Equal(t, text, contents[1])
+		return contents
+	}
+	decoded := func(t *testing.T, contents []ResourceContents) string {
+		data, err := base64.StdEncoding.DecodeString(contents[0].(BlobResourceContents).Blob)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	tests := []struct {
+		name     string
+		r        *ResourceRange
+		expected string
+	}{
+		{"No range", nil, "0123456789"},
+		{"Offset", &ResourceRange{Offset: 7}, "789"},
+		{"Offset and length", &ResourceRange{Offset: 2, Length: 3}, "234"},
+		{"Length past the end", &ResourceRange{Offset: 8, Length: 5}, "89"},
+		{"Offset past the end", &ResourceRange{Offset: 20, Length: 5}, ""},
+		{"Largest length", &ResourceRange{Offset: 1, Length: math.MaxInt64}, "123456789"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, decoded(t, apply(t, tt.r)))
+		})
+	}
+
+	_, err := ApplyResourceRange([]ResourceContents{blob}, &ResourceRange{Offset: -1})
+	assert.Error(t, err)
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 )
 
 var errToolSchemaConflict = errors.New("provide either InputSchema or RawInputSchema, not both")
@@ -38,6 +40,44 @@ type CallToolResult struct {
 	//
 	// If not set, this is assumed to be false (the call was successful).
 	IsError bool `json:"isError,omitempty"`
+	// An optional JSON object holding the result in structured form, for
+	// clients that know the shape of the tool's output. Tools setting it
+	// should also return it serialized in a text content block, for clients
+	// that do not.
+	StructuredContent any `json:"structuredContent,omitempty"`
+}
+
+// TextContents returns the text of every TextContent in the result, in order.
+func (r CallToolResult) TextContents() []string {
+	var texts []string
+	for _, content := range r.Content {
+		if text, ok := content.(TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return texts
+}
+
+// FirstText returns the text of the first TextContent in the result, and
+// false if there is none.
+func (r CallToolResult) FirstText() (string, bool) {
+	for _, content := range r.Content {
+		if text, ok := content.(TextContent); ok {
+			return text.Text, true
+		}
+	}
+	return "", false
+}
+
+// Images returns every ImageContent in the result, in order.
+func (r CallToolResult) Images() []ImageContent {
+	var images []ImageContent
+	for _, content := range r.Content {
+		if image, ok := content.(ImageContent); ok {
+			images = append(images, image)
+		}
+	}
+	return images
 }
 
 // CallToolRequest is used by the client to invoke a tool provided by the server.
@@ -46,18 +86,41 @@ type CallToolRequest struct {
 	Params struct {
 		Name      string         `json:"name"`
 		Arguments map[string]any `json:"arguments,omitempty"`
-		Meta      *struct {
-			// If specified, the caller is requesting out-of-band progress
-			// notifications for this request (as represented by
-			// notifications/progress). The value of this parameter is an
-			// opaque token that will be attached to any subsequent
-			// notifications. The receiver is not obligated to provide these
-			// notifications.
-			ProgressToken ProgressToken `json:"progressToken,omitempty"`
-		} `json:"_meta,omitempty"`
+		Meta      *Meta          `json:"_meta,omitempty"`
 	} `json:"params"`
 }
 
+// BindArguments decodes the request arguments into target, which must be a
+// pointer to a struct or another value json.Unmarshal can decode into.
+// Field names follow the usual encoding/json rules, so struct tags may be
+// used to map argument names.
+//
+// Usage:
+//
+//	var args struct {
+//	    A float64 `json:"a"`
+//	    B float64 `json:"b"`
+//	}
+//	if err := request.BindArguments(&args); err != nil {
+//	    return mcp.NewToolResultError(err.Error()), nil
+//	}
+func (r CallToolRequest) BindArguments(target any) error {
+	data, err := json.Marshal(r.Params.Arguments)
+	if err != nil {
+		return fmt.Errorf("failed to marshal arguments for tool %s: %w", r.Params.Name, err)
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return fmt.Errorf("invalid argument %q for tool %s: %w", typeErr.Field, r.Params.Name, err)
+		}
+		return fmt.Errorf("failed to bind arguments for tool %s: %w", r.Params.Name, err)
+	}
+
+	return nil
+}
+
 // ToolListChangedNotification is an optional notification from the server to
 // the client, informing it that the list of tools it offers has changed. This may
 // be issued by servers without any previous subscription from the client.
@@ -84,6 +147,59 @@ func (t Tool) GetName() string {
 	return t.Name
 }
 
+// MaxToolNameLength is the maximum length of a valid tool name.
+const MaxToolNameLength = 128
+
+// IsValidToolName reports whether name is a valid tool name: between 1 and
+// MaxToolNameLength characters, each an ASCII letter, digit, underscore, dot
+// or hyphen. Names with other characters, such as spaces, can break clients
+// that use tool names as identifiers.
+func IsValidToolName(name string) bool {
+	if len(name) == 0 || len(name) > MaxToolNameLength {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isToolNameChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeToolName turns name into a valid tool name by replacing every
+// character IsValidToolName does not allow with an underscore and cutting it
+// to MaxToolNameLength characters. An empty name stays empty.
+func NormalizeToolName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if b.Len() == MaxToolNameLength {
+			break
+		}
+		if r < utf8.RuneSelf && isToolNameChar(byte(r)) {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// isToolNameChar reports whether c may appear in a tool name.
+func isToolNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '.' || c == '-'
+}
+
+// ValidateSchema reports whether the tool's input schema can be marshaled,
+// i.e. that InputSchema and RawInputSchema are not both set. It returns the
+// same error MarshalJSON would.
+func (t Tool) ValidateSchema() error {
+	if t.RawInputSchema != nil && t.InputSchema.Type != "" {
+		return fmt.Errorf("tool %s has both InputSchema and RawInputSchema set: %w", t.Name, errToolSchemaConflict)
+	}
+	return nil
+}
+
 // MarshalJSON implements the json.Marshaler interface for Tool.
 // It handles marshaling either InputSchema or RawInputSchema based on which is set.
 func (t Tool) MarshalJSON() ([]byte, error) {
@@ -98,8 +214,8 @@ func (t Tool) MarshalJSON() ([]byte, error) {
 
 	// Determine which schema to use
 	if t.RawInputSchema != nil {
-		if t.InputSchema.Type != "" {
-			return nil, fmt.Errorf("tool %s has both InputSchema and RawInputSchema set: %w", t.Name, errToolSchemaConflict)
+		if err := t.ValidateSchema(); err != nil {
+			return nil, err
 		}
 		m["inputSchema"] = t.RawInputSchema
 	} else {
@@ -116,6 +232,9 @@ type ToolInputSchema struct {
 	Type       string         `json:"type"`
 	Properties map[string]any `json:"properties,omitempty"`
 	Required   []string       `json:"required,omitempty"`
+	// Defs holds shared schema definitions that properties can reference
+	// with Ref.
+	Defs map[string]any `json:"$defs,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface for ToolInputSchema.
@@ -132,6 +251,10 @@ func (tis ToolInputSchema) MarshalJSON() ([]byte, error) {
 		m["required"] = tis.Required
 	}
 
+	if len(tis.Defs) > 0 {
+		m["$defs"] = tis.Defs
+	}
+
 	return json.Marshal(m)
 }
 
@@ -146,8 +269,21 @@ type ToolAnnotation struct {
 	IdempotentHint *bool `json:"idempotentHint,omitempty"`
 	// If true, tool interacts with external entities
 	OpenWorldHint *bool `json:"openWorldHint,omitempty"`
+	// How long a call is expected to take, for clients scheduling tool calls
+	LatencyHint ToolHintLevel `json:"latencyHint,omitempty"`
+	// How expensive a call is expected to be, for clients warning before calls
+	CostHint ToolHintLevel `json:"costHint,omitempty"`
 }
 
+// ToolHintLevel is the advisory level of a tool's latency or cost hint.
+type ToolHintLevel string
+
+const (
+	ToolHintLow    ToolHintLevel = "low"
+	ToolHintMedium ToolHintLevel = "medium"
+	ToolHintHigh   ToolHintLevel = "high"
+)
+
 // ToolOption is a function that configures a Tool.
 // It provides a flexible way to set various properties of a Tool using the functional options pattern.
 type ToolOption func(*Tool)
@@ -259,6 +395,22 @@ func WithOpenWorldHintAnnotation(value bool) ToolOption {
 	}
 }
 
+// WithLatencyHint sets the LatencyHint field of the Tool's Annotations.
+// It is advisory, indicating how long calls to the tool are expected to take.
+func WithLatencyHint(level ToolHintLevel) ToolOption {
+	return func(t *Tool) {
+		t.Annotations.LatencyHint = level
+	}
+}
+
+// WithCostHint sets the CostHint field of the Tool's Annotations.
+// It is advisory, indicating how expensive calls to the tool are expected to be.
+func WithCostHint(level ToolHintLevel) ToolOption {
+	return func(t *Tool) {
+		t.Annotations.CostHint = level
+	}
+}
+
 //
 // Common Property Options
 //
@@ -563,6 +715,33 @@ func MaxItems(max int) PropertyOption {
 	}
 }
 
+//
+// Schema Definitions
+//
+
+// WithSchemaDefs adds shared schema definitions to the Tool's input schema
+// under "$defs". Properties refer to them with Ref, which also allows
+// recursive structures.
+func WithSchemaDefs(defs map[string]any) ToolOption {
+	return func(t *Tool) {
+		if t.InputSchema.Defs == nil {
+			t.InputSchema.Defs = make(map[string]any, len(defs))
+		}
+		for name, schema := range defs {
+			t.InputSchema.Defs[name] = schema
+		}
+	}
+}
+
+// Ref returns a schema referencing the definition with the given name added
+// with WithSchemaDefs, for use wherever a schema is expected, e.g. in Items
+// or Properties.
+func Ref(name string) map[string]any {
+	return map[string]any{
+		"$ref": "#/$defs/" + name,
+	}
+}
+
 // UniqueItems specifies whether array items must be unique
 func UniqueItems(unique bool) PropertyOption {
 	return func(schema map[string]any) {
@@ -3,6 +3,7 @@ package mcp
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -308,3 +309,186 @@ func TestParseToolCallToolRequest(t *testing.T) {
 	t.Logf("param15 type: %T,value:%v", param15, param15)
 
 }
+
+func TestCallToolRequest_BindArguments(t *testing.T) {
+	type AddArgs struct {
+		A float64 `json:"a"`
+		B float64 `json:"b"`
+	}
+
+	request := CallToolRequest{}
+	request.Params.Name = "add"
+	request.Params.Arguments = map[string]any{
+		"a": 1.5,
+		"b": 2,
+	}
+
+	var args AddArgs
+	err := request.BindArguments(&args)
+	assert.NoError(t, err)
+	assert.Equal(t, AddArgs{A: 1.5, B: 2}, args)
+
+	// Type mismatch returns a descriptive error
+	request.Params.Arguments["b"] = "two"
+	err = request.BindArguments(&args)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid argument "b" for tool add`)
+
+	var typeErr *json.UnmarshalTypeError
+	assert.ErrorAs(t, err, &typeErr)
+}
+
+func TestIsValidToolName(t *testing.T) {
+	for _, name := range []string{"search", "get_weather", "files.read", "v2-lookup", "A1", strings.Repeat("a", MaxToolNameLength)} {
+		assert.True(t, IsValidToolName(name), name)
+	}
+	for _, name := range []string{"", "search docs", "files/read", "naïve", "tool!", strings.Repeat("a", MaxToolNameLength+1)} {
+		assert.False(t, IsValidToolName(name), name)
+	}
+}
+
+func TestNormalizeToolName(t *testing.T) {
+	assert.Equal(t, "get_weather", NormalizeToolName("get_weather"))
+	assert.Equal(t, "search_docs", NormalizeToolName("search docs"))
+	assert.Equal(t, "na_ve", NormalizeToolName("naïve"))
+	assert.Equal(t, "", NormalizeToolName(""))
+	assert.Equal(t, strings.Repeat("a", MaxToolNameLength), NormalizeToolName(strings.Repeat("a", MaxToolNameLength+5)))
+	assert.True(t, IsValidToolName(NormalizeToolName("files/read (v2)")))
+}
+
+func TestCallToolResult_ContentAccessors(t *testing.T) {
+	result := CallToolResult{
+		Content: []Content{
+			NewImageContent("aW1hZ2U=", "image/png"),
+			NewTextContent("first"),
+			NewAudioContent("YXVkaW8=", "audio/wav"),
+			NewTextContent("second"),
+			NewImageContent("b3RoZXI=", "image/jpeg"),
+		},
+	}
+
+	assert.Equal(t, []string{"first", "second"}, result.TextContents())
+	text, ok := result.FirstText()
+	assert.True(t, ok)
+	assert.Equal(t, "first", text)
+	images := result.Images()
+	assert.Len(t, images, 2)
+	assert.Equal(t, "image/png", images[0].MIMEType)
+	assert.Equal(t, "image/jpeg", images[1].MIMEType)
+
+	empty := NewToolResultImage("", "aW1hZ2U=", "image/png")
+	empty.Content = empty.Content[1:]
+	assert.Empty(t, empty.TextContents())
+	_, ok = empty.FirstText()
+	assert.False(t, ok)
+	assert.Len(t, empty.Images(), 1)
+}
+
+func TestCallToolRequest_Meta(t *testing.T) {
+	var request CallToolRequest
+	err := json.Unmarshal([]byte(`{
+		"method": "tools/call",
+		"params": {
+			"name": "long-task",
+			"_meta": {"progressToken": "token-1", "traceId": "abc"}
+		}
+	}`), &request)
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", request.Params.Meta.GetProgressToken())
+	assert.Equal(t, map[string]any{"traceId": "abc"}, request.Params.Meta.AdditionalFields)
+
+	data, err := json.Marshal(request.Params)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "long-task",
+		"_meta": {"progressToken": "token-1", "traceId": "abc"}
+	}`, string(data))
+
+	// Without _meta there is no progress token
+	request = CallToolRequest{}
+	assert.Nil(t, request.Params.Meta.GetProgressToken())
+}
+
+func TestToolWithSchemaDefs(t *testing.T) {
+	tool := NewTool("tree-tool",
+		WithDescription("A tool taking a tree of nodes"),
+		WithSchemaDefs(map[string]any{
+			"node": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"value": map[string]any{"type": "string"},
+					"children": map[string]any{
+						"type":  "array",
+						"items": Ref("node"),
+					},
+				},
+			},
+		}),
+		WithArray("roots", Required(), Items(Ref("node"))),
+	)
+
+	data, err := json.Marshal(tool)
+	assert.NoError(t, err)
+
+	var result map[string]any
+	err = json.Unmarshal(data, &result)
+	assert.NoError(t, err)
+
+	schema, ok := result["inputSchema"].(map[string]any)
+	assert.True(t, ok)
+	assert.Equal(t, []any{"roots"}, schema["required"])
+
+	roots := schema["properties"].(map[string]any)["roots"].(map[string]any)
+	assert.Equal(t, map[string]any{"$ref": "#/$defs/node"}, roots["items"])
+
+	defs, ok := schema["$defs"].(map[string]any)
+	assert.True(t, ok, "inputSchema should include the $defs block")
+	node := defs["node"].(map[string]any)
+	children := node["properties"].(map[string]any)["children"].(map[string]any)
+	assert.Equal(t, map[string]any{"$ref": "#/$defs/node"}, children["items"])
+
+	// The schema round trips through Tool unmarshaling
+	var decoded Tool
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Contains(t, decoded.InputSchema.Defs, "node")
+}
+
+func TestToolWithLatencyAndCostHints(t *testing.T) {
+	tool := NewTool("search",
+		WithDescription("Searches the web"),
+		WithLatencyHint(ToolHintHigh),
+		WithCostHint(ToolHintMedium),
+	)
+
+	data, err := json.Marshal(tool)
+	assert.NoError(t, err)
+
+	var result map[string]any
+	assert.NoError(t, json.Unmarshal(data, &result))
+	annotations := result["annotations"].(map[string]any)
+	assert.Equal(t, "high", annotations["latencyHint"])
+	assert.Equal(t, "medium", annotations["costHint"])
+
+	var decoded Tool
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, ToolHintHigh, decoded.Annotations.LatencyHint)
+	assert.Equal(t, ToolHintMedium, decoded.Annotations.CostHint)
+
+	// The hints are omitted when unset
+	data, err = json.Marshal(NewTool("echo"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "latencyHint")
+	assert.NotContains(t, string(data), "costHint")
+}
+
+func TestLoggingLevel_IsValid(t *testing.T) {
+	for _, level := range []LoggingLevel{
+		LoggingLevelDebug, LoggingLevelInfo, LoggingLevelNotice, LoggingLevelWarning,
+		LoggingLevelError, LoggingLevelCritical, LoggingLevelAlert, LoggingLevelEmergency,
+	} {
+		assert.True(t, level.IsValid(), level)
+	}
+	for _, level := range []LoggingLevel{"", "verbose", "Info"} {
+		assert.False(t, level.IsValid(), level)
+	}
+}
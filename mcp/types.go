@@ -4,6 +4,7 @@ package mcp
 
 import (
 	"encoding/json"
+	"slices"
 
 	"github.com/yosida95/uritemplate/v3"
 )
@@ -31,6 +32,14 @@ const (
 	// https://modelcontextprotocol.io/specification/2024-11-05/server/resources/
 	MethodResourcesRead MCPMethod = "resources/read"
 
+	// MethodResourcesSubscribe requests resources/updated notifications for a resource.
+	// https://modelcontextprotocol.io/specification/2024-11-05/server/resources/
+	MethodResourcesSubscribe MCPMethod = "resources/subscribe"
+
+	// MethodResourcesUnsubscribe cancels a previous resources/subscribe request.
+	// https://modelcontextprotocol.io/specification/2024-11-05/server/resources/
+	MethodResourcesUnsubscribe MCPMethod = "resources/unsubscribe"
+
 	// MethodPromptsList lists all available prompt templates.
 	// https://modelcontextprotocol.io/specification/2024-11-05/server/prompts/
 	MethodPromptsList MCPMethod = "prompts/list"
@@ -47,6 +56,25 @@ const (
 	// https://modelcontextprotocol.io/specification/2024-11-05/server/tools/
 	MethodToolsCall MCPMethod = "tools/call"
 
+	// MethodCompletionComplete requests completion options for a prompt or
+	// resource template argument.
+	// https://modelcontextprotocol.io/specification/2025-03-26/server/utilities/completion
+	MethodCompletionComplete MCPMethod = "completion/complete"
+
+	// MethodLoggingSetLevel sets the minimum level of the log messages the
+	// server sends to the client.
+	// https://modelcontextprotocol.io/specification/2024-11-05/server/utilities/logging/
+	MethodLoggingSetLevel MCPMethod = "logging/setLevel"
+
+	// MethodSamplingCreateMessage is sent by the server to request an LLM
+	// completion from the client.
+	// https://modelcontextprotocol.io/specification/2024-11-05/client/sampling/
+	MethodSamplingCreateMessage MCPMethod = "sampling/createMessage"
+
+	// MethodRootsList is sent by the server to list the client's roots.
+	// https://modelcontextprotocol.io/specification/2024-11-05/client/roots/
+	MethodRootsList MCPMethod = "roots/list"
+
 	// MethodNotificationResourcesListChanged notifies when the list of available resources changes.
 	// https://modelcontextprotocol.io/specification/2025-03-26/server/resources#list-changed-notification
 	MethodNotificationResourcesListChanged = "notifications/resources/list_changed"
@@ -60,6 +88,10 @@ const (
 	// MethodNotificationToolsListChanged notifies when the list of available tools changes.
 	// https://spec.modelcontextprotocol.io/specification/2024-11-05/server/tools/list_changed/
 	MethodNotificationToolsListChanged = "notifications/tools/list_changed"
+
+	// MethodNotificationProgress reports the progress of a long-running request.
+	// https://modelcontextprotocol.io/specification/2025-03-26/basic/utilities/progress
+	MethodNotificationProgress = "notifications/progress"
 )
 
 type URITemplate struct {
@@ -91,6 +123,18 @@ type JSONRPCMessage any
 // LATEST_PROTOCOL_VERSION is the most recent version of the MCP protocol.
 const LATEST_PROTOCOL_VERSION = "2024-11-05"
 
+// validProtocolVersions lists the versions of the MCP protocol supported by
+// this package, the latest first.
+var validProtocolVersions = []string{
+	LATEST_PROTOCOL_VERSION,
+}
+
+// ValidProtocolVersions returns the versions of the MCP protocol supported by
+// this package, the latest first. The slice is a copy the caller may modify.
+func ValidProtocolVersions() []string {
+	return slices.Clone(validProtocolVersions)
+}
+
 // JSONRPC_VERSION is the version of JSON-RPC used by MCP.
 const JSONRPC_VERSION = "2.0"
 
@@ -100,18 +144,63 @@ type ProgressToken any
 // Cursor is an opaque token used to represent a cursor for pagination.
 type Cursor string
 
+// Meta is the metadata a sender attaches to request params under the
+// reserved "_meta" key.
+type Meta struct {
+	// If specified, the caller is requesting out-of-band progress
+	// notifications for this request (as represented by
+	// notifications/progress). The value of this parameter is an
+	// opaque token that will be attached to any subsequent
+	// notifications. The receiver is not obligated to provide these
+	// notifications.
+	ProgressToken ProgressToken
+
+	// AdditionalFields holds all other "_meta" entries.
+	AdditionalFields map[string]any
+}
+
+// GetProgressToken returns the progress token of the request, or nil if the
+// caller did not ask for progress notifications. It is safe to call on a nil
+// Meta.
+func (m *Meta) GetProgressToken() ProgressToken {
+	if m == nil {
+		return nil
+	}
+	return m.ProgressToken
+}
+
+// MarshalJSON implements custom JSON marshaling
+func (m Meta) MarshalJSON() ([]byte, error) {
+	raw := make(map[string]any, len(m.AdditionalFields)+1)
+	for k, v := range m.AdditionalFields {
+		raw[k] = v
+	}
+	if m.ProgressToken != nil {
+		raw["progressToken"] = m.ProgressToken
+	}
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling
+func (m *Meta) UnmarshalJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	m.ProgressToken = raw["progressToken"]
+	delete(raw, "progressToken")
+	m.AdditionalFields = nil
+	if len(raw) > 0 {
+		m.AdditionalFields = raw
+	}
+	return nil
+}
+
 type Request struct {
 	Method string `json:"method"`
 	Params struct {
-		Meta *struct {
-			// If specified, the caller is requesting out-of-band progress
-			// notifications for this request (as represented by
-			// notifications/progress). The value of this parameter is an
-			// opaque token that will be attached to any subsequent
-			// notifications. The receiver is not obligated to provide these
-			// notifications.
-			ProgressToken ProgressToken `json:"progressToken,omitempty"`
-		} `json:"_meta,omitempty"`
+		Meta *Meta `json:"_meta,omitempty"`
 	} `json:"params,omitempty"`
 }
 
@@ -231,6 +320,22 @@ type JSONRPCError struct {
 	} `json:"error"`
 }
 
+// JSONRPCErrorError is the error returned by client calls answered with a
+// JSON-RPC error response, carrying the error details sent by the server.
+type JSONRPCErrorError struct {
+	// The error type that occurred.
+	Code int
+	// A short description of the error.
+	Message string
+	// Additional information about the error, in its wire form. Empty if the
+	// server sent none.
+	Data json.RawMessage
+}
+
+func (e *JSONRPCErrorError) Error() string {
+	return e.Message
+}
+
 // Standard JSON-RPC error codes
 const (
 	PARSE_ERROR      = -32700
@@ -242,7 +347,9 @@ const (
 
 // MCP error codes
 const (
+	UNAUTHORIZED       = -32001
 	RESOURCE_NOT_FOUND = -32002
+	FORBIDDEN          = -32003
 )
 
 /* Empty result */
@@ -290,6 +397,8 @@ type InitializeRequest struct {
 		ProtocolVersion string             `json:"protocolVersion"`
 		Capabilities    ClientCapabilities `json:"capabilities"`
 		ClientInfo      Implementation     `json:"clientInfo"`
+		// Metadata attached by the caller under "_meta".
+		Meta *Meta `json:"_meta,omitempty"`
 	} `json:"params"`
 }
 
@@ -332,12 +441,22 @@ type ClientCapabilities struct {
 	Sampling *struct{} `json:"sampling,omitempty"`
 }
 
+// HasExperimental reports whether the client declared the experimental,
+// non-standard capability with the given key.
+func (c ClientCapabilities) HasExperimental(key string) bool {
+	_, ok := c.Experimental[key]
+	return ok
+}
+
 // ServerCapabilities represents capabilities that a server may support. Known
 // capabilities are defined here, in this schema, but this is not a closed set: any
 // server can define its own, additional capabilities.
 type ServerCapabilities struct {
 	// Experimental, non-standard capabilities that the server supports.
 	Experimental map[string]any `json:"experimental,omitempty"`
+	// Present if the server offers autocompletion suggestions for prompt and
+	// resource template arguments.
+	Completions *struct{} `json:"completions,omitempty"`
 	// Present if the server supports sending log messages to the client.
 	Logging *struct{} `json:"logging,omitempty"`
 	// Present if the server offers any prompt templates.
@@ -360,6 +479,13 @@ type ServerCapabilities struct {
 	} `json:"tools,omitempty"`
 }
 
+// HasExperimental reports whether the server declared the experimental,
+// non-standard capability with the given key.
+func (c ServerCapabilities) HasExperimental(key string) bool {
+	_, ok := c.Experimental[key]
+	return ok
+}
+
 // Implementation describes the name and version of an MCP implementation.
 type Implementation struct {
 	Name    string `json:"name"`
@@ -404,6 +530,8 @@ type PaginatedRequest struct {
 		// An opaque token representing the current pagination position.
 		// If provided, the server should return results starting after this cursor.
 		Cursor Cursor `json:"cursor,omitempty"`
+		// Metadata attached by the caller under "_meta".
+		Meta *Meta `json:"_meta,omitempty"`
 	} `json:"params,omitempty"`
 }
 
@@ -453,9 +581,23 @@ type ReadResourceRequest struct {
 		URI string `json:"uri"`
 		// Arguments to pass to the resource handler
 		Arguments map[string]any `json:"arguments,omitempty"`
+		// Range optionally restricts the read to a byte range of the
+		// resource. Handlers that support range reads should honor it, see
+		// ApplyResourceRange.
+		Range *ResourceRange `json:"range,omitempty"`
+		// Metadata attached by the caller under "_meta".
+		Meta *Meta `json:"_meta,omitempty"`
 	} `json:"params"`
 }
 
+// ResourceRange selects a byte range of a resource's contents.
+type ResourceRange struct {
+	// The zero-based byte offset to start reading from.
+	Offset int64 `json:"offset"`
+	// The maximum number of bytes to read. Zero means read until the end.
+	Length int64 `json:"length,omitempty"`
+}
+
 // ReadResourceResult is the server's response to a resources/read request
 // from the client.
 type ReadResourceResult struct {
@@ -479,6 +621,8 @@ type SubscribeRequest struct {
 		// The URI of the resource to subscribe to. The URI can use any protocol; it
 		// is up to the server how to interpret it.
 		URI string `json:"uri"`
+		// Metadata attached by the caller under "_meta".
+		Meta *Meta `json:"_meta,omitempty"`
 	} `json:"params"`
 }
 
@@ -490,6 +634,8 @@ type UnsubscribeRequest struct {
 	Params struct {
 		// The URI of the resource to unsubscribe from.
 		URI string `json:"uri"`
+		// Metadata attached by the caller under "_meta".
+		Meta *Meta `json:"_meta,omitempty"`
 	} `json:"params"`
 }
 
@@ -594,6 +740,8 @@ type SetLevelRequest struct {
 		// The server should send all logs at this level and higher (i.e., more severe) to
 		// the client as notifications/logging/message.
 		Level LoggingLevel `json:"level"`
+		// Metadata attached by the caller under "_meta".
+		Meta *Meta `json:"_meta,omitempty"`
 	} `json:"params"`
 }
 
@@ -630,6 +778,16 @@ const (
 	LoggingLevelEmergency LoggingLevel = "emergency"
 )
 
+// IsValid reports whether l is one of the levels defined by the protocol.
+func (l LoggingLevel) IsValid() bool {
+	switch l {
+	case LoggingLevelDebug, LoggingLevelInfo, LoggingLevelNotice, LoggingLevelWarning,
+		LoggingLevelError, LoggingLevelCritical, LoggingLevelAlert, LoggingLevelEmergency:
+		return true
+	}
+	return false
+}
+
 /* Sampling */
 
 // CreateMessageRequest is a request from the server to sample an LLM via the
@@ -647,6 +805,8 @@ type CreateMessageRequest struct {
 		MaxTokens        int               `json:"maxTokens"`
 		StopSequences    []string          `json:"stopSequences,omitempty"`
 		Metadata         any               `json:"metadata,omitempty"`
+		// Metadata attached by the caller under "_meta".
+		Meta *Meta `json:"_meta,omitempty"`
 	} `json:"params"`
 }
 
@@ -813,22 +973,27 @@ type CompleteRequest struct {
 			// The value of the argument to use for completion matching.
 			Value string `json:"value"`
 		} `json:"argument"`
+		// Metadata attached by the caller under "_meta".
+		Meta *Meta `json:"_meta,omitempty"`
 	} `json:"params"`
 }
 
 // CompleteResult is the server's response to a completion/complete request
 type CompleteResult struct {
 	Result
-	Completion struct {
-		// An array of completion values. Must not exceed 100 items.
-		Values []string `json:"values"`
-		// The total number of completion options available. This can exceed the
-		// number of values actually sent in the response.
-		Total int `json:"total,omitempty"`
-		// Indicates whether there are additional completion options beyond those
-		// provided in the current response, even if the exact total is unknown.
-		HasMore bool `json:"hasMore,omitempty"`
-	} `json:"completion"`
+	Completion Completion `json:"completion"`
+}
+
+// Completion holds the values suggested in a CompleteResult.
+type Completion struct {
+	// An array of completion values. Must not exceed 100 items.
+	Values []string `json:"values"`
+	// The total number of completion options available. This can exceed the
+	// number of values actually sent in the response.
+	Total int `json:"total,omitempty"`
+	// Indicates whether there are additional completion options beyond those
+	// provided in the current response, even if the exact total is unknown.
+	HasMore bool `json:"hasMore,omitempty"`
 }
 
 // ResourceReference is a reference to a resource or resource template definition.
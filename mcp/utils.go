@@ -194,6 +194,22 @@ func NewPromptMessage(role Role, content Content) PromptMessage {
 	}
 }
 
+// NewPromptMessages creates a message for each of contents, all sent by role.
+// A PromptMessage carries a single content block, so a turn made of several
+// blocks, e.g. text followed by an image, is a run of messages:
+//
+//	messages := append(
+//		mcp.NewPromptMessages(mcp.RoleUser, mcp.NewTextContent("Describe this"), image),
+//		mcp.NewPromptMessage(mcp.RoleAssistant, mcp.NewTextContent("It shows...")),
+//	)
+func NewPromptMessages(role Role, contents ...Content) []PromptMessage {
+	messages := make([]PromptMessage, 0, len(contents))
+	for _, content := range contents {
+		messages = append(messages, NewPromptMessage(role, content))
+	}
+	return messages
+}
+
 // NewTextContent
 // Helper function to create a new TextContent
 func NewTextContent(text string) TextContent {
@@ -242,6 +258,21 @@ func NewToolResultText(text string) *CallToolResult {
 	}
 }
 
+// NewToolResultStructured creates a new CallToolResult with structured
+// content, along with a text content holding fallbackText for clients that
+// do not read structured content, usually structured serialized as JSON.
+func NewToolResultStructured(structured any, fallbackText string) *CallToolResult {
+	return &CallToolResult{
+		Content: []Content{
+			TextContent{
+				Type: "text",
+				Text: fallbackText,
+			},
+		},
+		StructuredContent: structured,
+	}
+}
+
 // NewToolResultImage creates a new CallToolResult with both text and image content
 func NewToolResultImage(text, imageData, mimeType string) *CallToolResult {
 	return &CallToolResult{
@@ -570,6 +601,10 @@ func ParseCallToolResult(rawMessage *json.RawMessage) (*CallToolResult, error) {
 		}
 	}
 
+	if structured, ok := jsonContent["structuredContent"]; ok {
+		result.StructuredContent = structured
+	}
+
 	contents, ok := jsonContent["content"]
 	if !ok {
 		return nil, fmt.Errorf("content is missing")
@@ -607,7 +642,9 @@ func ParseResourceContents(contentMap map[string]any) (ResourceContents, error)
 
 	mimeType := ExtractString(contentMap, "mimeType")
 
-	if text := ExtractString(contentMap, "text"); text != "" {
+	// Check for the presence of the keys rather than non-empty values, so
+	// that empty contents (e.g. an empty range of a blob) still parse.
+	if text, ok := contentMap["text"].(string); ok {
 		return TextResourceContents{
 			URI:      uri,
 			MIMEType: mimeType,
@@ -615,7 +652,7 @@ func ParseResourceContents(contentMap map[string]any) (ResourceContents, error)
 		}, nil
 	}
 
-	if blob := ExtractString(contentMap, "blob"); blob != "" {
+	if blob, ok := contentMap["blob"].(string); ok {
 		return BlobResourceContents{
 			URI:      uri,
 			MIMEType: mimeType,
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// ArgumentSafetyMiddleware returns a middleware turning panics from failed
+// type assertions in tool handlers, typically unchecked assertions on
+// arguments like
+//
+//	a := request.Params.Arguments["a"].(float64)
+//
+// panicking when the client sends a string, into an INVALID_PARAMS error
+// wrapping ErrInvalidToolArguments and naming the argument. Other panics are
+// passed on, e.g. to the middleware added with WithRecovery. Register it
+// with WithToolHandlerMiddleware:
+//
+//	server.WithToolHandlerMiddleware(server.ArgumentSafetyMiddleware())
+//
+// A type assertion error does not tell which value was asserted, so the
+// argument is identified by the type the handler found. Failed assertions on
+// a value of a type some argument has are taken for argument errors, as are
+// those on a nil value while an argument declared in the tool's input schema
+// is missing or null; the others come from the handler's own values and are
+// passed on. The types are parsed
+// from the message of the runtime's panic, e.g. "interface conversion:
+// interface {} is string, not float64", and assertions whose message does not
+// read that way are passed on as well.
+func ArgumentSafetyMiddleware() ToolHandlerMiddleware {
+	return func(next ToolHandlerFunc) ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					assertionErr, ok := r.(*runtime.TypeAssertionError)
+					if !ok {
+						panic(r)
+					}
+					tool, _ := ctx.Value(calledToolKey{}).(mcp.Tool)
+					argumentErr, ok := invalidArgumentError(tool, request, assertionErr)
+					if !ok {
+						panic(r)
+					}
+					result, err = nil, argumentErr
+				}
+			}()
+			return next(ctx, request)
+		}
+	}
+}
+
+// calledToolKey is the context key for the tool whose handler is called.
+type calledToolKey struct{}
+
+// invalidArgumentError describes the failed assertion on an argument of
+// request to tool reported by assertionErr, whose message reads e.g.
+// "interface conversion: interface {} is string, not float64". It returns
+// false if the message does not read that way, or if no argument matches,
+// in which case the assertion was not on an argument.
+func invalidArgumentError(tool mcp.Tool, request mcp.CallToolRequest, assertionErr *runtime.TypeAssertionError) (error, bool) {
+	_, types, ok := strings.Cut(assertionErr.Error(), " is ")
+	got, want, found := strings.Cut(types, ", not ")
+	if !ok || !found {
+		return nil, false
+	}
+	want, _, _ = strings.Cut(want, " ")
+
+	var names []string
+	if got == "nil" {
+		for name := range tool.InputSchema.Properties {
+			if request.Params.Arguments[name] == nil {
+				names = append(names, fmt.Sprintf("%q", name))
+			}
+		}
+		if len(names) == 0 {
+			return nil, false
+		}
+		sort.Strings(names)
+		return fmt.Errorf("%w: tool %s: missing argument %s of type %s", ErrInvalidToolArguments, request.Params.Name, strings.Join(names, " or "), want), true
+	}
+	for name, value := range request.Params.Arguments {
+		if fmt.Sprintf("%T", value) == got {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(names) == 0 {
+		return nil, false
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%w: tool %s: argument %s must be %s, not %s", ErrInvalidToolArguments, request.Params.Name, strings.Join(names, " or "), want, got), true
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestArgumentSafetyMiddleware(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0",
+		WithRecovery(),
+		WithToolHandlerMiddleware(ArgumentSafetyMiddleware()),
+	)
+	server.AddTool(mcp.NewTool("add", mcp.WithNumber("a"), mcp.WithNumber("b")), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		a := request.Params.Arguments["a"].(float64)
+		b := request.Params.Arguments["b"].(float64)
+		return mcp.FormatNumberResult(a + b), nil
+	})
+	server.AddTool(mcp.NewTool("broken"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var items []string
+		return mcp.NewToolResultText(items[1]), nil
+	})
+	server.AddTool(mcp.NewTool("internal"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var cached any = 42
+		return mcp.NewToolResultText(cached.(string)), nil
+	})
+	server.AddTool(mcp.NewTool("config", mcp.WithString("key")), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		cfg := map[string]any{}
+		return mcp.NewToolResultText(cfg[request.Params.Arguments["key"].(string)].(string)), nil
+	})
+
+	call := func(message string) mcp.JSONRPCMessage {
+		return server.HandleMessage(context.Background(), []byte(message))
+	}
+	expectInvalidParams := func(t *testing.T, response mcp.JSONRPCMessage, message string) {
+		errorResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error, got %#v", response)
+		assert.Equal(t, mcp.INVALID_PARAMS, errorResponse.Error.Code)
+		assert.Equal(t, message, errorResponse.Error.Message)
+	}
+
+	t.Run("Valid arguments", func(t *testing.T) {
+		response := call(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "add", "arguments": {"a": 1, "b": 2}}}`)
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+		text, _ := resp.Result.(mcp.CallToolResult).FirstText()
+		assert.Equal(t, "3.00", text)
+	})
+
+	t.Run("Wrong type", func(t *testing.T) {
+		response := call(`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "add", "arguments": {"a": 1, "b": "2"}}}`)
+		expectInvalidParams(t, response, `invalid tool arguments: tool add: argument "b" must be float64, not string`)
+	})
+
+	t.Run("Missing argument", func(t *testing.T) {
+		response := call(`{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "add", "arguments": {"a": 1}}}`)
+		expectInvalidParams(t, response, `invalid tool arguments: tool add: missing argument "b" of type float64`)
+	})
+
+	t.Run("Ambiguous argument", func(t *testing.T) {
+		response := call(`{"jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": {"name": "add", "arguments": {"a": "1", "b": "2"}}}`)
+		expectInvalidParams(t, response, `invalid tool arguments: tool add: argument "a" or "b" must be float64, not string`)
+	})
+
+	t.Run("Other panics are passed on", func(t *testing.T) {
+		response := call(`{"jsonrpc": "2.0", "id": 5, "method": "tools/call", "params": {"name": "broken"}}`)
+		errorResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error, got %#v", response)
+		assert.Equal(t, mcp.INTERNAL_ERROR, errorResponse.Error.Code)
+		assert.Contains(t, errorResponse.Error.Message, "panic recovered in broken tool handler")
+	})
+
+	t.Run("Assertions on other values are passed on", func(t *testing.T) {
+		response := call(`{"jsonrpc": "2.0", "id": 6, "method": "tools/call", "params": {"name": "internal", "arguments": {"a": "1"}}}`)
+		errorResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error, got %#v", response)
+		assert.Equal(t, mcp.INTERNAL_ERROR, errorResponse.Error.Code)
+		assert.Contains(t, errorResponse.Error.Message, "panic recovered in internal tool handler")
+	})
+
+	t.Run("Assertions on nil values are passed on while no argument is missing", func(t *testing.T) {
+		response := call(`{"jsonrpc": "2.0", "id": 7, "method": "tools/call", "params": {"name": "config", "arguments": {"key": "region"}}}`)
+		errorResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error, got %#v", response)
+		assert.Equal(t, mcp.INTERNAL_ERROR, errorResponse.Error.Code)
+		assert.Contains(t, errorResponse.Error.Message, "panic recovered in config tool handler")
+
+		response = call(`{"jsonrpc": "2.0", "id": 8, "method": "tools/call", "params": {"name": "config"}}`)
+		expectInvalidParams(t, response, `invalid tool arguments: tool config: missing argument "key" of type string`)
+	})
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// clientInfoKey is the session state key for the client implementation
+// reported in the initialize request.
+type clientInfoKey struct{}
+
+// clientCapabilitiesKey is the session state key for the capabilities the
+// client declared in the initialize request.
+type clientCapabilitiesKey struct{}
+
+// recordClientInfo keeps the client implementation and capabilities reported
+// in request in the state of session, if it is registered.
+func (s *MCPServer) recordClientInfo(session ClientSession, request mcp.InitializeRequest) {
+	if state := s.SessionState(session.SessionID()); state != nil {
+		state.Set(clientInfoKey{}, request.Params.ClientInfo)
+		state.Set(clientCapabilitiesKey{}, request.Params.Capabilities)
+	}
+}
+
+// ClientInfoFromContext returns the name and version the client of the
+// current session reported when initializing, for use in handlers, hooks and
+// tool filters, e.g. to expose some tools to a known client only:
+//
+//	server.WithToolFilter(func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+//		if server.ClientInfoFromContext(ctx).Name != "cli" {
+//			return nil
+//		}
+//		return tools
+//	})
+//
+// It returns an empty Implementation if the session has not been initialized
+// or ctx carries no registered session.
+func ClientInfoFromContext(ctx context.Context) mcp.Implementation {
+	state := SessionStateFromContext(ctx)
+	if state == nil {
+		return mcp.Implementation{}
+	}
+	info, _ := state.Get(clientInfoKey{})
+	implementation, _ := info.(mcp.Implementation)
+	return implementation
+}
+
+// ClientCapabilitiesFromContext returns the capabilities the client of the
+// current session declared when initializing, letting handlers adapt to what
+// the client supports, e.g. only offering to sample when Sampling is set.
+//
+// It returns empty ClientCapabilities if the session has not been initialized
+// or ctx carries no registered session.
+func ClientCapabilitiesFromContext(ctx context.Context) mcp.ClientCapabilities {
+	capabilities, _ := clientCapabilities(SessionStateFromContext(ctx))
+	return capabilities
+}
+
+// clientCapabilities returns the capabilities recorded in state and whether
+// the client declared any, i.e. whether it has initialized.
+func clientCapabilities(state *SessionState) (mcp.ClientCapabilities, bool) {
+	if state == nil {
+		return mcp.ClientCapabilities{}, false
+	}
+	value, ok := state.Get(clientCapabilitiesKey{})
+	capabilities, _ := value.(mcp.ClientCapabilities)
+	return capabilities, ok
+}
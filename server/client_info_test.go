@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_ClientInfoFromContext(t *testing.T) {
+	// Only the CLI is trusted with the deploy tool
+	server := NewMCPServer("test-server", "1.0.0",
+		WithToolCapabilities(true),
+		WithToolFilter(func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+			if ClientInfoFromContext(ctx).Name == "cli" {
+				return tools
+			}
+			var filtered []mcp.Tool
+			for _, tool := range tools {
+				if tool.Name != "deploy" {
+					filtered = append(filtered, tool)
+				}
+			}
+			return filtered
+		}),
+	)
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		info := ClientInfoFromContext(ctx)
+		return mcp.NewToolResultText(info.Name + " " + info.Version), nil
+	}
+	server.AddTool(mcp.NewTool("deploy"), handler)
+	server.AddTool(mcp.NewTool("status"), handler)
+
+	connect := func(id, clientName string) context.Context {
+		session := &sessionTestClient{
+			sessionID:           id,
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		ctx := server.WithContext(context.Background(), session)
+		if clientName != "" {
+			response := server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "clientInfo": {"name": "`+clientName+`", "version": "1.2.0"}}}`))
+			_, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok, "expected success, got %#v", response)
+		}
+		return ctx
+	}
+	listTools := func(ctx context.Context) []string {
+		response := server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+		var names []string
+		for _, tool := range resp.Result.(mcp.ListToolsResult).Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	cli := connect("session-cli", "cli")
+	web := connect("session-web", "web-ui")
+	uninitialized := connect("session-new", "")
+
+	assert.Equal(t, []string{"deploy", "status"}, listTools(cli))
+	assert.Equal(t, []string{"status"}, listTools(web))
+	assert.Equal(t, []string{"status"}, listTools(uninitialized))
+
+	response := server.HandleMessage(cli, []byte(`{"jsonrpc": "2.0", "id": 3, "method": "tools/call", "params": {"name": "status"}}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success, got %#v", response)
+	text, _ := resp.Result.(mcp.CallToolResult).FirstText()
+	assert.Equal(t, "cli 1.2.0", text, "handlers see the client info too")
+
+	assert.Equal(t, mcp.Implementation{}, ClientInfoFromContext(context.Background()))
+}
+
+func TestMCPServer_ClientCapabilitiesFromContext(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	connect := func(id, capabilities string) (context.Context, *sessionTestClientWithSampling) {
+		session := &sessionTestClientWithSampling{sessionTestClient: sessionTestClient{
+			sessionID:           id,
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		}}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		ctx := server.WithContext(context.Background(), session)
+		response := server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "clientInfo": {"name": "test", "version": "1.0.0"}, "capabilities": `+capabilities+`}}`))
+		_, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+		return context.WithValue(ctx, serverKey{}, server), session
+	}
+
+	samplingCtx, samplingSession := connect("session-sampling", `{"sampling": {}, "roots": {"listChanged": true}}`)
+	plainCtx, plainSession := connect("session-plain", `{}`)
+
+	capabilities := ClientCapabilitiesFromContext(samplingCtx)
+	assert.NotNil(t, capabilities.Sampling)
+	require.NotNil(t, capabilities.Roots)
+	assert.True(t, capabilities.Roots.ListChanged)
+	assert.Equal(t, mcp.ClientCapabilities{}, ClientCapabilitiesFromContext(plainCtx))
+	assert.Equal(t, mcp.ClientCapabilities{}, ClientCapabilitiesFromContext(context.Background()))
+
+	request := mcp.CreateMessageRequest{}
+	_, err := server.RequestSampling(samplingCtx, request)
+	require.NoError(t, err)
+	assert.Len(t, samplingSession.requests, 1)
+
+	_, err = server.RequestSampling(plainCtx, request)
+	assert.ErrorIs(t, err, ErrSessionDoesNotSupportSampling)
+	assert.Empty(t, plainSession.requests, "the client is not asked without the capability")
+}
+
+func TestMCPServer_ToolAdaptsToClientCapabilities(t *testing.T) {
+	// The chart tool only sends an image to clients declaring they render
+	// images, and a text description to the others
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddTool(mcp.NewTool("chart"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if ClientCapabilitiesFromContext(ctx).HasExperimental("images") {
+			return mcp.NewToolResultImage("chart", "iVBORw0KGgo=", "image/png"), nil
+		}
+		return mcp.NewToolResultText("chart: up 3%"), nil
+	})
+
+	callChart := func(id, capabilities string) []mcp.Content {
+		session := &sessionTestClient{
+			sessionID:           id,
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		ctx := server.WithContext(context.Background(), session)
+		response := server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "clientInfo": {"name": "test", "version": "1.0.0"}, "capabilities": `+capabilities+`}}`))
+		_, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+
+		response = server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "chart"}}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+		return resp.Result.(mcp.CallToolResult).Content
+	}
+
+	content := callChart("session-images", `{"experimental": {"images": {}}}`)
+	require.Len(t, content, 2)
+	assert.IsType(t, mcp.ImageContent{}, content[1])
+
+	content = callChart("session-text", `{}`)
+	require.Len(t, content, 1)
+	assert.Equal(t, mcp.NewTextContent("chart: up 3%"), content[0])
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SessionWithRequests is an extension of ClientSession that can send
+// JSON-RPC requests to the client and wait for the response, as needed for
+// server-initiated methods like sampling/createMessage or roots/list
+type SessionWithRequests interface {
+	ClientSession
+	// SendRequest sends a request with the given method and params to the
+	// client and returns the result of its response. An error response is
+	// returned as an *mcp.JSONRPCErrorError. SendRequest must return once ctx
+	// is done, even if the client has not answered.
+	SendRequest(ctx context.Context, method string, params any) (json.RawMessage, error)
+}
+
+// WithClientRequestTimeout limits how long SendRequestToClient, and helpers
+// built on it like RequestSampling, wait for the client to respond. Without
+// it, requests wait as long as their context allows.
+func WithClientRequestTimeout(timeout time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.clientRequestTimeout = timeout
+	}
+}
+
+// SendRequestToClient sends a JSON-RPC request to the client of the current
+// session and waits for its response, e.g. from within a tool handler:
+//
+//	result, err := s.SendRequestToClient(ctx, "roots/list", nil)
+//
+// The session is taken from ctx and must implement SessionWithRequests,
+// otherwise ErrSessionDoesNotSupportRequests is returned. The request is
+// abandoned when ctx is done or the timeout set with
+// WithClientRequestTimeout passes, in which case the returned error wraps
+// ctx.Err().
+func (s *MCPServer) SendRequestToClient(
+	ctx context.Context,
+	method string,
+	params any,
+) (json.RawMessage, error) {
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	requestSession, ok := session.(SessionWithRequests)
+	if !ok {
+		return nil, ErrSessionDoesNotSupportRequests
+	}
+
+	if s.clientRequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.clientRequestTimeout)
+		defer cancel()
+	}
+
+	result, err := requestSession.SendRequest(ctx, method, params)
+	if err != nil {
+		return nil, fmt.Errorf("request %s: %w", method, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// sessionTestClientWithRequests implements the SessionWithRequests interface for testing
+type sessionTestClientWithRequests struct {
+	sessionTestClient
+	respond func(ctx context.Context, method string, params any) (json.RawMessage, error)
+}
+
+func (f *sessionTestClientWithRequests) SendRequest(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	return f.respond(ctx, method, params)
+}
+
+func TestMCPServer_SendRequestToClient(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithClientRequestTimeout(50*time.Millisecond))
+
+	_, err := server.SendRequestToClient(context.Background(), "roots/list", nil)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+
+	plainSession := &sessionTestClient{sessionID: "plain"}
+	_, err = server.SendRequestToClient(server.WithContext(context.Background(), plainSession), "roots/list", nil)
+	assert.ErrorIs(t, err, ErrSessionDoesNotSupportRequests)
+
+	t.Run("Returns the client's result", func(t *testing.T) {
+		session := &sessionTestClientWithRequests{
+			sessionTestClient: sessionTestClient{sessionID: "requests"},
+			respond: func(ctx context.Context, method string, params any) (json.RawMessage, error) {
+				assert.Equal(t, "roots/list", method)
+				return json.RawMessage(`{"roots":[{"uri":"file:///tmp"}]}`), nil
+			},
+		}
+		result, err := server.SendRequestToClient(server.WithContext(context.Background(), session), "roots/list", nil)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"roots":[{"uri":"file:///tmp"}]}`, string(result))
+	})
+
+	t.Run("Returns the client's error", func(t *testing.T) {
+		session := &sessionTestClientWithRequests{
+			sessionTestClient: sessionTestClient{sessionID: "requests"},
+			respond: func(ctx context.Context, method string, params any) (json.RawMessage, error) {
+				return nil, &mcp.JSONRPCErrorError{Code: mcp.METHOD_NOT_FOUND, Message: "not supported"}
+			},
+		}
+		_, err := server.SendRequestToClient(server.WithContext(context.Background(), session), "roots/list", nil)
+		var rpcErr *mcp.JSONRPCErrorError
+		require.ErrorAs(t, err, &rpcErr)
+		assert.Equal(t, mcp.METHOD_NOT_FOUND, rpcErr.Code)
+	})
+
+	t.Run("Times out when the client does not answer", func(t *testing.T) {
+		session := &sessionTestClientWithRequests{
+			sessionTestClient: sessionTestClient{sessionID: "requests"},
+			respond: func(ctx context.Context, method string, params any) (json.RawMessage, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		}
+		start := time.Now()
+		_, err := server.SendRequestToClient(server.WithContext(context.Background(), session), "roots/list", nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Stops waiting when the context is cancelled", func(t *testing.T) {
+		session := &sessionTestClientWithRequests{
+			sessionTestClient: sessionTestClient{sessionID: "requests"},
+			respond: func(ctx context.Context, method string, params any) (json.RawMessage, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		}
+		ctx, cancel := context.WithCancel(server.WithContext(context.Background(), session))
+		cancel()
+		_, err := server.SendRequestToClient(ctx, "roots/list", nil)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Sampling is sent as a request", func(t *testing.T) {
+		var sentParams any
+		session := &sessionTestClientWithRequests{
+			sessionTestClient: sessionTestClient{sessionID: "requests"},
+			respond: func(ctx context.Context, method string, params any) (json.RawMessage, error) {
+				assert.Equal(t, string(mcp.MethodSamplingCreateMessage), method)
+				sentParams = params
+				return json.RawMessage(`{"role":"assistant","content":{"type":"text","text":"Paris"},"model":"test-model"}`), nil
+			},
+		}
+		request := mcp.CreateMessageRequest{}
+		request.Params.MaxTokens = 10
+		result, err := server.RequestSampling(server.WithContext(context.Background(), session), request)
+		require.NoError(t, err)
+		assert.Equal(t, "test-model", result.Model)
+		assert.Equal(t, mcp.NewTextContent("Paris"), result.Content)
+		assert.Equal(t, request.Params, sentParams)
+	})
+}
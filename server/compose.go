@@ -0,0 +1,159 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// Compose returns a server exposing the tools, prompts, resources, resource
+// templates and resource matchers of several servers as one, e.g. to put a
+// gateway in front of a set of small servers. Tool and prompt names are
+// prefixed with the name of the server they come from and an underscore, so
+// the "search" tool of a server named "docs" is listed as "docs_search".
+// Resources and resource templates keep their URIs; if several servers
+// register the same URI, the first server passed to Compose wins.
+//
+// Calls are routed to the owning server through its HandleMessage, so its
+// hooks, middlewares and authorization apply as if the client had called it
+// directly, and its JSON-RPC errors reach the client with their code and
+// message.
+//
+// The list of what is exposed is fixed when Compose is called: list calls
+// are answered by the composite server, not forwarded, so tools, prompts,
+// resources, templates and matchers added to the servers afterwards are
+// never exposed, and those removed keep being listed, failing when called.
+// Call Compose again to pick up such changes.
+func Compose(name, version string, servers ...*MCPServer) *MCPServer {
+	composite := NewMCPServer(name, version)
+	for _, child := range servers {
+		composite.addComposedServer(child)
+	}
+	return composite
+}
+
+// addComposedServer registers forwarding entries for everything child
+// currently serves.
+func (s *MCPServer) addComposedServer(child *MCPServer) {
+	prefix := child.name + "_"
+
+	child.toolsMu.RLock()
+	var tools []ServerTool
+	for _, tool := range child.tools {
+		tools = append(tools, tool)
+	}
+	child.toolsMu.RUnlock()
+	for _, tool := range tools {
+		name := tool.Tool.Name
+		composed := tool.Tool
+		composed.Name = prefix + name
+		s.AddTool(composed, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			params := request.Params
+			params.Name = name
+			return forwardRequest[mcp.CallToolResult](ctx, child, mcp.MethodToolsCall, params)
+		})
+	}
+
+	child.promptsMu.RLock()
+	var prompts []mcp.Prompt
+	for _, prompt := range child.prompts {
+		prompts = append(prompts, prompt)
+	}
+	child.promptsMu.RUnlock()
+	for _, prompt := range prompts {
+		name := prompt.Name
+		composed := prompt
+		composed.Name = prefix + name
+		s.AddPrompt(composed, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			params := request.Params
+			params.Name = name
+			return forwardRequest[mcp.GetPromptResult](ctx, child, mcp.MethodPromptsGet, params)
+		})
+	}
+
+	readResource := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		result, err := forwardRequest[mcp.ReadResourceResult](ctx, child, mcp.MethodResourcesRead, request.Params)
+		if err != nil {
+			return nil, err
+		}
+		return result.Contents, nil
+	}
+
+	child.resourcesMu.RLock()
+	var resources []mcp.Resource
+	for _, entry := range child.resources {
+		resources = append(resources, entry.resource)
+	}
+	var templates []resourceTemplateEntry
+	for _, entry := range child.resourceTemplates {
+		templates = append(templates, entry)
+	}
+	matchers := child.resourceMatchers
+	child.resourcesMu.RUnlock()
+
+	s.resourcesMu.RLock()
+	var newResources []mcp.Resource
+	for _, resource := range resources {
+		if _, exists := s.resources[resource.URI]; !exists {
+			newResources = append(newResources, resource)
+		}
+	}
+	var newTemplates []resourceTemplateEntry
+	for _, entry := range templates {
+		if _, exists := s.resourceTemplates[entry.template.URITemplate.Raw()]; !exists {
+			newTemplates = append(newTemplates, entry)
+		}
+	}
+	s.resourcesMu.RUnlock()
+
+	for _, resource := range newResources {
+		s.AddResource(resource, readResource)
+	}
+	for _, entry := range newTemplates {
+		if entry.completion != nil {
+			s.AddResourceTemplateWithCompletionResult(entry.template, readResource, entry.completion)
+		} else {
+			s.AddResourceTemplate(entry.template, readResource)
+		}
+	}
+	for _, entry := range matchers {
+		s.AddResourceMatcherWithLister(entry.matcher, readResource, entry.lister)
+	}
+}
+
+// forwardRequest sends a request for method with params to server, under
+// the id of the request being handled in ctx, and decodes its result.
+// JSON-RPC errors returned by server are passed on as *mcp.JSONRPCErrorError.
+func forwardRequest[T any](ctx context.Context, server *MCPServer, method mcp.MCPMethod, params any) (*T, error) {
+	id, ok := RequestIDFromContext(ctx)
+	if !ok {
+		id = string(method)
+	}
+	message, err := json.Marshal(mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      id,
+		Params:  params,
+		Request: mcp.Request{Method: string(method)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("forward %s to %s: %w", method, server.name, err)
+	}
+
+	switch response := server.HandleMessage(ctx, message).(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := response.Result.(T)
+		if !ok {
+			return nil, fmt.Errorf("forward %s to %s: unexpected result %T", method, server.name, response.Result)
+		}
+		return &result, nil
+	case mcp.JSONRPCError:
+		return nil, &mcp.JSONRPCErrorError{
+			Code:    response.Error.Code,
+			Message: response.Error.Message,
+		}
+	default:
+		return nil, fmt.Errorf("forward %s to %s: no response", method, server.name)
+	}
+}
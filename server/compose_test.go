@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestCompose(t *testing.T) {
+	weather := NewMCPServer("weather", "1.0.0")
+	weather.AddTool(mcp.NewTool("lookup", mcp.WithString("city")), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		assert.Equal(t, "lookup", request.Params.Name)
+		assert.Same(t, weather, ServerFromContext(ctx))
+		return mcp.NewToolResultText("sunny in " + request.Params.Arguments["city"].(string)), nil
+	})
+	weather.AddResource(mcp.NewResource("weather://today", "Today"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: "sunny"}}, nil
+	})
+
+	docs := NewMCPServer("docs", "1.0.0")
+	docs.AddTool(mcp.NewTool("lookup"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, assert.AnError
+	})
+	docs.AddPrompt(mcp.NewPrompt("summarize"), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return mcp.NewGetPromptResult("summary", nil), nil
+	})
+
+	gateway := Compose("gateway", "1.0.0", weather, docs)
+	request := func(method, params string) mcp.JSONRPCMessage {
+		return gateway.HandleMessage(context.Background(), []byte(
+			`{"jsonrpc":"2.0","id":1,"method":"`+method+`","params":`+params+`}`))
+	}
+
+	t.Run("lists the tools of all servers", func(t *testing.T) {
+		response, ok := request("tools/list", `{}`).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		var names []string
+		for _, tool := range response.Result.(mcp.ListToolsResult).Tools {
+			names = append(names, tool.Name)
+		}
+		assert.Equal(t, []string{"docs_lookup", "weather_lookup"}, names)
+	})
+
+	t.Run("routes tool calls by prefix", func(t *testing.T) {
+		response, ok := request("tools/call", `{"name":"weather_lookup","arguments":{"city":"Seattle"}}`).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		result := response.Result.(mcp.CallToolResult)
+		assert.Equal(t, "sunny in Seattle", result.Content[0].(mcp.TextContent).Text)
+
+		errorResponse, ok := request("tools/call", `{"name":"docs_lookup"}`).(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Contains(t, errorResponse.Error.Message, assert.AnError.Error())
+	})
+
+	t.Run("routes prompts and resources", func(t *testing.T) {
+		response, ok := request("prompts/get", `{"name":"docs_summarize"}`).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		assert.Equal(t, "summary", response.Result.(mcp.GetPromptResult).Description)
+
+		response, ok = request("resources/read", `{"uri":"weather://today"}`).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		data, err := json.Marshal(response.Result)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"text":"sunny"`)
+	})
+
+	t.Run("passes on the error codes of the servers", func(t *testing.T) {
+		secrets := NewMCPServer("secrets", "1.0.0", WithToolAuthorizer(func(ctx context.Context, tool mcp.Tool, request mcp.CallToolRequest) error {
+			return assert.AnError
+		}))
+		secrets.AddTool(mcp.NewTool("reveal"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("hunter2"), nil
+		})
+		secrets.AddPrompt(mcp.NewPrompt("hint"), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return nil, ErrInvalidPromptArguments
+		})
+		secrets.AddResource(mcp.NewResource("secrets://vault", "Vault"), func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, &mcp.JSONRPCErrorError{Code: mcp.RESOURCE_NOT_FOUND, Message: "vault is sealed"}
+		})
+
+		gateway := Compose("gateway", "1.0.0", secrets)
+		request := func(method, params string) mcp.JSONRPCError {
+			response := gateway.HandleMessage(context.Background(), []byte(
+				`{"jsonrpc":"2.0","id":1,"method":"`+method+`","params":`+params+`}`))
+			errorResponse, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok, "expected error, got %#v", response)
+			return errorResponse
+		}
+
+		errorResponse := request("tools/call", `{"name":"secrets_reveal"}`)
+		assert.Equal(t, mcp.FORBIDDEN, errorResponse.Error.Code)
+		assert.Contains(t, errorResponse.Error.Message, assert.AnError.Error())
+
+		errorResponse = request("prompts/get", `{"name":"secrets_hint"}`)
+		assert.Equal(t, mcp.INVALID_PARAMS, errorResponse.Error.Code)
+
+		errorResponse = request("resources/read", `{"uri":"secrets://vault"}`)
+		assert.Equal(t, mcp.RESOURCE_NOT_FOUND, errorResponse.Error.Code)
+		assert.Equal(t, "vault is sealed", errorResponse.Error.Message)
+	})
+}
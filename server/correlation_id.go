@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// correlationIDMetaKey is the result _meta field carrying the correlation ID
+// stamped with WithResponseCorrelationID.
+const correlationIDMetaKey = "correlationId"
+
+// correlationIDKey is the context key for the correlation ID of the request
+// being handled.
+type correlationIDKey struct{}
+
+// WithResponseCorrelationID assigns each request a server-generated UUID,
+// available to handlers and hooks through CorrelationIDFromContext, and
+// returns it in the correlationId field of the result's _meta, so that
+// client and server logs about the same request can be matched. Clients
+// ignore _meta fields they do not know. Error responses carry no _meta and
+// are not stamped.
+func WithResponseCorrelationID() ServerOption {
+	return func(s *MCPServer) {
+		s.responseCorrelationID = true
+	}
+}
+
+// CorrelationIDFromContext returns the correlation ID assigned to the request
+// being handled by a server configured with WithResponseCorrelationID. It
+// returns false otherwise, and while handling a notification.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok
+}
+
+// newCorrelationID returns a new correlation ID.
+func newCorrelationID() string {
+	return uuid.New().String()
+}
+
+// withCorrelationID returns a copy of meta with the correlation ID added,
+// leaving the handler's map unchanged.
+func withCorrelationID(meta map[string]any, correlationID string) map[string]any {
+	stamped := make(map[string]any, len(meta)+1)
+	for k, v := range meta {
+		stamped[k] = v
+	}
+	stamped[correlationIDMetaKey] = correlationID
+	return stamped
+}
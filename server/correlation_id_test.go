@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_ResponseCorrelationID(t *testing.T) {
+	handlerMeta := map[string]any{"source": "cache"}
+	newServer := func(opts ...ServerOption) *MCPServer {
+		server := NewMCPServer("test-server", "1.0.0", opts...)
+		server.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			correlationID, _ := CorrelationIDFromContext(ctx)
+			result := mcp.NewToolResultText(correlationID)
+			result.Meta = handlerMeta
+			return result, nil
+		})
+		return server
+	}
+	call := func(server *MCPServer) mcp.JSONRPCResponse {
+		response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "whoami"}}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+		return resp
+	}
+
+	t.Run("Stamped", func(t *testing.T) {
+		server := newServer(WithResponseCorrelationID())
+
+		result := call(server).Result.(mcp.CallToolResult)
+		correlationID, _ := result.Meta["correlationId"].(string)
+		assert.Len(t, correlationID, 36, "expected a UUID")
+		text, _ := result.FirstText()
+		assert.Equal(t, correlationID, text, "handlers see the correlation ID")
+		assert.Equal(t, "cache", result.Meta["source"])
+		assert.Equal(t, map[string]any{"source": "cache"}, handlerMeta, "the handler's meta is left unchanged")
+
+		next := call(server).Result.(mcp.CallToolResult)
+		assert.NotEqual(t, correlationID, next.Meta["correlationId"])
+
+		// Results without meta of their own are stamped too
+		ping := server.HandleMessage(context.Background(), []byte(`{"jsonrpc": "2.0", "id": 2, "method": "ping"}`))
+		assert.Contains(t, ping.(mcp.JSONRPCResponse).Result.(mcp.EmptyResult).Meta, "correlationId")
+
+		// Clients unaware of the field parse the result as before
+		data, err := json.Marshal(call(server).Result)
+		require.NoError(t, err)
+		raw := json.RawMessage(data)
+		parsed, err := mcp.ParseCallToolResult(&raw)
+		require.NoError(t, err)
+		text, _ = parsed.FirstText()
+		assert.Equal(t, parsed.Meta["correlationId"], text)
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		result := call(newServer()).Result.(mcp.CallToolResult)
+		assert.Equal(t, map[string]any{"source": "cache"}, result.Meta)
+		text, _ := result.FirstText()
+		assert.Empty(t, text)
+	})
+}
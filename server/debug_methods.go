@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+const (
+	// methodDebugEcho answers with the request it received.
+	methodDebugEcho mcp.MCPMethod = "debug/echo"
+	// methodDebugEchoNotification answers like debug/echo after sending the
+	// request back in a debug/test notification.
+	methodDebugEchoNotification mcp.MCPMethod = "debug/echo_notification"
+	// methodDebugEchoErrorString answers with an error whose message is the
+	// request it received.
+	methodDebugEchoErrorString mcp.MCPMethod = "debug/echo_error_string"
+
+	// methodDebugTest is the notification sent by debug/echo_notification.
+	methodDebugTest = "debug/test"
+)
+
+// WithDebugMethods makes the server answer the debug/echo,
+// debug/echo_notification and debug/echo_error_string requests, so any MCP
+// client can be pointed at an mcp-go server to check its transport plumbing.
+// They are off by default, as they have no use in production.
+func WithDebugMethods() ServerOption {
+	return func(s *MCPServer) {
+		s.debugMethods = true
+	}
+}
+
+// handleDebugMethod answers the debug request message, returning false if
+// method is not a debug method.
+func (s *MCPServer) handleDebugMethod(
+	ctx context.Context,
+	id any,
+	method mcp.MCPMethod,
+	message json.RawMessage,
+) (mcp.JSONRPCMessage, bool) {
+	switch method {
+	case methodDebugEcho, methodDebugEchoNotification, methodDebugEchoErrorString:
+	default:
+		return nil, false
+	}
+
+	var request map[string]any
+	if err := json.Unmarshal(message, &request); err != nil {
+		s.hooks.onError(ctx, id, method, message,
+			&UnparsableMessageError{message: message, err: err, method: method})
+		return createErrorResponse(id, mcp.INVALID_REQUEST, "Failed to parse request"), true
+	}
+
+	switch method {
+	case methodDebugEchoNotification:
+		if err := s.SendNotificationToClient(ctx, methodDebugTest, request); err != nil {
+			s.hooks.onError(ctx, id, method, request, err)
+			return createErrorResponse(id, mcp.INTERNAL_ERROR, err.Error()), true
+		}
+	case methodDebugEchoErrorString:
+		return createErrorResponse(id, mcp.INTERNAL_ERROR, string(message)), true
+	}
+	return createResponse(id, request), true
+}
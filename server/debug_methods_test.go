@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_DebugMethods(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithDebugMethods())
+	session := &sessionTestClient{
+		sessionID:           "session-1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		initialized:         true,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	ctx := server.WithContext(context.Background(), session)
+
+	request := func(srv *MCPServer, method string) mcp.JSONRPCMessage {
+		return srv.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "`+method+`", "params": {"string": "hello"}}`))
+	}
+	assertEcho := func(t *testing.T, echoed any, method string) {
+		echo, ok := echoed.(map[string]any)
+		require.True(t, ok, "expected the request, got %#v", echoed)
+		assert.Equal(t, method, echo["method"])
+		assert.Equal(t, map[string]any{"string": "hello"}, echo["params"])
+	}
+
+	t.Run("Echo", func(t *testing.T) {
+		response := request(server, "debug/echo")
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+		assertEcho(t, resp.Result, "debug/echo")
+	})
+
+	t.Run("Echo notification", func(t *testing.T) {
+		response := request(server, "debug/echo_notification")
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+		assertEcho(t, resp.Result, "debug/echo_notification")
+
+		select {
+		case notification := <-session.notificationChannel:
+			assert.Equal(t, "debug/test", notification.Method)
+			assertEcho(t, notification.Params.AdditionalFields, "debug/echo_notification")
+		case <-time.After(time.Second):
+			t.Fatal("debug/test notification not sent")
+		}
+	})
+
+	t.Run("Echo error string", func(t *testing.T) {
+		response := request(server, "debug/echo_error_string")
+		errResp, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error, got %#v", response)
+		var echoed any
+		require.NoError(t, json.Unmarshal([]byte(errResp.Error.Message), &echoed))
+		assertEcho(t, echoed, "debug/echo_error_string")
+	})
+
+	t.Run("Off by default", func(t *testing.T) {
+		response := request(NewMCPServer("test-server", "1.0.0"), "debug/echo")
+		errResp, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error, got %#v", response)
+		assert.Equal(t, mcp.METHOD_NOT_FOUND, errResp.Error.Code)
+	})
+}
@@ -12,17 +12,56 @@ var (
 	ErrPromptNotFound   = errors.New("prompt not found")
 	ErrToolNotFound     = errors.New("tool not found")
 
+	// Prompt-related errors
+	ErrInvalidPromptArguments = errors.New("invalid prompt arguments")
+
+	// Tool-related errors
+	ErrToolResultTooLarge    = errors.New("tool result too large")
+	ErrUnauthorized          = errors.New("unauthorized")
+	ErrForbidden             = errors.New("forbidden")
+	ErrInvalidToolDefinition = errors.New("invalid tool definition")
+	ErrInvalidToolArguments  = errors.New("invalid tool arguments")
+
 	// Session-related errors
-	ErrSessionNotFound            = errors.New("session not found")
-	ErrSessionExists              = errors.New("session already exists")
-	ErrSessionNotInitialized      = errors.New("session not properly initialized")
-	ErrSessionDoesNotSupportTools = errors.New("session does not support per-session tools")
+	ErrSessionNotFound                = errors.New("session not found")
+	ErrSessionExists                  = errors.New("session already exists")
+	ErrSessionNotInitialized          = errors.New("session not properly initialized")
+	ErrSessionDoesNotSupportTools     = errors.New("session does not support per-session tools")
+	ErrSessionDoesNotSupportResources = errors.New("session does not support per-session resources")
+	ErrSessionDoesNotSupportPrompts   = errors.New("session does not support per-session prompts")
+	ErrSessionDoesNotSupportSampling  = errors.New("session does not support sampling")
+	ErrSessionDoesNotSupportRequests  = errors.New("session does not support server requests")
+	ErrSessionClosed                  = errors.New("session closed")
+
+	// Transport-related errors
+	ErrServerBusy             = errors.New("server busy")
+	ErrRequestTooLarge        = errors.New("request too large")
+	ErrInvalidHandlerPatterns = errors.New("invalid handler patterns")
 
 	// Notification-related errors
 	ErrNotificationNotInitialized = errors.New("notification channel not initialized")
 	ErrNotificationChannelBlocked = errors.New("notification channel full or blocked")
 )
 
+// RequestRejectedError can be returned by an OnRequestInitializationFunc to
+// reject a request with a specific JSON-RPC error code, e.g. mcp.UNAUTHORIZED,
+// instead of INVALID_REQUEST.
+type RequestRejectedError struct {
+	Code int
+	Err  error
+}
+
+func (e *RequestRejectedError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("request rejected with code %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *RequestRejectedError) Unwrap() error {
+	return e.Err
+}
+
 // ErrDynamicPathConfig is returned when attempting to use static path methods with dynamic path configuration
 type ErrDynamicPathConfig struct {
 	Method string
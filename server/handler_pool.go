@@ -0,0 +1,68 @@
+package server
+
+// defaultHandlerQueueSize is the number of messages that may wait for a
+// handler pool worker unless set with WithHandlerQueueSize.
+const defaultHandlerQueueSize = 100
+
+// handlerPool runs tasks on a bounded number of goroutines. Workers are
+// started on demand and exit once the queue is drained, so an idle pool holds
+// no goroutines.
+type handlerPool struct {
+	queue   chan func()
+	workers chan struct{}
+}
+
+func newHandlerPool(size, queueSize int) *handlerPool {
+	return &handlerPool{
+		queue:   make(chan func(), queueSize),
+		workers: make(chan struct{}, size),
+	}
+}
+
+// submit queues task to run on a worker. It reports false without queueing
+// the task if the queue is full.
+func (p *handlerPool) submit(task func()) bool {
+	select {
+	case p.queue <- task:
+	default:
+		return false
+	}
+
+	// Start a worker unless all of them are already running, in which case
+	// one of them picks up the task.
+	select {
+	case p.workers <- struct{}{}:
+		go p.work()
+	default:
+	}
+	return true
+}
+
+// work runs queued tasks until the queue is empty.
+func (p *handlerPool) work() {
+	for {
+		for drained := false; !drained; {
+			select {
+			case task := <-p.queue:
+				task()
+			default:
+				drained = true
+			}
+		}
+
+		<-p.workers
+
+		// A task queued after the queue was found empty, while this worker
+		// still counted as running, would otherwise wait for the next
+		// submission.
+		if len(p.queue) == 0 {
+			return
+		}
+		select {
+		case p.workers <- struct{}{}:
+		default:
+			// Another worker is running and picks it up
+			return
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerPool(t *testing.T) {
+	pool := newHandlerPool(3, 1000)
+
+	var wg sync.WaitGroup
+	var ran, running, maxRunning atomic.Int32
+	for i := 0; i < 500; i++ {
+		wg.Add(1)
+		require.True(t, pool.submit(func() {
+			defer wg.Done()
+			n := running.Add(1)
+			for {
+				max := maxRunning.Load()
+				if n <= max || maxRunning.CompareAndSwap(max, n) {
+					break
+				}
+			}
+			time.Sleep(time.Microsecond)
+			running.Add(-1)
+			ran.Add(1)
+		}))
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(500), ran.Load())
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+
+	// Workers exit once the queue is drained
+	assert.Eventually(t, func() bool { return len(pool.workers) == 0 }, time.Second, time.Millisecond)
+}
+
+func TestHandlerPool_RejectsWhenQueueFull(t *testing.T) {
+	pool := newHandlerPool(1, 1)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.True(t, pool.submit(func() {
+		close(started)
+		<-release
+	}))
+	<-started
+
+	require.True(t, pool.submit(func() {}), "one task should fit in the queue")
+	assert.False(t, pool.submit(func() {}), "tasks beyond the queue size should be rejected")
+
+	close(release)
+}
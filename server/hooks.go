@@ -20,6 +20,8 @@ type BeforeAnyHookFunc func(ctx context.Context, id any, method mcp.MCPMethod, m
 
 // OnSuccessHookFunc is a hook that will be called after the request
 // successfully generates a result, but before the result is sent to the client.
+// The time elapsed since the request was received can be computed from
+// RequestStartFromContext, in this hook as well as in OnErrorHookFunc.
 type OnSuccessHookFunc func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any)
 
 // OnErrorHookFunc is a hook that will be called when an error occurs,
@@ -59,8 +61,21 @@ type OnErrorHookFunc func(ctx context.Context, id any, method mcp.MCPMethod, mes
 
 // OnRequestInitializationFunc is a function that called before handle diff request method
 // Should any errors arise during func execution, the service will promptly return the corresponding error message.
+//
+// Returning an error rejects the request before it is dispatched, making these
+// hooks a single place to gate all requests, e.g. for authentication or quotas.
+// The remaining OnRequestInitialization hooks, the method's hooks and its
+// handler are skipped, the error is reported to the OnError hooks, and the
+// client receives an INVALID_REQUEST error with the error's message, or the
+// code of a *RequestRejectedError in the error's chain.
 type OnRequestInitializationFunc func(ctx context.Context, id any, message any) error
 
+// OnProtocolVersionMismatchFunc is a hook that will be called when a client
+// initializes with a protocol version the server does not support. The
+// server still answers with its own version, leaving it to the client to
+// disconnect, so these hooks are the place to log incompatible clients.
+type OnProtocolVersionMismatchFunc func(ctx context.Context, id any, requested string, supported string)
+
 type OnBeforeInitializeFunc func(ctx context.Context, id any, message *mcp.InitializeRequest)
 type OnAfterInitializeFunc func(ctx context.Context, id any, message *mcp.InitializeRequest, result *mcp.InitializeResult)
 
@@ -76,6 +91,12 @@ type OnAfterListResourceTemplatesFunc func(ctx context.Context, id any, message
 type OnBeforeReadResourceFunc func(ctx context.Context, id any, message *mcp.ReadResourceRequest)
 type OnAfterReadResourceFunc func(ctx context.Context, id any, message *mcp.ReadResourceRequest, result *mcp.ReadResourceResult)
 
+type OnBeforeSubscribeFunc func(ctx context.Context, id any, message *mcp.SubscribeRequest)
+type OnAfterSubscribeFunc func(ctx context.Context, id any, message *mcp.SubscribeRequest, result *mcp.EmptyResult)
+
+type OnBeforeUnsubscribeFunc func(ctx context.Context, id any, message *mcp.UnsubscribeRequest)
+type OnAfterUnsubscribeFunc func(ctx context.Context, id any, message *mcp.UnsubscribeRequest, result *mcp.EmptyResult)
+
 type OnBeforeListPromptsFunc func(ctx context.Context, id any, message *mcp.ListPromptsRequest)
 type OnAfterListPromptsFunc func(ctx context.Context, id any, message *mcp.ListPromptsRequest, result *mcp.ListPromptsResult)
 
@@ -88,6 +109,18 @@ type OnAfterListToolsFunc func(ctx context.Context, id any, message *mcp.ListToo
 type OnBeforeCallToolFunc func(ctx context.Context, id any, message *mcp.CallToolRequest)
 type OnAfterCallToolFunc func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult)
 
+type OnBeforeCompleteFunc func(ctx context.Context, id any, message *mcp.CompleteRequest)
+type OnAfterCompleteFunc func(ctx context.Context, id any, message *mcp.CompleteRequest, result *mcp.CompleteResult)
+
+type OnBeforeSetLevelFunc func(ctx context.Context, id any, message *mcp.SetLevelRequest)
+type OnAfterSetLevelFunc func(ctx context.Context, id any, message *mcp.SetLevelRequest, result *mcp.EmptyResult)
+
+// Hooks holds the callbacks invoked by the server while handling requests and
+// sessions. Hooks of the same kind run in the order they were registered, and
+// the generic hooks (OnBeforeAny, OnSuccess) run before the method specific
+// ones. Use RemoveAll or the Clear* methods to reset hooks, e.g. between test
+// cases. Like the Add* methods, they must not be called while the server is
+// handling requests.
 type Hooks struct {
 	OnRegisterSession             []OnRegisterSessionHookFunc
 	OnUnregisterSession           []OnUnregisterSessionHookFunc
@@ -95,6 +128,7 @@ type Hooks struct {
 	OnSuccess                     []OnSuccessHookFunc
 	OnError                       []OnErrorHookFunc
 	OnRequestInitialization       []OnRequestInitializationFunc
+	OnProtocolVersionMismatch     []OnProtocolVersionMismatchFunc
 	OnBeforeInitialize            []OnBeforeInitializeFunc
 	OnAfterInitialize             []OnAfterInitializeFunc
 	OnBeforePing                  []OnBeforePingFunc
@@ -105,6 +139,10 @@ type Hooks struct {
 	OnAfterListResourceTemplates  []OnAfterListResourceTemplatesFunc
 	OnBeforeReadResource          []OnBeforeReadResourceFunc
 	OnAfterReadResource           []OnAfterReadResourceFunc
+	OnBeforeSubscribe             []OnBeforeSubscribeFunc
+	OnAfterSubscribe              []OnAfterSubscribeFunc
+	OnBeforeUnsubscribe           []OnBeforeUnsubscribeFunc
+	OnAfterUnsubscribe            []OnAfterUnsubscribeFunc
 	OnBeforeListPrompts           []OnBeforeListPromptsFunc
 	OnAfterListPrompts            []OnAfterListPromptsFunc
 	OnBeforeGetPrompt             []OnBeforeGetPromptFunc
@@ -113,16 +151,35 @@ type Hooks struct {
 	OnAfterListTools              []OnAfterListToolsFunc
 	OnBeforeCallTool              []OnBeforeCallToolFunc
 	OnAfterCallTool               []OnAfterCallToolFunc
+	OnBeforeComplete              []OnBeforeCompleteFunc
+	OnAfterComplete               []OnAfterCompleteFunc
+	OnBeforeSetLevel              []OnBeforeSetLevelFunc
+	OnAfterSetLevel               []OnAfterSetLevelFunc
+}
+
+// RemoveAll removes every registered hook of every kind.
+func (c *Hooks) RemoveAll() {
+	*c = Hooks{}
 }
 
 func (c *Hooks) AddBeforeAny(hook BeforeAnyHookFunc) {
 	c.OnBeforeAny = append(c.OnBeforeAny, hook)
 }
 
+// ClearBeforeAny removes all hooks registered with AddBeforeAny.
+func (c *Hooks) ClearBeforeAny() {
+	c.OnBeforeAny = nil
+}
+
 func (c *Hooks) AddOnSuccess(hook OnSuccessHookFunc) {
 	c.OnSuccess = append(c.OnSuccess, hook)
 }
 
+// ClearOnSuccess removes all hooks registered with AddOnSuccess.
+func (c *Hooks) ClearOnSuccess() {
+	c.OnSuccess = nil
+}
+
 // AddOnError registers a hook function that will be called when an error occurs.
 // The error parameter contains the actual error object, which can be interrogated
 // using Go's error handling patterns like errors.Is and errors.As.
@@ -172,6 +229,11 @@ func (c *Hooks) AddOnError(hook OnErrorHookFunc) {
 	c.OnError = append(c.OnError, hook)
 }
 
+// ClearOnError removes all hooks registered with AddOnError.
+func (c *Hooks) ClearOnError() {
+	c.OnError = nil
+}
+
 func (c *Hooks) beforeAny(ctx context.Context, id any, method mcp.MCPMethod, message any) {
 	if c == nil {
 		return
@@ -217,6 +279,11 @@ func (c *Hooks) AddOnRegisterSession(hook OnRegisterSessionHookFunc) {
 	c.OnRegisterSession = append(c.OnRegisterSession, hook)
 }
 
+// ClearOnRegisterSession removes all hooks registered with AddOnRegisterSession.
+func (c *Hooks) ClearOnRegisterSession() {
+	c.OnRegisterSession = nil
+}
+
 func (c *Hooks) RegisterSession(ctx context.Context, session ClientSession) {
 	if c == nil {
 		return
@@ -230,6 +297,11 @@ func (c *Hooks) AddOnUnregisterSession(hook OnUnregisterSessionHookFunc) {
 	c.OnUnregisterSession = append(c.OnUnregisterSession, hook)
 }
 
+// ClearOnUnregisterSession removes all hooks registered with AddOnUnregisterSession.
+func (c *Hooks) ClearOnUnregisterSession() {
+	c.OnUnregisterSession = nil
+}
+
 func (c *Hooks) UnregisterSession(ctx context.Context, session ClientSession) {
 	if c == nil {
 		return
@@ -243,6 +315,11 @@ func (c *Hooks) AddOnRequestInitialization(hook OnRequestInitializationFunc) {
 	c.OnRequestInitialization = append(c.OnRequestInitialization, hook)
 }
 
+// ClearOnRequestInitialization removes all hooks registered with AddOnRequestInitialization.
+func (c *Hooks) ClearOnRequestInitialization() {
+	c.OnRequestInitialization = nil
+}
+
 func (c *Hooks) onRequestInitialization(ctx context.Context, id any, message any) error {
 	if c == nil {
 		return nil
@@ -255,6 +332,24 @@ func (c *Hooks) onRequestInitialization(ctx context.Context, id any, message any
 	}
 	return nil
 }
+
+func (c *Hooks) AddOnProtocolVersionMismatch(hook OnProtocolVersionMismatchFunc) {
+	c.OnProtocolVersionMismatch = append(c.OnProtocolVersionMismatch, hook)
+}
+
+// ClearOnProtocolVersionMismatch removes all hooks registered with AddOnProtocolVersionMismatch.
+func (c *Hooks) ClearOnProtocolVersionMismatch() {
+	c.OnProtocolVersionMismatch = nil
+}
+
+func (c *Hooks) onProtocolVersionMismatch(ctx context.Context, id any, requested string, supported string) {
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnProtocolVersionMismatch {
+		hook(ctx, id, requested, supported)
+	}
+}
 func (c *Hooks) AddBeforeInitialize(hook OnBeforeInitializeFunc) {
 	c.OnBeforeInitialize = append(c.OnBeforeInitialize, hook)
 }
@@ -263,6 +358,16 @@ func (c *Hooks) AddAfterInitialize(hook OnAfterInitializeFunc) {
 	c.OnAfterInitialize = append(c.OnAfterInitialize, hook)
 }
 
+// ClearBeforeInitialize removes all hooks registered with AddBeforeInitialize.
+func (c *Hooks) ClearBeforeInitialize() {
+	c.OnBeforeInitialize = nil
+}
+
+// ClearAfterInitialize removes all hooks registered with AddAfterInitialize.
+func (c *Hooks) ClearAfterInitialize() {
+	c.OnAfterInitialize = nil
+}
+
 func (c *Hooks) beforeInitialize(ctx context.Context, id any, message *mcp.InitializeRequest) {
 	c.beforeAny(ctx, id, mcp.MethodInitialize, message)
 	if c == nil {
@@ -290,6 +395,16 @@ func (c *Hooks) AddAfterPing(hook OnAfterPingFunc) {
 	c.OnAfterPing = append(c.OnAfterPing, hook)
 }
 
+// ClearBeforePing removes all hooks registered with AddBeforePing.
+func (c *Hooks) ClearBeforePing() {
+	c.OnBeforePing = nil
+}
+
+// ClearAfterPing removes all hooks registered with AddAfterPing.
+func (c *Hooks) ClearAfterPing() {
+	c.OnAfterPing = nil
+}
+
 func (c *Hooks) beforePing(ctx context.Context, id any, message *mcp.PingRequest) {
 	c.beforeAny(ctx, id, mcp.MethodPing, message)
 	if c == nil {
@@ -317,6 +432,16 @@ func (c *Hooks) AddAfterListResources(hook OnAfterListResourcesFunc) {
 	c.OnAfterListResources = append(c.OnAfterListResources, hook)
 }
 
+// ClearBeforeListResources removes all hooks registered with AddBeforeListResources.
+func (c *Hooks) ClearBeforeListResources() {
+	c.OnBeforeListResources = nil
+}
+
+// ClearAfterListResources removes all hooks registered with AddAfterListResources.
+func (c *Hooks) ClearAfterListResources() {
+	c.OnAfterListResources = nil
+}
+
 func (c *Hooks) beforeListResources(ctx context.Context, id any, message *mcp.ListResourcesRequest) {
 	c.beforeAny(ctx, id, mcp.MethodResourcesList, message)
 	if c == nil {
@@ -344,6 +469,16 @@ func (c *Hooks) AddAfterListResourceTemplates(hook OnAfterListResourceTemplatesF
 	c.OnAfterListResourceTemplates = append(c.OnAfterListResourceTemplates, hook)
 }
 
+// ClearBeforeListResourceTemplates removes all hooks registered with AddBeforeListResourceTemplates.
+func (c *Hooks) ClearBeforeListResourceTemplates() {
+	c.OnBeforeListResourceTemplates = nil
+}
+
+// ClearAfterListResourceTemplates removes all hooks registered with AddAfterListResourceTemplates.
+func (c *Hooks) ClearAfterListResourceTemplates() {
+	c.OnAfterListResourceTemplates = nil
+}
+
 func (c *Hooks) beforeListResourceTemplates(ctx context.Context, id any, message *mcp.ListResourceTemplatesRequest) {
 	c.beforeAny(ctx, id, mcp.MethodResourcesTemplatesList, message)
 	if c == nil {
@@ -371,6 +506,16 @@ func (c *Hooks) AddAfterReadResource(hook OnAfterReadResourceFunc) {
 	c.OnAfterReadResource = append(c.OnAfterReadResource, hook)
 }
 
+// ClearBeforeReadResource removes all hooks registered with AddBeforeReadResource.
+func (c *Hooks) ClearBeforeReadResource() {
+	c.OnBeforeReadResource = nil
+}
+
+// ClearAfterReadResource removes all hooks registered with AddAfterReadResource.
+func (c *Hooks) ClearAfterReadResource() {
+	c.OnAfterReadResource = nil
+}
+
 func (c *Hooks) beforeReadResource(ctx context.Context, id any, message *mcp.ReadResourceRequest) {
 	c.beforeAny(ctx, id, mcp.MethodResourcesRead, message)
 	if c == nil {
@@ -390,6 +535,80 @@ func (c *Hooks) afterReadResource(ctx context.Context, id any, message *mcp.Read
 		hook(ctx, id, message, result)
 	}
 }
+func (c *Hooks) AddBeforeSubscribe(hook OnBeforeSubscribeFunc) {
+	c.OnBeforeSubscribe = append(c.OnBeforeSubscribe, hook)
+}
+
+func (c *Hooks) AddAfterSubscribe(hook OnAfterSubscribeFunc) {
+	c.OnAfterSubscribe = append(c.OnAfterSubscribe, hook)
+}
+
+// ClearBeforeSubscribe removes all hooks registered with AddBeforeSubscribe.
+func (c *Hooks) ClearBeforeSubscribe() {
+	c.OnBeforeSubscribe = nil
+}
+
+// ClearAfterSubscribe removes all hooks registered with AddAfterSubscribe.
+func (c *Hooks) ClearAfterSubscribe() {
+	c.OnAfterSubscribe = nil
+}
+
+func (c *Hooks) beforeSubscribe(ctx context.Context, id any, message *mcp.SubscribeRequest) {
+	c.beforeAny(ctx, id, mcp.MethodResourcesSubscribe, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeSubscribe {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterSubscribe(ctx context.Context, id any, message *mcp.SubscribeRequest, result *mcp.EmptyResult) {
+	c.onSuccess(ctx, id, mcp.MethodResourcesSubscribe, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterSubscribe {
+		hook(ctx, id, message, result)
+	}
+}
+func (c *Hooks) AddBeforeUnsubscribe(hook OnBeforeUnsubscribeFunc) {
+	c.OnBeforeUnsubscribe = append(c.OnBeforeUnsubscribe, hook)
+}
+
+func (c *Hooks) AddAfterUnsubscribe(hook OnAfterUnsubscribeFunc) {
+	c.OnAfterUnsubscribe = append(c.OnAfterUnsubscribe, hook)
+}
+
+// ClearBeforeUnsubscribe removes all hooks registered with AddBeforeUnsubscribe.
+func (c *Hooks) ClearBeforeUnsubscribe() {
+	c.OnBeforeUnsubscribe = nil
+}
+
+// ClearAfterUnsubscribe removes all hooks registered with AddAfterUnsubscribe.
+func (c *Hooks) ClearAfterUnsubscribe() {
+	c.OnAfterUnsubscribe = nil
+}
+
+func (c *Hooks) beforeUnsubscribe(ctx context.Context, id any, message *mcp.UnsubscribeRequest) {
+	c.beforeAny(ctx, id, mcp.MethodResourcesUnsubscribe, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeUnsubscribe {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterUnsubscribe(ctx context.Context, id any, message *mcp.UnsubscribeRequest, result *mcp.EmptyResult) {
+	c.onSuccess(ctx, id, mcp.MethodResourcesUnsubscribe, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterUnsubscribe {
+		hook(ctx, id, message, result)
+	}
+}
 func (c *Hooks) AddBeforeListPrompts(hook OnBeforeListPromptsFunc) {
 	c.OnBeforeListPrompts = append(c.OnBeforeListPrompts, hook)
 }
@@ -398,6 +617,16 @@ func (c *Hooks) AddAfterListPrompts(hook OnAfterListPromptsFunc) {
 	c.OnAfterListPrompts = append(c.OnAfterListPrompts, hook)
 }
 
+// ClearBeforeListPrompts removes all hooks registered with AddBeforeListPrompts.
+func (c *Hooks) ClearBeforeListPrompts() {
+	c.OnBeforeListPrompts = nil
+}
+
+// ClearAfterListPrompts removes all hooks registered with AddAfterListPrompts.
+func (c *Hooks) ClearAfterListPrompts() {
+	c.OnAfterListPrompts = nil
+}
+
 func (c *Hooks) beforeListPrompts(ctx context.Context, id any, message *mcp.ListPromptsRequest) {
 	c.beforeAny(ctx, id, mcp.MethodPromptsList, message)
 	if c == nil {
@@ -425,6 +654,16 @@ func (c *Hooks) AddAfterGetPrompt(hook OnAfterGetPromptFunc) {
 	c.OnAfterGetPrompt = append(c.OnAfterGetPrompt, hook)
 }
 
+// ClearBeforeGetPrompt removes all hooks registered with AddBeforeGetPrompt.
+func (c *Hooks) ClearBeforeGetPrompt() {
+	c.OnBeforeGetPrompt = nil
+}
+
+// ClearAfterGetPrompt removes all hooks registered with AddAfterGetPrompt.
+func (c *Hooks) ClearAfterGetPrompt() {
+	c.OnAfterGetPrompt = nil
+}
+
 func (c *Hooks) beforeGetPrompt(ctx context.Context, id any, message *mcp.GetPromptRequest) {
 	c.beforeAny(ctx, id, mcp.MethodPromptsGet, message)
 	if c == nil {
@@ -452,6 +691,16 @@ func (c *Hooks) AddAfterListTools(hook OnAfterListToolsFunc) {
 	c.OnAfterListTools = append(c.OnAfterListTools, hook)
 }
 
+// ClearBeforeListTools removes all hooks registered with AddBeforeListTools.
+func (c *Hooks) ClearBeforeListTools() {
+	c.OnBeforeListTools = nil
+}
+
+// ClearAfterListTools removes all hooks registered with AddAfterListTools.
+func (c *Hooks) ClearAfterListTools() {
+	c.OnAfterListTools = nil
+}
+
 func (c *Hooks) beforeListTools(ctx context.Context, id any, message *mcp.ListToolsRequest) {
 	c.beforeAny(ctx, id, mcp.MethodToolsList, message)
 	if c == nil {
@@ -479,6 +728,16 @@ func (c *Hooks) AddAfterCallTool(hook OnAfterCallToolFunc) {
 	c.OnAfterCallTool = append(c.OnAfterCallTool, hook)
 }
 
+// ClearBeforeCallTool removes all hooks registered with AddBeforeCallTool.
+func (c *Hooks) ClearBeforeCallTool() {
+	c.OnBeforeCallTool = nil
+}
+
+// ClearAfterCallTool removes all hooks registered with AddAfterCallTool.
+func (c *Hooks) ClearAfterCallTool() {
+	c.OnAfterCallTool = nil
+}
+
 func (c *Hooks) beforeCallTool(ctx context.Context, id any, message *mcp.CallToolRequest) {
 	c.beforeAny(ctx, id, mcp.MethodToolsCall, message)
 	if c == nil {
@@ -498,3 +757,77 @@ func (c *Hooks) afterCallTool(ctx context.Context, id any, message *mcp.CallTool
 		hook(ctx, id, message, result)
 	}
 }
+func (c *Hooks) AddBeforeComplete(hook OnBeforeCompleteFunc) {
+	c.OnBeforeComplete = append(c.OnBeforeComplete, hook)
+}
+
+func (c *Hooks) AddAfterComplete(hook OnAfterCompleteFunc) {
+	c.OnAfterComplete = append(c.OnAfterComplete, hook)
+}
+
+// ClearBeforeComplete removes all hooks registered with AddBeforeComplete.
+func (c *Hooks) ClearBeforeComplete() {
+	c.OnBeforeComplete = nil
+}
+
+// ClearAfterComplete removes all hooks registered with AddAfterComplete.
+func (c *Hooks) ClearAfterComplete() {
+	c.OnAfterComplete = nil
+}
+
+func (c *Hooks) beforeComplete(ctx context.Context, id any, message *mcp.CompleteRequest) {
+	c.beforeAny(ctx, id, mcp.MethodCompletionComplete, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeComplete {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterComplete(ctx context.Context, id any, message *mcp.CompleteRequest, result *mcp.CompleteResult) {
+	c.onSuccess(ctx, id, mcp.MethodCompletionComplete, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterComplete {
+		hook(ctx, id, message, result)
+	}
+}
+func (c *Hooks) AddBeforeSetLevel(hook OnBeforeSetLevelFunc) {
+	c.OnBeforeSetLevel = append(c.OnBeforeSetLevel, hook)
+}
+
+func (c *Hooks) AddAfterSetLevel(hook OnAfterSetLevelFunc) {
+	c.OnAfterSetLevel = append(c.OnAfterSetLevel, hook)
+}
+
+// ClearBeforeSetLevel removes all hooks registered with AddBeforeSetLevel.
+func (c *Hooks) ClearBeforeSetLevel() {
+	c.OnBeforeSetLevel = nil
+}
+
+// ClearAfterSetLevel removes all hooks registered with AddAfterSetLevel.
+func (c *Hooks) ClearAfterSetLevel() {
+	c.OnAfterSetLevel = nil
+}
+
+func (c *Hooks) beforeSetLevel(ctx context.Context, id any, message *mcp.SetLevelRequest) {
+	c.beforeAny(ctx, id, mcp.MethodLoggingSetLevel, message)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnBeforeSetLevel {
+		hook(ctx, id, message)
+	}
+}
+
+func (c *Hooks) afterSetLevel(ctx context.Context, id any, message *mcp.SetLevelRequest, result *mcp.EmptyResult) {
+	c.onSuccess(ctx, id, mcp.MethodLoggingSetLevel, message, result)
+	if c == nil {
+		return
+	}
+	for _, hook := range c.OnAfterSetLevel {
+		hook(ctx, id, message, result)
+	}
+}
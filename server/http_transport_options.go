@@ -22,6 +22,7 @@ type httpTransportConfigurable interface {
 	setContextFunc(HTTPContextFunc)
 	setHTTPServer(*http.Server)
 	setBaseURL(string)
+	setIDGenerator(func() string)
 }
 
 // HTTPTransportOption is a function that configures an httpTransportConfigurable.
@@ -85,6 +86,7 @@ func (s *StreamableHTTPServer) setKeepAlive(bool)                      {}
 func (s *StreamableHTTPServer) setContextFunc(HTTPContextFunc)         {}
 func (s *StreamableHTTPServer) setHTTPServer(srv *http.Server)         {}
 func (s *StreamableHTTPServer) setBaseURL(baseURL string)              {}
+func (s *StreamableHTTPServer) setIDGenerator(func() string)           {}
 
 // Ensure the option types implement the correct interfaces
 var (
@@ -187,3 +189,14 @@ func WithHTTPServer(srv *http.Server) CommonHTTPServerOption {
 		},
 	}
 }
+
+// WithIDGenerator sets the function generating session IDs, e.g. to make
+// them deterministic in tests. It must return a unique ID on every call.
+// By default a random UUID is used.
+func WithIDGenerator(fn func() string) CommonHTTPServerOption {
+	return commonOption{
+		apply: func(c httpTransportConfigurable) {
+			c.setIDGenerator(fn)
+		},
+	}
+}
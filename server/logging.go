@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// loggingLevelKey is the session state key for the level the client set with
+// logging/setLevel.
+type loggingLevelKey struct{}
+
+// handleSetLevel validates the requested level and records it for the
+// current session.
+func (s *MCPServer) handleSetLevel(
+	ctx context.Context,
+	id any,
+	request mcp.SetLevelRequest,
+) (*mcp.EmptyResult, *requestError) {
+	if !s.capabilities.logging {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.METHOD_NOT_FOUND,
+			err:  fmt.Errorf("logging %w", ErrUnsupported),
+		}
+	}
+	level := request.Params.Level
+	if !level.IsValid() {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("invalid logging level %q", level),
+		}
+	}
+	if state := SessionStateFromContext(ctx); state != nil {
+		state.Set(loggingLevelKey{}, level)
+	}
+	return &mcp.EmptyResult{}, nil
+}
+
+// LoggingLevelFromContext returns the level the client of the current
+// session set with logging/setLevel. It returns false if the client has not
+// set one or ctx carries no registered session.
+func LoggingLevelFromContext(ctx context.Context) (mcp.LoggingLevel, bool) {
+	state := SessionStateFromContext(ctx)
+	if state == nil {
+		return "", false
+	}
+	level, ok := state.Get(loggingLevelKey{})
+	if !ok {
+		return "", false
+	}
+	return level.(mcp.LoggingLevel), true
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_SetLevel(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithLogging())
+	session := &sessionTestClient{
+		sessionID:           "session-1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		initialized:         true,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	ctx := server.WithContext(context.Background(), session)
+
+	setLevel := func(srv *MCPServer, level string) mcp.JSONRPCMessage {
+		return srv.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "logging/setLevel", "params": {"level": "`+level+`"}}`))
+	}
+
+	// Handlers see the server in their context as well
+	handlerCtx := context.WithValue(ctx, serverKey{}, server)
+	_, ok := LoggingLevelFromContext(handlerCtx)
+	assert.False(t, ok, "no level before logging/setLevel")
+
+	response := setLevel(server, "warning")
+	_, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected success, got %#v", response)
+	level, ok := LoggingLevelFromContext(handlerCtx)
+	assert.True(t, ok)
+	assert.Equal(t, mcp.LoggingLevelWarning, level)
+
+	for _, invalid := range []string{"verbose", "WARNING", ""} {
+		response := setLevel(server, invalid)
+		errResp, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok, "expected error for %q, got %#v", invalid, response)
+		assert.Equal(t, mcp.INVALID_PARAMS, errResp.Error.Code, invalid)
+	}
+	level, _ = LoggingLevelFromContext(handlerCtx)
+	assert.Equal(t, mcp.LoggingLevelWarning, level, "rejected levels are not recorded")
+
+	t.Run("Logging not enabled", func(t *testing.T) {
+		response := setLevel(NewMCPServer("test-server", "1.0.0"), "info")
+		errResp, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.METHOD_NOT_FOUND, errResp.Error.Code)
+	})
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// progressTokenKey is the context key for the progress token of the request
+// being handled.
+type progressTokenKey struct{}
+
+// withProgressToken returns a copy of ctx carrying token, or ctx itself if
+// the caller did not ask for progress notifications.
+func withProgressToken(ctx context.Context, token mcp.ProgressToken) context.Context {
+	if token == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressTokenKey{}, token)
+}
+
+// ProgressTokenFromContext returns the progress token the client set in
+// params._meta.progressToken of the tool call being handled, or nil if it
+// did not ask for progress notifications.
+func ProgressTokenFromContext(ctx context.Context) mcp.ProgressToken {
+	return ctx.Value(progressTokenKey{})
+}
+
+// SendProgressNotification reports the progress of the tool call being
+// handled to the client with a notifications/progress notification carrying
+// the call's progress token. A total of zero means the total is unknown, and
+// an empty message is omitted. If the client did not ask for progress
+// notifications, nothing is sent and nil is returned.
+func SendProgressNotification(ctx context.Context, progress, total float64, message string) error {
+	token := ProgressTokenFromContext(ctx)
+	if token == nil {
+		return nil
+	}
+	srv := ServerFromContext(ctx)
+	if srv == nil {
+		return ErrNotificationNotInitialized
+	}
+
+	params := map[string]any{
+		"progressToken": token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	return srv.SendNotificationToClient(ctx, mcp.MethodNotificationProgress, params)
+}
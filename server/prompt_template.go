@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// AddPromptFromTemplate registers a prompt whose message text is produced by
+// rendering a Go text/template with the arguments supplied in prompts/get.
+//
+// Template variables are referenced as fields of the dot, e.g. {{.topic}}.
+// If args is nil, the prompt arguments are derived from the fields referenced
+// by the template, in order of first appearance. Arguments missing from the
+// request render as empty strings.
+func (s *MCPServer) AddPromptFromTemplate(
+	name, description string,
+	tmplText string,
+	args []mcp.PromptArgument,
+) error {
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(tmplText)
+	if err != nil {
+		return fmt.Errorf("failed to parse template for prompt %s: %w", name, err)
+	}
+
+	if args == nil {
+		for _, field := range templateFields(tmpl) {
+			args = append(args, mcp.PromptArgument{Name: field})
+		}
+	}
+
+	prompt := mcp.Prompt{
+		Name:        name,
+		Description: description,
+		Arguments:   args,
+	}
+
+	s.AddPrompt(prompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		data := make(map[string]string, len(prompt.Arguments))
+		for _, arg := range prompt.Arguments {
+			data[arg.Name] = ""
+		}
+		for k, v := range request.Params.Arguments {
+			data[k] = v
+		}
+
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("failed to render prompt %s: %w", name, err)
+		}
+
+		return mcp.NewGetPromptResult(
+			description,
+			[]mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(sb.String())),
+			},
+		), nil
+	})
+
+	return nil
+}
+
+// AddPromptsFromFS registers a template prompt for every file in fsys matching
+// glob, as understood by fs.Glob. Each file is parsed as a Go text/template,
+// see AddPromptFromTemplate.
+//
+// The prompt name is the file name without its extension. If the template
+// starts with a comment, e.g. {{/* Summarize a document */}}, the comment text
+// is used as the prompt description.
+func (s *MCPServer) AddPromptsFromFS(fsys fs.FS, glob string) error {
+	matches, err := fs.Glob(fsys, glob)
+	if err != nil {
+		return fmt.Errorf("invalid prompt glob %q: %w", glob, err)
+	}
+
+	for _, match := range matches {
+		content, err := fs.ReadFile(fsys, match)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt file %s: %w", match, err)
+		}
+
+		base := path.Base(match)
+		name := strings.TrimSuffix(base, path.Ext(base))
+		text := string(content)
+
+		if err := s.AddPromptFromTemplate(name, templateDescription(text), text, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// templateDescription returns the text of a leading {{/* ... */}} comment.
+func templateDescription(text string) string {
+	trimmed := strings.TrimSpace(text)
+	for _, open := range []string{"{{/*", "{{- /*"} {
+		if !strings.HasPrefix(trimmed, open) {
+			continue
+		}
+		end := strings.Index(trimmed, "*/")
+		if end < 0 {
+			return ""
+		}
+		return strings.TrimSpace(trimmed[len(open):end])
+	}
+	return ""
+}
+
+// templateFields returns the names of the top-level fields referenced by the
+// template, in order of first appearance. Fields referenced inside range and
+// with blocks are skipped, since the dot no longer refers to the arguments.
+func templateFields(tmpl *template.Template) []string {
+	var fields []string
+	seen := make(map[string]bool)
+
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			fields = append(fields, name)
+		}
+	}
+
+	var walkPipe func(pipe *parse.PipeNode)
+	var walk func(node parse.Node)
+
+	walkPipe = func(pipe *parse.PipeNode) {
+		if pipe == nil {
+			return
+		}
+		for _, cmd := range pipe.Cmds {
+			for _, arg := range cmd.Args {
+				walk(arg)
+			}
+		}
+	}
+
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walkPipe(n.Pipe)
+		case *parse.PipeNode:
+			walkPipe(n)
+		case *parse.FieldNode:
+			if len(n.Ident) > 0 {
+				add(n.Ident[0])
+			}
+		case *parse.IfNode:
+			walkPipe(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walkPipe(n.Pipe)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walkPipe(n.Pipe)
+			walk(n.ElseList)
+		}
+	}
+
+	if tmpl.Tree != nil {
+		walk(tmpl.Tree.Root)
+	}
+	return fields
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_AddPromptFromTemplate(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithPromptCapabilities(true))
+
+	err := server.AddPromptFromTemplate(
+		"greeting",
+		"Greets someone",
+		"Hello {{.name}}, welcome to {{.place}}!{{if .note}} Note: {{.note}}{{end}}",
+		nil,
+	)
+	require.NoError(t, err)
+
+	err = server.AddPromptFromTemplate("broken", "", "Hello {{.name", nil)
+	assert.Error(t, err)
+
+	tests := []struct {
+		name     string
+		message  string
+		validate func(t *testing.T, response mcp.JSONRPCMessage)
+	}{
+		{
+			name: "List prompts exposes template variables as arguments",
+			message: `{
+				"jsonrpc": "2.0",
+				"id": 1,
+				"method": "prompts/list"
+			}`,
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				resp, ok := response.(mcp.JSONRPCResponse)
+				require.True(t, ok)
+
+				result, ok := resp.Result.(mcp.ListPromptsResult)
+				require.True(t, ok)
+				require.Len(t, result.Prompts, 1)
+				assert.Equal(t, "greeting", result.Prompts[0].Name)
+				assert.Equal(t, "Greets someone", result.Prompts[0].Description)
+
+				var names []string
+				for _, arg := range result.Prompts[0].Arguments {
+					names = append(names, arg.Name)
+				}
+				assert.Equal(t, []string{"name", "place", "note"}, names)
+			},
+		},
+		{
+			name: "Get prompt renders arguments",
+			message: `{
+				"jsonrpc": "2.0",
+				"id": 1,
+				"method": "prompts/get",
+				"params": {
+					"name": "greeting",
+					"arguments": {"name": "Ada", "place": "London", "note": "bring tea"}
+				}
+			}`,
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				resp, ok := response.(mcp.JSONRPCResponse)
+				require.True(t, ok)
+
+				result, ok := resp.Result.(mcp.GetPromptResult)
+				require.True(t, ok)
+				require.Len(t, result.Messages, 1)
+				assert.Equal(t, mcp.RoleUser, result.Messages[0].Role)
+				textContent, ok := result.Messages[0].Content.(mcp.TextContent)
+				require.True(t, ok)
+				assert.Equal(t, "Hello Ada, welcome to London! Note: bring tea", textContent.Text)
+			},
+		},
+		{
+			name: "Get prompt with missing argument",
+			message: `{
+				"jsonrpc": "2.0",
+				"id": 1,
+				"method": "prompts/get",
+				"params": {
+					"name": "greeting",
+					"arguments": {"name": "Ada"}
+				}
+			}`,
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				resp, ok := response.(mcp.JSONRPCResponse)
+				require.True(t, ok)
+
+				result, ok := resp.Result.(mcp.GetPromptResult)
+				require.True(t, ok)
+				require.Len(t, result.Messages, 1)
+				textContent, ok := result.Messages[0].Content.(mcp.TextContent)
+				require.True(t, ok)
+				assert.Equal(t, "Hello Ada, welcome to !", textContent.Text)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := server.HandleMessage(context.Background(), []byte(tt.message))
+			tt.validate(t, response)
+		})
+	}
+}
+
+func TestMCPServer_AddPromptsFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"prompts/summarize.md": {
+			Data: []byte("{{/* Summarize a document */}}Summarize the following in {{.style}} style:\n\n{{.text}}"),
+		},
+		"prompts/review.md": {
+			Data: []byte("Review this {{.language}} code."),
+		},
+		"prompts/ignored.txt": {
+			Data: []byte("Not a prompt"),
+		},
+	}
+
+	server := NewMCPServer("test-server", "1.0.0", WithPromptCapabilities(true))
+	require.NoError(t, server.AddPromptsFromFS(fsys, "prompts/*.md"))
+
+	response := server.HandleMessage(context.Background(), []byte(`{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "prompts/list"
+	}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	result, ok := resp.Result.(mcp.ListPromptsResult)
+	require.True(t, ok)
+	require.Len(t, result.Prompts, 2)
+	assert.Equal(t, "review", result.Prompts[0].Name)
+	assert.Equal(t, "summarize", result.Prompts[1].Name)
+	assert.Equal(t, "Summarize a document", result.Prompts[1].Description)
+	require.Len(t, result.Prompts[1].Arguments, 2)
+	assert.Equal(t, "style", result.Prompts[1].Arguments[0].Name)
+	assert.Equal(t, "text", result.Prompts[1].Arguments[1].Name)
+
+	response = server.HandleMessage(context.Background(), []byte(`{
+		"jsonrpc": "2.0",
+		"id": 2,
+		"method": "prompts/get",
+		"params": {
+			"name": "summarize",
+			"arguments": {"style": "terse", "text": "MCP is a protocol."}
+		}
+	}`))
+	resp, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	getResult, ok := resp.Result.(mcp.GetPromptResult)
+	require.True(t, ok)
+	require.Len(t, getResult.Messages, 1)
+	textContent, ok := getResult.Messages[0].Content.(mcp.TextContent)
+	require.True(t, ok)
+	assert.Equal(t, "Summarize the following in terse style:\n\nMCP is a protocol.", textContent.Text)
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// WithRequestDeduplication makes the server remember, per session, the ids of
+// the requests it handled during the last window. A request repeating the id
+// of such a request is not handled again: it receives the response of the
+// original request, waiting for it if the original is still being processed.
+// This protects non-idempotent tools from clients that retry requests.
+func WithRequestDeduplication(window time.Duration) ServerOption {
+	return func(s *MCPServer) {
+		s.requestDedup = &requestDedup{
+			window:  window,
+			entries: make(map[dedupKey]*dedupEntry),
+		}
+	}
+}
+
+// dedupKey identifies a request within a session. The id is formatted with
+// its type so that the number 1 and the string "1" remain distinct.
+type dedupKey struct {
+	sessionID string
+	id        string
+}
+
+// dedupEntry tracks a request in flight or recently handled.
+type dedupEntry struct {
+	done     chan struct{}
+	response mcp.JSONRPCMessage
+	expires  time.Time
+}
+
+// requestDedup holds the requests seen within the deduplication window.
+type requestDedup struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[dedupKey]*dedupEntry
+}
+
+// handle calls handler unless a request with the same id was already seen in
+// the session of ctx, in which case the original response is returned.
+func (d *requestDedup) handle(ctx context.Context, id any, handler func() mcp.JSONRPCMessage) mcp.JSONRPCMessage {
+	key := dedupKey{id: fmt.Sprintf("%T:%v", id, id)}
+	if session := ClientSessionFromContext(ctx); session != nil {
+		key.sessionID = session.SessionID()
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	for k, entry := range d.entries {
+		if !entry.expires.IsZero() && now.After(entry.expires) {
+			delete(d.entries, k)
+		}
+	}
+	entry, duplicate := d.entries[key]
+	if !duplicate {
+		entry = &dedupEntry{done: make(chan struct{})}
+		d.entries[key] = entry
+	}
+	d.mu.Unlock()
+
+	if duplicate {
+		select {
+		case <-entry.done:
+			return entry.response
+		case <-ctx.Done():
+			return createErrorResponse(
+				id,
+				mcp.INVALID_REQUEST,
+				fmt.Sprintf("duplicate request id %v is still being processed", id),
+			)
+		}
+	}
+
+	response := handler()
+
+	d.mu.Lock()
+	entry.response = response
+	entry.expires = time.Now().Add(d.window)
+	d.mu.Unlock()
+	close(entry.done)
+
+	return response
+}
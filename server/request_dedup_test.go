@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_WithRequestDeduplication(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithRequestDeduplication(time.Minute))
+
+	var calls atomic.Int32
+	server.AddTool(mcp.NewTool("charge-card"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return mcp.NewToolResultText("charged"), nil
+	})
+
+	session := &sessionTestClient{
+		sessionID:           "dedup-session",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+	}
+	session.Initialize()
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	ctx := server.WithContext(context.Background(), session)
+
+	callMessage := func(id int) []byte {
+		return []byte(fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": %d,
+			"method": "tools/call",
+			"params": {
+				"name": "charge-card"
+			}
+		}`, id))
+	}
+
+	// The same id sent twice rapidly runs the handler once
+	var wg sync.WaitGroup
+	responses := make([]mcp.JSONRPCMessage, 2)
+	for i := range responses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i] = server.HandleMessage(ctx, callMessage(1))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, response := range responses {
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		assert.Equal(t, float64(1), resp.ID)
+		result, ok := resp.Result.(mcp.CallToolResult)
+		require.True(t, ok)
+		assert.Equal(t, "charged", result.Content[0].(mcp.TextContent).Text)
+	}
+
+	// A retry after completion gets the cached response
+	_, ok := server.HandleMessage(ctx, callMessage(1)).(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// A new id is handled normally
+	server.HandleMessage(ctx, callMessage(2))
+	assert.Equal(t, int32(2), calls.Load())
+
+	// The same id in another session is not a duplicate
+	server.HandleMessage(context.Background(), callMessage(1))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestMCPServer_RequestDeduplicationWindow(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithRequestDeduplication(10*time.Millisecond))
+
+	var calls atomic.Int32
+	server.AddTool(mcp.NewTool("counter"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	message := []byte(`{"jsonrpc": "2.0", "id": "req-1", "method": "tools/call", "params": {"name": "counter"}}`)
+	server.HandleMessage(context.Background(), message)
+	server.HandleMessage(context.Background(), message)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// Once the window has passed, the id can be reused
+	time.Sleep(20 * time.Millisecond)
+	server.HandleMessage(context.Background(), message)
+	assert.Equal(t, int32(2), calls.Load())
+}
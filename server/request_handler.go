@@ -5,7 +5,9 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/zillow/mcp-go/mcp"
 )
@@ -15,18 +17,21 @@ func (s *MCPServer) HandleMessage(
 	ctx context.Context,
 	message json.RawMessage,
 ) mcp.JSONRPCMessage {
-	// Add server to context
+	// Add server and start time to context
 	ctx = context.WithValue(ctx, serverKey{}, s)
-	var err *requestError
+	ctx = context.WithValue(ctx, requestStartKey{}, time.Now())
 
 	var baseMessage struct {
-		JSONRPC string        `json:"jsonrpc"`
-		Method  mcp.MCPMethod `json:"method"`
-		ID      any           `json:"id,omitempty"`
-		Result  any           `json:"result,omitempty"`
+		JSONRPC *string         `json:"jsonrpc"`
+		Method  mcp.MCPMethod   `json:"method"`
+		ID      json.RawMessage `json:"id,omitempty"`
+		Result  any             `json:"result,omitempty"`
 	}
 
+	// Every error answered with a JSON-RPC error below is also reported to
+	// the OnError hooks, wrapping UnparsableMessageError or ErrUnsupported.
 	if err := json.Unmarshal(message, &baseMessage); err != nil {
+		s.hooks.onError(ctx, nil, "", message, &UnparsableMessageError{message: message, err: err})
 		return createErrorResponse(
 			nil,
 			mcp.PARSE_ERROR,
@@ -34,18 +39,47 @@ func (s *MCPServer) HandleMessage(
 		)
 	}
 
-	// Check for valid JSONRPC version
-	if baseMessage.JSONRPC != mcp.JSONRPC_VERSION {
+	id, err := parseRequestID(baseMessage.ID)
+	if err != nil {
+		s.hooks.onError(ctx, nil, baseMessage.Method, message,
+			&UnparsableMessageError{message: message, err: err, method: baseMessage.Method})
 		return createErrorResponse(
-			baseMessage.ID,
+			nil,
+			mcp.INVALID_REQUEST,
+			"Invalid request id",
+		)
+	}
+
+	// Check for valid JSONRPC version, which WithLenientJSONRPCVersion allows
+	// to be omitted
+	if version := baseMessage.JSONRPC; version == nil && !s.lenientJSONRPCVersion ||
+		version != nil && *version != mcp.JSONRPC_VERSION {
+		var got string
+		if version != nil {
+			got = *version
+		}
+		s.hooks.onError(ctx, id, baseMessage.Method, message,
+			fmt.Errorf("JSON-RPC version %q %w", got, ErrUnsupported))
+		return createErrorResponse(
+			id,
 			mcp.INVALID_REQUEST,
 			"Invalid JSON-RPC version",
 		)
 	}
 
-	if baseMessage.ID == nil {
+	ctx = context.WithValue(ctx, requestMethodKey{}, baseMessage.Method)
+	if id != nil {
+		ctx = context.WithValue(ctx, requestIDKey{}, mcp.RequestId(id))
+		if s.responseCorrelationID {
+			ctx = context.WithValue(ctx, correlationIDKey{}, newCorrelationID())
+		}
+	}
+
+	if id == nil {
 		var notification mcp.JSONRPCNotification
 		if err := json.Unmarshal(message, &notification); err != nil {
+			s.hooks.onError(ctx, nil, baseMessage.Method, message,
+				&UnparsableMessageError{message: message, err: err, method: baseMessage.Method})
 			return createErrorResponse(
 				nil,
 				mcp.PARSE_ERROR,
@@ -62,234 +96,402 @@ func (s *MCPServer) HandleMessage(
 		return nil
 	}
 
-	handleErr := s.hooks.onRequestInitialization(ctx, baseMessage.ID, message)
+	handleErr := s.hooks.onRequestInitialization(ctx, id, message)
 	if handleErr != nil {
+		s.hooks.onError(ctx, id, baseMessage.Method, message, handleErr)
+		code := mcp.INVALID_REQUEST
+		var rejectedErr *RequestRejectedError
+		if errors.As(handleErr, &rejectedErr) {
+			code = rejectedErr.Code
+		}
 		return createErrorResponse(
-			baseMessage.ID,
-			mcp.INVALID_REQUEST,
+			id,
+			code,
 			handleErr.Error(),
 		)
 	}
 
-	switch baseMessage.Method {
+	if s.requestDedup != nil {
+		return s.requestDedup.handle(ctx, id, func() mcp.JSONRPCMessage {
+			return s.handleRequest(ctx, id, baseMessage.Method, message)
+		})
+	}
+	return s.handleRequest(ctx, id, baseMessage.Method, message)
+}
+
+// handleRequest dispatches a request to the handler registered for its method.
+func (s *MCPServer) handleRequest(
+	ctx context.Context,
+	id any,
+	method mcp.MCPMethod,
+	message json.RawMessage,
+) mcp.JSONRPCMessage {
+	var err *requestError
+	switch method {
 	case mcp.MethodInitialize:
 		var request mcp.InitializeRequest
 		var result *mcp.InitializeResult
 		if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
-				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
 			}
 		} else {
-			s.hooks.beforeInitialize(ctx, baseMessage.ID, &request)
-			result, err = s.handleInitialize(ctx, baseMessage.ID, request)
+			s.hooks.beforeInitialize(ctx, id, &request)
+			result, err = s.handleInitialize(ctx, id, request)
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.hooks.onError(ctx, id, method, &request, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterInitialize(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterInitialize(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
 	case mcp.MethodPing:
 		var request mcp.PingRequest
 		var result *mcp.EmptyResult
 		if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
-				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
 			}
 		} else {
-			s.hooks.beforePing(ctx, baseMessage.ID, &request)
-			result, err = s.handlePing(ctx, baseMessage.ID, request)
+			s.hooks.beforePing(ctx, id, &request)
+			result, err = s.handlePing(ctx, id, request)
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.hooks.onError(ctx, id, method, &request, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterPing(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterPing(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
 	case mcp.MethodResourcesList:
 		var request mcp.ListResourcesRequest
 		var result *mcp.ListResourcesResult
 		if s.capabilities.resources == nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.METHOD_NOT_FOUND,
 				err:  fmt.Errorf("resources %w", ErrUnsupported),
 			}
 		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
-				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
 			}
 		} else {
-			s.hooks.beforeListResources(ctx, baseMessage.ID, &request)
-			result, err = s.handleListResources(ctx, baseMessage.ID, request)
+			s.hooks.beforeListResources(ctx, id, &request)
+			result, err = s.handleListResources(ctx, id, request)
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.hooks.onError(ctx, id, method, &request, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterListResources(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterListResources(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
 	case mcp.MethodResourcesTemplatesList:
 		var request mcp.ListResourceTemplatesRequest
 		var result *mcp.ListResourceTemplatesResult
 		if s.capabilities.resources == nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.METHOD_NOT_FOUND,
 				err:  fmt.Errorf("resources %w", ErrUnsupported),
 			}
 		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
-				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
 			}
 		} else {
-			s.hooks.beforeListResourceTemplates(ctx, baseMessage.ID, &request)
-			result, err = s.handleListResourceTemplates(ctx, baseMessage.ID, request)
+			s.hooks.beforeListResourceTemplates(ctx, id, &request)
+			result, err = s.handleListResourceTemplates(ctx, id, request)
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.hooks.onError(ctx, id, method, &request, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterListResourceTemplates(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterListResourceTemplates(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
 	case mcp.MethodResourcesRead:
 		var request mcp.ReadResourceRequest
 		var result *mcp.ReadResourceResult
 		if s.capabilities.resources == nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
+				code: mcp.METHOD_NOT_FOUND,
+				err:  fmt.Errorf("resources %w", ErrUnsupported),
+			}
+		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   id,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
+			}
+		} else {
+			s.hooks.beforeReadResource(ctx, id, &request)
+			result, err = s.handleReadResource(ctx, id, request)
+		}
+		if err != nil {
+			s.hooks.onError(ctx, id, method, &request, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterReadResource(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
+	case mcp.MethodResourcesSubscribe:
+		var request mcp.SubscribeRequest
+		var result *mcp.EmptyResult
+		if s.capabilities.resources == nil {
+			err = &requestError{
+				id:   id,
+				code: mcp.METHOD_NOT_FOUND,
+				err:  fmt.Errorf("resources %w", ErrUnsupported),
+			}
+		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   id,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
+			}
+		} else {
+			s.hooks.beforeSubscribe(ctx, id, &request)
+			result, err = s.handleSubscribe(ctx, id, request)
+		}
+		if err != nil {
+			s.hooks.onError(ctx, id, method, &request, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterSubscribe(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
+	case mcp.MethodResourcesUnsubscribe:
+		var request mcp.UnsubscribeRequest
+		var result *mcp.EmptyResult
+		if s.capabilities.resources == nil {
+			err = &requestError{
+				id:   id,
 				code: mcp.METHOD_NOT_FOUND,
 				err:  fmt.Errorf("resources %w", ErrUnsupported),
 			}
 		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
-				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
 			}
 		} else {
-			s.hooks.beforeReadResource(ctx, baseMessage.ID, &request)
-			result, err = s.handleReadResource(ctx, baseMessage.ID, request)
+			s.hooks.beforeUnsubscribe(ctx, id, &request)
+			result, err = s.handleUnsubscribe(ctx, id, request)
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.hooks.onError(ctx, id, method, &request, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterReadResource(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterUnsubscribe(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
 	case mcp.MethodPromptsList:
 		var request mcp.ListPromptsRequest
 		var result *mcp.ListPromptsResult
 		if s.capabilities.prompts == nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.METHOD_NOT_FOUND,
 				err:  fmt.Errorf("prompts %w", ErrUnsupported),
 			}
 		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
-				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
 			}
 		} else {
-			s.hooks.beforeListPrompts(ctx, baseMessage.ID, &request)
-			result, err = s.handleListPrompts(ctx, baseMessage.ID, request)
+			s.hooks.beforeListPrompts(ctx, id, &request)
+			result, err = s.handleListPrompts(ctx, id, request)
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.hooks.onError(ctx, id, method, &request, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterListPrompts(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterListPrompts(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
 	case mcp.MethodPromptsGet:
 		var request mcp.GetPromptRequest
 		var result *mcp.GetPromptResult
 		if s.capabilities.prompts == nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.METHOD_NOT_FOUND,
 				err:  fmt.Errorf("prompts %w", ErrUnsupported),
 			}
 		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
-				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
 			}
 		} else {
-			s.hooks.beforeGetPrompt(ctx, baseMessage.ID, &request)
-			result, err = s.handleGetPrompt(ctx, baseMessage.ID, request)
+			s.hooks.beforeGetPrompt(ctx, id, &request)
+			result, err = s.handleGetPrompt(ctx, id, request)
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.hooks.onError(ctx, id, method, &request, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterGetPrompt(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterGetPrompt(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
 	case mcp.MethodToolsList:
 		var request mcp.ListToolsRequest
 		var result *mcp.ListToolsResult
 		if s.capabilities.tools == nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.METHOD_NOT_FOUND,
 				err:  fmt.Errorf("tools %w", ErrUnsupported),
 			}
 		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.INVALID_REQUEST,
-				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
 			}
 		} else {
-			s.hooks.beforeListTools(ctx, baseMessage.ID, &request)
-			result, err = s.handleListTools(ctx, baseMessage.ID, request)
+			s.hooks.beforeListTools(ctx, id, &request)
+			result, err = s.handleListTools(ctx, id, request)
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.hooks.onError(ctx, id, method, &request, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterListTools(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterListTools(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
 	case mcp.MethodToolsCall:
 		var request mcp.CallToolRequest
 		var result *mcp.CallToolResult
 		if s.capabilities.tools == nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
 				code: mcp.METHOD_NOT_FOUND,
 				err:  fmt.Errorf("tools %w", ErrUnsupported),
 			}
 		} else if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
 			err = &requestError{
-				id:   baseMessage.ID,
+				id:   id,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
+			}
+		} else {
+			s.hooks.beforeCallTool(ctx, id, &request)
+			result, err = s.handleToolCall(ctx, id, request)
+		}
+		if err != nil {
+			s.hooks.onError(ctx, id, method, &request, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterCallTool(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
+	case mcp.MethodCompletionComplete:
+		var request mcp.CompleteRequest
+		var result *mcp.CompleteResult
+		if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   id,
 				code: mcp.INVALID_REQUEST,
-				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: baseMessage.Method},
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
 			}
 		} else {
-			s.hooks.beforeCallTool(ctx, baseMessage.ID, &request)
-			result, err = s.handleToolCall(ctx, baseMessage.ID, request)
+			s.hooks.beforeComplete(ctx, id, &request)
+			result, err = s.handleComplete(ctx, id, request)
 		}
 		if err != nil {
-			s.hooks.onError(ctx, baseMessage.ID, baseMessage.Method, &request, err)
+			s.hooks.onError(ctx, id, method, &request, err)
 			return err.ToJSONRPCError()
 		}
-		s.hooks.afterCallTool(ctx, baseMessage.ID, &request, result)
-		return createResponse(baseMessage.ID, *result)
+		s.hooks.afterComplete(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
+	case mcp.MethodLoggingSetLevel:
+		var request mcp.SetLevelRequest
+		var result *mcp.EmptyResult
+		if unmarshalErr := json.Unmarshal(message, &request); unmarshalErr != nil {
+			err = &requestError{
+				id:   id,
+				code: mcp.INVALID_REQUEST,
+				err:  &UnparsableMessageError{message: message, err: unmarshalErr, method: method},
+			}
+		} else {
+			s.hooks.beforeSetLevel(ctx, id, &request)
+			result, err = s.handleSetLevel(ctx, id, request)
+		}
+		if err != nil {
+			s.hooks.onError(ctx, id, method, &request, err)
+			return err.ToJSONRPCError()
+		}
+		s.hooks.afterSetLevel(ctx, id, &request, result)
+		response := *result
+		if correlationID, ok := CorrelationIDFromContext(ctx); ok {
+			response.Meta = withCorrelationID(response.Meta, correlationID)
+		}
+		return createResponse(id, response)
 	default:
+		if s.debugMethods {
+			if response, ok := s.handleDebugMethod(ctx, id, method, message); ok {
+				return response
+			}
+		}
+		s.hooks.onError(ctx, id, method, message, fmt.Errorf("method %s %w", method, ErrUnsupported))
 		return createErrorResponse(
-			baseMessage.ID,
+			id,
 			mcp.METHOD_NOT_FOUND,
-			fmt.Sprintf("Method %s not found", baseMessage.Method),
+			fmt.Sprintf("Method %s not found", method),
 		)
 	}
 }
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"bufio"
+	"io"
+)
+
+// WithMaxRequestBytes limits the size of a single message a client may send
+// to n bytes. Transports enforce the limit while reading, before the message
+// is unmarshalled, and answer oversized messages with INVALID_REQUEST: the
+// SSE server limits the body of each message POST, and the stdio server the
+// length of each line. A limit of zero or less disables the check.
+func WithMaxRequestBytes(n int) ServerOption {
+	return func(s *MCPServer) {
+		s.maxRequestBytes = n
+	}
+}
+
+// readLimitedLine reads a line like reader.ReadString('\n'), but returns
+// ErrRequestTooLarge once the line exceeds limit bytes, not counting the
+// newline. The rest of an oversized line is discarded without being
+// buffered, so the next call starts at the following line. A limit of zero
+// or less reads lines of any length.
+func readLimitedLine(reader *bufio.Reader, limit int) (string, error) {
+	if limit <= 0 {
+		return reader.ReadString('\n')
+	}
+
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		size := len(line) + len(chunk)
+		if err == nil {
+			size-- // the newline
+		}
+		if size > limit {
+			for err == bufio.ErrBufferFull {
+				_, err = reader.ReadSlice('\n')
+			}
+			if err != nil && err != io.EOF {
+				return "", err
+			}
+			return "", ErrRequestTooLarge
+		}
+
+		line = append(line, chunk...)
+		if err != bufio.ErrBufferFull {
+			return string(line), err
+		}
+	}
+}
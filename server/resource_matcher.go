@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// ResourceMatcherFunc reports whether a resource matcher serves uri.
+type ResourceMatcherFunc func(uri string) bool
+
+// ResourceListerFunc lists the resources served by a resource matcher, to be
+// included in resources/list responses.
+type ResourceListerFunc func(ctx context.Context) ([]mcp.Resource, error)
+
+// resourceMatcherEntry is a resource matcher registered with
+// AddResourceMatcher or AddResourceMatcherWithLister.
+type resourceMatcherEntry struct {
+	matcher ResourceMatcherFunc
+	handler ResourceHandlerFunc
+	lister  ResourceListerFunc
+}
+
+// AddResourceMatcher registers a handler for every resource URI for which
+// matcher returns true, e.g. all URIs with a common prefix. Matchers are
+// consulted in the order they were added, after resources and resource
+// templates, so a single handler can serve URIs that cannot be expressed as
+// a template. The resources it serves are not listed by resources/list; use
+// AddResourceMatcherWithLister for that.
+func (s *MCPServer) AddResourceMatcher(matcher ResourceMatcherFunc, handler ResourceHandlerFunc) {
+	s.AddResourceMatcherWithLister(matcher, handler, nil)
+}
+
+// AddResourceMatcherWithLister registers a resource matcher like
+// AddResourceMatcher, along with a lister whose resources are included in
+// resources/list responses. Resources registered with AddResource take
+// precedence over listed resources with the same URI.
+func (s *MCPServer) AddResourceMatcherWithLister(
+	matcher ResourceMatcherFunc,
+	handler ResourceHandlerFunc,
+	lister ResourceListerFunc,
+) {
+	s.implicitlyRegisterResourceCapabilities()
+
+	s.resourcesMu.Lock()
+	s.resourceMatchers = append(s.resourceMatchers, resourceMatcherEntry{
+		matcher: matcher,
+		handler: handler,
+		lister:  lister,
+	})
+	s.resourcesMu.Unlock()
+
+	// When the list of available resources changes, servers that declared the listChanged capability SHOULD send a notification
+	if s.capabilities.resources.listChanged {
+		// Send notification to all initialized sessions
+		s.SendNotificationToAllClients(mcp.MethodNotificationResourcesListChanged, nil)
+	}
+}
+
+// listMatchedResources returns the resources of all resource matchers with a
+// lister, leaving out URIs already present in exclude.
+func (s *MCPServer) listMatchedResources(
+	ctx context.Context,
+	exclude map[string]struct{},
+) ([]mcp.Resource, error) {
+	s.resourcesMu.RLock()
+	var listers []ResourceListerFunc
+	for _, entry := range s.resourceMatchers {
+		if entry.lister != nil {
+			listers = append(listers, entry.lister)
+		}
+	}
+	s.resourcesMu.RUnlock()
+
+	var resources []mcp.Resource
+	for _, lister := range listers {
+		listed, err := lister(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range listed {
+			if _, ok := exclude[resource.URI]; ok {
+				continue
+			}
+			exclude[resource.URI] = struct{}{}
+			resources = append(resources, resource)
+		}
+	}
+	return resources, nil
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_ResourceMatcher(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+
+	readHandler := func(text string) ResourceHandlerFunc {
+		return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: request.Params.URI, Text: text}}, nil
+		}
+	}
+
+	server.AddResource(mcp.NewResource("docs://readme", "Readme"), readHandler("static"))
+	server.AddResourceTemplate(mcp.NewResourceTemplate("docs://pages/{id}", "Page"), ResourceTemplateHandlerFunc(readHandler("template")))
+	server.AddResourceMatcherWithLister(
+		func(uri string) bool { return strings.HasPrefix(uri, "docs://") },
+		readHandler("matcher"),
+		func(ctx context.Context) ([]mcp.Resource, error) {
+			return []mcp.Resource{
+				mcp.NewResource("docs://readme", "Shadowed"),
+				mcp.NewResource("docs://guide/1", "Guide 1"),
+				mcp.NewResource("docs://guide/2", "Guide 2"),
+			}, nil
+		},
+	)
+	server.AddResourceMatcher(
+		func(uri string) bool { return strings.HasPrefix(uri, "files://") },
+		readHandler("unlisted"),
+	)
+
+	read := func(uri string) mcp.JSONRPCMessage {
+		return server.HandleMessage(context.Background(), []byte(fmt.Sprintf(
+			`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":%q}}`, uri)))
+	}
+
+	t.Run("resources and templates take precedence", func(t *testing.T) {
+		for uri, expected := range map[string]string{
+			"docs://readme":     "static",
+			"docs://pages/1":    "template",
+			"docs://guide/1":    "matcher",
+			"docs://anything":   "matcher",
+			"files://notes.txt": "unlisted",
+		} {
+			response, ok := read(uri).(mcp.JSONRPCResponse)
+			require.True(t, ok, uri)
+			result, ok := response.Result.(mcp.ReadResourceResult)
+			require.True(t, ok, uri)
+			require.Len(t, result.Contents, 1, uri)
+			contents := result.Contents[0].(mcp.TextResourceContents)
+			assert.Equal(t, uri, contents.URI)
+			assert.Equal(t, expected, contents.Text, uri)
+		}
+	})
+
+	t.Run("unmatched URIs are not found", func(t *testing.T) {
+		errorResponse, ok := read("other://x").(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.RESOURCE_NOT_FOUND, errorResponse.Error.Code)
+	})
+
+	t.Run("listers contribute to resources/list", func(t *testing.T) {
+		response, ok := server.HandleMessage(context.Background(), []byte(
+			`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`)).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		var names []string
+		for _, resource := range response.Result.(mcp.ListResourcesResult).Resources {
+			names = append(names, resource.Name)
+		}
+		assert.Equal(t, []string{"Guide 1", "Guide 2", "Readme"}, names)
+	})
+
+	t.Run("lister errors fail resources/list", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0")
+		server.AddResourceMatcherWithLister(
+			func(uri string) bool { return true },
+			readHandler("matcher"),
+			func(ctx context.Context) ([]mcp.Resource, error) {
+				return nil, errors.New("listing failed")
+			},
+		)
+		errorResponse, ok := server.HandleMessage(context.Background(), []byte(
+			`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`)).(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INTERNAL_ERROR, errorResponse.Error.Code)
+		assert.Contains(t, errorResponse.Error.Message, "listing failed")
+	})
+}
@@ -0,0 +1,357 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// defaultResourceChunkSize is the size of the chunks reader resources are
+// split into unless WithResourceChunkSize is used.
+const defaultResourceChunkSize = 64 * 1024
+
+// ReaderResourceHandlerFunc returns the contents of a resource as a reader
+// together with its MIME type. If the reader implements io.Closer it is
+// closed once it has been consumed.
+type ReaderResourceHandlerFunc func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, string, error)
+
+// WithResourceChunkSize sets the size in bytes of the chunks reader resources
+// are split into. A size of zero or less uses the default of 64KiB.
+func WithResourceChunkSize(size int) ServerOption {
+	return func(s *MCPServer) {
+		s.resourceChunkSize = size
+	}
+}
+
+// resourceStreamingKey is the context key marking messages handled by a
+// transport that writes reader resources as they are read.
+type resourceStreamingKey struct{}
+
+// withResourceStreaming marks ctx as handled by a transport writing the
+// response with writeStreamedResponse.
+func withResourceStreaming(ctx context.Context) context.Context {
+	return context.WithValue(ctx, resourceStreamingKey{}, true)
+}
+
+// AddReaderResource registers a resource whose handler returns a reader.
+// The contents are split into entries of the size set by
+// WithResourceChunkSize. Text MIME types produce text contents split on
+// character boundaries; anything else produces base64 blob contents.
+//
+// The SSE transport streams the entries: each chunk is read from the reader,
+// written to the client and flushed before the next one is read, so the
+// whole contents are never held in memory. Hooks seeing the result of such a
+// read get a single contents entry of an unexported type standing for the
+// reader. Other transports, and HandleMessage, read the reader to the end
+// and return all entries in a single response. If the reader fails once the
+// response has started, the response is cut short and the client fails to
+// parse it.
+//
+// As with mcp.ApplyResourceRange, a range in the request applies to binary
+// contents only: the bytes before the offset are skipped, without reading
+// them when the reader is an io.Seeker. Text contents are returned whole.
+func (s *MCPServer) AddReaderResource(
+	resource mcp.Resource,
+	handler ReaderResourceHandlerFunc,
+) {
+	s.AddResource(resource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		reader, mimeType, err := handler(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		if mimeType == "" {
+			mimeType = resource.MIMEType
+		}
+		chunkSize := s.resourceChunkSize
+		if chunkSize <= 0 {
+			chunkSize = defaultResourceChunkSize
+		}
+		contents := &readerResourceContents{
+			ctx:       ctx,
+			uri:       request.Params.URI,
+			mimeType:  mimeType,
+			reader:    reader,
+			chunkSize: chunkSize,
+		}
+
+		if !contents.text() {
+			ranged, err := rangeReader(reader, request.Params.Range)
+			if err != nil {
+				contents.close()
+				return nil, fmt.Errorf("failed to read resource %s: %w", request.Params.URI, err)
+			}
+			contents.reader = ranged
+		}
+
+		if streaming, _ := ctx.Value(resourceStreamingKey{}).(bool); streaming {
+			return []mcp.ResourceContents{contents}, nil
+		}
+		var entries []mcp.ResourceContents
+		err = contents.each(func(entry mcp.ResourceContents) error {
+			entries = append(entries, entry)
+			return nil
+		})
+		return entries, err
+	})
+}
+
+// rangeReader restricts reader to the byte range r, if any.
+func rangeReader(reader io.Reader, r *mcp.ResourceRange) (io.Reader, error) {
+	if r == nil {
+		return reader, nil
+	}
+	if r.Offset < 0 || r.Length < 0 {
+		return nil, fmt.Errorf("invalid resource range: offset %d, length %d", r.Offset, r.Length)
+	}
+
+	if seeker, ok := reader.(io.Seeker); ok {
+		if _, err := seeker.Seek(r.Offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	} else if _, err := io.CopyN(io.Discard, reader, r.Offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if r.Length > 0 {
+		// Keep the original reader to close it
+		reader = struct {
+			io.Reader
+			io.Closer
+		}{io.LimitReader(reader, r.Length), closerOf(reader)}
+	}
+	return reader, nil
+}
+
+// closerOf returns reader as an io.Closer, or one doing nothing if it is not.
+func closerOf(reader io.Reader) io.Closer {
+	if closer, ok := reader.(io.Closer); ok {
+		return closer
+	}
+	return io.NopCloser(nil)
+}
+
+// readerResourceContents is the contents of a reader resource read by a
+// transport streaming it, read only as the response is written. It stands
+// for any number of contents entries, and is consumed by the first call to
+// each or MarshalJSON.
+type readerResourceContents struct {
+	// Embedded, and left nil, to implement the sealed interface
+	mcp.ResourceContents
+
+	ctx       context.Context
+	uri       string
+	mimeType  string
+	reader    io.Reader
+	chunkSize int
+	consumed  bool
+}
+
+// text reports whether the contents are read as text.
+func (c *readerResourceContents) text() bool {
+	return strings.HasPrefix(c.mimeType, "text/")
+}
+
+// close closes the reader if it implements io.Closer.
+func (c *readerResourceContents) close() {
+	_ = closerOf(c.reader).Close()
+}
+
+// each reads the reader to the end, calling fn with one contents entry per
+// chunk of at most chunkSize bytes, and closes it.
+func (c *readerResourceContents) each(fn func(mcp.ResourceContents) error) error {
+	if c.consumed {
+		return fmt.Errorf("resource %s has already been read", c.uri)
+	}
+	c.consumed = true
+	defer c.close()
+
+	chunkSize := c.chunkSize
+	text := c.text()
+	if text {
+		// Leave room for at least one whole character per chunk
+		chunkSize = max(chunkSize, utf8.UTFMax)
+	}
+	buf := make([]byte, chunkSize)
+	var pending int
+
+	for {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := io.ReadFull(c.reader, buf[pending:])
+		n += pending
+		eof := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !eof {
+			return fmt.Errorf("failed to read resource %s: %w", c.uri, err)
+		}
+
+		chunk := buf[:n]
+		pending = 0
+		if text && !eof {
+			// Carry an incomplete trailing character over to the next chunk
+			end := len(chunk)
+			for end > 0 && end > len(chunk)-utf8.UTFMax && !utf8.RuneStart(chunk[end-1]) {
+				end--
+			}
+			if end > 0 && !utf8.FullRune(chunk[end-1:]) {
+				chunk = chunk[:end-1]
+			}
+		}
+
+		if len(chunk) > 0 {
+			var entry mcp.ResourceContents
+			if text {
+				entry = mcp.TextResourceContents{
+					URI:      c.uri,
+					MIMEType: c.mimeType,
+					Text:     string(chunk),
+				}
+			} else {
+				entry = mcp.BlobResourceContents{
+					URI:      c.uri,
+					MIMEType: c.mimeType,
+					Blob:     base64.StdEncoding.EncodeToString(chunk),
+				}
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+		if eof {
+			return nil
+		}
+		pending = copy(buf, buf[len(chunk):n])
+	}
+}
+
+// MarshalJSON encodes the contents as a single entry, for responses encoded
+// by other means than writeStreamedResponse.
+func (c *readerResourceContents) MarshalJSON() ([]byte, error) {
+	var text strings.Builder
+	var blob []byte
+	err := c.each(func(entry mcp.ResourceContents) error {
+		switch entry := entry.(type) {
+		case mcp.TextResourceContents:
+			text.WriteString(entry.Text)
+		case mcp.BlobResourceContents:
+			data, err := base64.StdEncoding.DecodeString(entry.Blob)
+			if err != nil {
+				return err
+			}
+			blob = append(blob, data...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c.text() {
+		return json.Marshal(mcp.TextResourceContents{URI: c.uri, MIMEType: c.mimeType, Text: text.String()})
+	}
+	return json.Marshal(mcp.BlobResourceContents{
+		URI:      c.uri,
+		MIMEType: c.mimeType,
+		Blob:     base64.StdEncoding.EncodeToString(blob),
+	})
+}
+
+// streamedResponse returns the result of response if it has reader contents
+// to stream.
+func streamedResponse(response mcp.JSONRPCMessage) (mcp.JSONRPCResponse, mcp.ReadResourceResult, bool) {
+	resp, ok := response.(mcp.JSONRPCResponse)
+	if !ok {
+		return resp, mcp.ReadResourceResult{}, false
+	}
+	result, ok := resp.Result.(mcp.ReadResourceResult)
+	if !ok {
+		return resp, result, false
+	}
+	for _, contents := range result.Contents {
+		if _, ok := contents.(*readerResourceContents); ok {
+			return resp, result, true
+		}
+	}
+	return resp, result, false
+}
+
+// writeStreamedResponse writes response to w as JSON, calling flush after
+// each entry of its reader contents. It reports false, writing nothing, if
+// response has no reader contents.
+func writeStreamedResponse(w io.Writer, flush func(), response mcp.JSONRPCMessage) (bool, error) {
+	resp, result, ok := streamedResponse(response)
+	if !ok {
+		return false, nil
+	}
+	// Close the readers left unread if writing fails
+	defer discardStreamedResponse(response)
+
+	id, err := json.Marshal(resp.ID)
+	if err != nil {
+		return true, err
+	}
+	if _, err := fmt.Fprintf(w, `{"jsonrpc":%q,"id":%s,"result":{`, resp.JSONRPC, id); err != nil {
+		return true, err
+	}
+	if len(result.Meta) > 0 {
+		meta, err := json.Marshal(result.Meta)
+		if err != nil {
+			return true, err
+		}
+		if _, err := fmt.Fprintf(w, `"_meta":%s,`, meta); err != nil {
+			return true, err
+		}
+	}
+	if _, err := io.WriteString(w, `"contents":[`); err != nil {
+		return true, err
+	}
+
+	first := true
+	writeEntry := func(entry any) error {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if !first {
+			data = append([]byte{','}, data...)
+		}
+		first = false
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		flush()
+		return nil
+	}
+	for _, contents := range result.Contents {
+		if reader, ok := contents.(*readerResourceContents); ok {
+			err = reader.each(func(entry mcp.ResourceContents) error { return writeEntry(entry) })
+		} else {
+			err = writeEntry(contents)
+		}
+		if err != nil {
+			return true, err
+		}
+	}
+
+	_, err = io.WriteString(w, `]}}`)
+	return true, err
+}
+
+// discardStreamedResponse closes the readers of response left unread.
+func discardStreamedResponse(response mcp.JSONRPCMessage) {
+	_, result, _ := streamedResponse(response)
+	for _, contents := range result.Contents {
+		if reader, ok := contents.(*readerResourceContents); ok && !reader.consumed {
+			reader.consumed = true
+			reader.close()
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_AddReaderResource(t *testing.T) {
+	data := make([]byte, 1<<20)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	text := strings.Repeat("héllo wörld ✓ ", 1000)
+
+	server := NewMCPServer("test-server", "1.0.0", WithResourceChunkSize(64*1024))
+	server.AddReaderResource(
+		mcp.NewResource("file:///large.bin", "large"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, string, error) {
+			// io.MultiReader hides the Seeker, exercising the discarding path
+			return io.MultiReader(bytes.NewReader(data)), "application/octet-stream", nil
+		},
+	)
+
+	read := func(t *testing.T, server *MCPServer, params string) []mcp.ResourceContents {
+		response := server.HandleMessage(context.Background(), []byte(
+			`{"jsonrpc": "2.0", "id": 1, "method": "resources/read", "params": `+params+`}`,
+		))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "unexpected response %#v", response)
+		result, ok := resp.Result.(mcp.ReadResourceResult)
+		require.True(t, ok)
+		return result.Contents
+	}
+
+	joinBlobs := func(t *testing.T, contents []mcp.ResourceContents) []byte {
+		var out []byte
+		for _, content := range contents {
+			blob, ok := content.(mcp.BlobResourceContents)
+			require.True(t, ok)
+			assert.Equal(t, "application/octet-stream", blob.MIMEType)
+			decoded, err := base64.StdEncoding.DecodeString(blob.Blob)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(decoded), 64*1024)
+			out = append(out, decoded...)
+		}
+		return out
+	}
+
+	t.Run("Binary contents are delivered in chunks", func(t *testing.T) {
+		contents := read(t, server, `{"uri": "file:///large.bin"}`)
+		assert.Len(t, contents, 16)
+		assert.Equal(t, data, joinBlobs(t, contents))
+	})
+
+	t.Run("Range is applied while reading", func(t *testing.T) {
+		contents := read(t, server, `{"uri": "file:///large.bin", "range": {"offset": 100000, "length": 70000}}`)
+		assert.Len(t, contents, 2)
+		assert.Equal(t, data[100000:170000], joinBlobs(t, contents))
+	})
+
+	t.Run("Range leaves text whole", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0")
+		server.AddReaderResource(
+			mcp.NewResource("file:///small.txt", "text", mcp.WithMIMEType("text/plain")),
+			func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, string, error) {
+				return strings.NewReader("héllo"), "", nil
+			},
+		)
+
+		contents := read(t, server, `{"uri": "file:///small.txt", "range": {"offset": 2, "length": 1}}`)
+		require.Len(t, contents, 1)
+		assert.Equal(t, "héllo", contents[0].(mcp.TextResourceContents).Text)
+	})
+
+	t.Run("Text chunks are split on character boundaries", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0", WithResourceChunkSize(1001))
+		server.AddReaderResource(
+			mcp.NewResource("file:///large.txt", "text", mcp.WithMIMEType("text/plain")),
+			func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, string, error) {
+				return strings.NewReader(text), "", nil
+			},
+		)
+
+		contents := read(t, server, `{"uri": "file:///large.txt"}`)
+		require.Greater(t, len(contents), 1)
+
+		var sb strings.Builder
+		for _, content := range contents {
+			chunk, ok := content.(mcp.TextResourceContents)
+			require.True(t, ok)
+			assert.Equal(t, "text/plain", chunk.MIMEType)
+			assert.True(t, utf8.ValidString(chunk.Text), "chunk splits a character")
+			sb.WriteString(chunk.Text)
+		}
+		assert.Equal(t, text, sb.String())
+	})
+}
+
+// gatedReader reads data, blocking once gateAt bytes have been read until
+// gate is closed.
+type gatedReader struct {
+	data   []byte
+	gateAt int
+	gate   chan struct{}
+	pos    int
+	closed bool
+}
+
+func (r *gatedReader) Read(p []byte) (int, error) {
+	if r.pos >= len(r.data) {
+		return 0, io.EOF
+	}
+	if r.pos >= r.gateAt {
+		<-r.gate
+	} else {
+		p = p[:min(len(p), r.gateAt-r.pos)]
+	}
+	n := copy(p, r.data[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+func (r *gatedReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestSSEServer_StreamsReaderResources(t *testing.T) {
+	data := make([]byte, 64*1024)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	// readUntil reads body until done reports true for what was received
+	readUntil := func(t *testing.T, body io.Reader, received []byte, done func([]byte) bool) []byte {
+		buf := make([]byte, 4096)
+		deadline := time.After(5 * time.Second)
+		for !done(received) {
+			type readResult struct {
+				n   int
+				err error
+			}
+			result := make(chan readResult, 1)
+			go func() {
+				n, err := body.Read(buf)
+				result <- readResult{n, err}
+			}()
+			select {
+			case r := <-result:
+				received = append(received, buf[:r.n]...)
+				if r.err == io.EOF {
+					return received
+				}
+				require.NoError(t, r.err)
+			case <-deadline:
+				t.Fatalf("Timed out reading the response, got %q", received)
+			}
+		}
+		return received
+	}
+
+	// read serves a resource whose reader blocks after 4 chunks until the
+	// client has received them, and returns the response
+	read := func(t *testing.T, opts ...SSEOption) (string, *gatedReader) {
+		reader := &gatedReader{data: data, gateAt: 4 * 1024, gate: make(chan struct{})}
+		mcpServer := NewMCPServer("test", "1.0.0", WithResourceChunkSize(1024))
+		mcpServer.AddReaderResource(
+			mcp.NewResource("file:///large.bin", "large"),
+			func(ctx context.Context, request mcp.ReadResourceRequest) (io.Reader, string, error) {
+				return reader, "application/octet-stream", nil
+			},
+		)
+		testServer := NewTestServer(mcpServer, opts...)
+		defer testServer.Close()
+		// Let the handler finish before closing the server
+		defer func() {
+			select {
+			case <-reader.gate:
+			default:
+				close(reader.gate)
+			}
+		}()
+
+		sseResp, err := http.Get(fmt.Sprintf("%s/sse", testServer.URL))
+		require.NoError(t, err, "Failed to connect to SSE endpoint")
+		defer sseResp.Body.Close()
+		endpointEvent, err := readSSEEvent(sseResp)
+		require.NoError(t, err, "Failed to read SSE response")
+		messageURL := testServer.URL + strings.TrimSpace(
+			strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
+		)
+
+		// Buffering the response would hold it back until the gate opens
+		client := &http.Client{Timeout: 5 * time.Second}
+		resp, err := client.Post(messageURL, "application/json", strings.NewReader(
+			`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"file:///large.bin"}}`,
+		))
+		require.NoError(t, err, "Failed to send message")
+		defer resp.Body.Close()
+
+		var body io.Reader = sseResp.Body
+		if resp.StatusCode == http.StatusOK {
+			body = resp.Body
+		}
+		received := readUntil(t, body, nil, func(received []byte) bool {
+			return bytes.Count(received, []byte(`"uri"`)) >= 4
+		})
+		close(reader.gate)
+		received = readUntil(t, body, received, func(received []byte) bool {
+			return bytes.HasSuffix(received, []byte("\n\n"))
+		})
+		return string(received), reader
+	}
+
+	decode := func(t *testing.T, message string) {
+		var response struct {
+			ID     int `json:"id"`
+			Result struct {
+				Contents []mcp.BlobResourceContents `json:"contents"`
+			} `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(message), &response))
+		assert.Equal(t, 1, response.ID)
+		require.Len(t, response.Result.Contents, 64)
+
+		var out []byte
+		for _, blob := range response.Result.Contents {
+			assert.Equal(t, "file:///large.bin", blob.URI)
+			decoded, err := base64.StdEncoding.DecodeString(blob.Blob)
+			require.NoError(t, err)
+			out = append(out, decoded...)
+		}
+		assert.Equal(t, data, out)
+	}
+
+	t.Run("Message events", func(t *testing.T) {
+		received, reader := read(t)
+		message, found := strings.CutPrefix(received, "event: message\ndata: ")
+		require.True(t, found, "unexpected event %q", received)
+		decode(t, message)
+		assert.True(t, reader.closed, "reader is closed once read")
+	})
+
+	t.Run("Synchronous HTTP responses", func(t *testing.T) {
+		received, reader := read(t, WithSynchronousHTTPResponse())
+		decode(t, received)
+		assert.True(t, reader.closed, "reader is closed once read")
+	})
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// resourceSubscriptionsKey is the session state key for the set of resource
+// URIs the session subscribed to, a map[string]struct{} guarded by
+// MCPServer.subscriptionsMu.
+type resourceSubscriptionsKey struct{}
+
+// handleSubscribe records that the current session wants
+// notifications/resources/updated for the requested URI.
+func (s *MCPServer) handleSubscribe(
+	ctx context.Context,
+	id any,
+	request mcp.SubscribeRequest,
+) (*mcp.EmptyResult, *requestError) {
+	if !s.capabilities.resources.subscribe {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.METHOD_NOT_FOUND,
+			err:  fmt.Errorf("resource subscriptions %w", ErrUnsupported),
+		}
+	}
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_REQUEST,
+			err:  ErrSessionNotFound,
+		}
+	}
+
+	state := s.SessionState(session.SessionID())
+	if state == nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_REQUEST,
+			err:  ErrSessionNotFound,
+		}
+	}
+
+	s.subscriptionsMu.Lock()
+	defer s.subscriptionsMu.Unlock()
+	value, _ := state.Get(resourceSubscriptionsKey{})
+	uris, ok := value.(map[string]struct{})
+	if !ok {
+		uris = make(map[string]struct{})
+		state.Set(resourceSubscriptionsKey{}, uris)
+	}
+	uris[request.Params.URI] = struct{}{}
+	return &mcp.EmptyResult{}, nil
+}
+
+// handleUnsubscribe removes a subscription made with resources/subscribe.
+// Unsubscribing from a URI that was never subscribed to is not an error.
+func (s *MCPServer) handleUnsubscribe(
+	ctx context.Context,
+	id any,
+	request mcp.UnsubscribeRequest,
+) (*mcp.EmptyResult, *requestError) {
+	if !s.capabilities.resources.subscribe {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.METHOD_NOT_FOUND,
+			err:  fmt.Errorf("resource subscriptions %w", ErrUnsupported),
+		}
+	}
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_REQUEST,
+			err:  ErrSessionNotFound,
+		}
+	}
+
+	state := s.SessionState(session.SessionID())
+	if state == nil {
+		return &mcp.EmptyResult{}, nil
+	}
+
+	s.subscriptionsMu.Lock()
+	defer s.subscriptionsMu.Unlock()
+	value, _ := state.Get(resourceSubscriptionsKey{})
+	if uris, ok := value.(map[string]struct{}); ok {
+		delete(uris, request.Params.URI)
+		if len(uris) == 0 {
+			state.Delete(resourceSubscriptionsKey{})
+		}
+	}
+	return &mcp.EmptyResult{}, nil
+}
+
+// notifyResourceUpdated sends notifications/resources/updated for uri to
+// every initialized session subscribed to it. Sessions whose notification
+// channel is full are skipped; unregistered sessions have lost their state,
+// and with it their subscriptions.
+func (s *MCPServer) notifyResourceUpdated(uri string) {
+	s.subscriptionsMu.RLock()
+	var sessionIDs []string
+	s.sessionStates.Range(func(key, value any) bool {
+		subscriptions, _ := value.(*SessionState).Get(resourceSubscriptionsKey{})
+		uris, _ := subscriptions.(map[string]struct{})
+		if _, ok := uris[uri]; ok {
+			sessionIDs = append(sessionIDs, key.(string))
+		}
+		return true
+	})
+	s.subscriptionsMu.RUnlock()
+
+	for _, sessionID := range sessionIDs {
+		value, ok := s.sessions.Load(sessionID)
+		if !ok {
+			continue
+		}
+		session, ok := value.(ClientSession)
+		if !ok || !session.Initialized() {
+			continue
+		}
+		notification := mcp.JSONRPCNotification{
+			JSONRPC: mcp.JSONRPC_VERSION,
+			Notification: mcp.Notification{
+				Method: mcp.MethodNotificationResourceUpdated,
+				Params: mcp.NotificationParams{
+					AdditionalFields: map[string]any{"uri": uri},
+				},
+			},
+		}
+		select {
+		case session.NotificationChannel() <- notification:
+		default:
+			s.hooks.onError(context.Background(), nil, "notification", map[string]any{
+				"method":    mcp.MethodNotificationResourceUpdated,
+				"sessionID": sessionID,
+			}, fmt.Errorf("notification channel blocked for session %s: %w", sessionID, ErrNotificationChannelBlocked))
+		}
+	}
+}
+
+// StartResourceUpdateTicker calls changed every interval and sends
+// notifications/resources/updated for each returned URI to the sessions
+// subscribed to it via resources/subscribe. It returns immediately; the
+// ticker runs until ctx is cancelled.
+//
+//	s.StartResourceUpdateTicker(ctx, 5*time.Second, func() []string {
+//		return store.ChangedSince(lastPoll)
+//	})
+func (s *MCPServer) StartResourceUpdateTicker(
+	ctx context.Context,
+	interval time.Duration,
+	changed func() []string,
+) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, uri := range changed() {
+					s.notifyResourceUpdated(uri)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_ResourceSubscriptions(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(true, false))
+
+	subscribed := fakeSession{
+		sessionID:           "subscribed",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		initialized:         true,
+	}
+	other := fakeSession{
+		sessionID:           "other",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		initialized:         true,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), subscribed))
+	require.NoError(t, server.RegisterSession(context.Background(), other))
+
+	response := server.HandleMessage(server.WithContext(context.Background(), subscribed), []byte(`{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "resources/subscribe",
+		"params": {"uri": "test://watched"}
+	}`))
+	require.IsType(t, mcp.JSONRPCResponse{}, response)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server.StartResourceUpdateTicker(ctx, 10*time.Millisecond, func() []string {
+		return []string{"test://watched", "test://unwatched"}
+	})
+
+	select {
+	case notification := <-subscribed.notificationChannel:
+		assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+		assert.Equal(t, "test://watched", notification.Params.AdditionalFields["uri"])
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for resources/updated notification")
+	}
+	assert.Empty(t, other.notificationChannel, "unsubscribed session must not be notified")
+
+	t.Run("unsubscribe stops notifications", func(t *testing.T) {
+		response := server.HandleMessage(server.WithContext(context.Background(), subscribed), []byte(`{
+			"jsonrpc": "2.0",
+			"id": 2,
+			"method": "resources/unsubscribe",
+			"params": {"uri": "test://watched"}
+		}`))
+		require.IsType(t, mcp.JSONRPCResponse{}, response)
+
+		// Drain anything sent before the unsubscribe was processed.
+		time.Sleep(20 * time.Millisecond)
+		for len(subscribed.notificationChannel) > 0 {
+			<-subscribed.notificationChannel
+		}
+		time.Sleep(50 * time.Millisecond)
+		assert.Empty(t, subscribed.notificationChannel)
+	})
+
+	t.Run("disconnect drops subscriptions", func(t *testing.T) {
+		response := server.HandleMessage(server.WithContext(context.Background(), other), []byte(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"method": "resources/subscribe",
+			"params": {"uri": "test://watched"}
+		}`))
+		require.IsType(t, mcp.JSONRPCResponse{}, response)
+		subscribed := func() bool {
+			state := server.SessionState(other.SessionID())
+			if state == nil {
+				return false
+			}
+			_, ok := state.Get(resourceSubscriptionsKey{})
+			return ok
+		}
+		require.True(t, subscribed())
+		server.notifyResourceUpdated("test://watched")
+		select {
+		case notification := <-other.notificationChannel:
+			assert.Equal(t, mcp.MethodNotificationResourceUpdated, notification.Method)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for resources/updated notification")
+		}
+
+		server.UnregisterSession(context.Background(), other.SessionID())
+		assert.False(t, subscribed())
+
+		// Re-registering with the same ID starts without subscriptions
+		require.NoError(t, server.RegisterSession(context.Background(), other))
+		assert.False(t, subscribed())
+		// Drain anything sent before the session was unregistered.
+		for len(other.notificationChannel) > 0 {
+			<-other.notificationChannel
+		}
+		server.notifyResourceUpdated("test://watched")
+		select {
+		case notification := <-other.notificationChannel:
+			t.Errorf("re-registered session must not be notified, got %v", notification)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("ticker stops on cancel", func(t *testing.T) {
+		calls := make(chan struct{}, 100)
+		tickerCtx, tickerCancel := context.WithCancel(context.Background())
+		server.StartResourceUpdateTicker(tickerCtx, 5*time.Millisecond, func() []string {
+			calls <- struct{}{}
+			return nil
+		})
+		<-calls
+		tickerCancel()
+		time.Sleep(20 * time.Millisecond)
+		for len(calls) > 0 {
+			<-calls
+		}
+		time.Sleep(30 * time.Millisecond)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("subscribe requires the capability", func(t *testing.T) {
+		noSubscribe := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(false, false))
+		require.NoError(t, noSubscribe.RegisterSession(context.Background(), other))
+		response := noSubscribe.HandleMessage(noSubscribe.WithContext(context.Background(), other), []byte(`{
+			"jsonrpc": "2.0",
+			"id": 3,
+			"method": "resources/subscribe",
+			"params": {"uri": "test://watched"}
+		}`))
+		errorResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.METHOD_NOT_FOUND, errorResponse.Error.Code)
+	})
+}
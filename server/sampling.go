@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// SessionWithSampling is an extension of ClientSession that can send
+// sampling/createMessage requests to the client. Sessions implementing
+// SessionWithRequests support sampling without it.
+type SessionWithSampling interface {
+	ClientSession
+	// RequestSampling sends a sampling request to the client and waits for
+	// its result
+	RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
+}
+
+// RequestSampling asks the client of the current session to sample an LLM,
+// e.g. from within a tool handler:
+//
+//	result, err := s.RequestSampling(ctx, mcp.CreateMessageRequest{...})
+//
+// The session is taken from ctx and must implement SessionWithSampling or
+// SessionWithRequests, otherwise ErrSessionDoesNotSupportSampling is
+// returned. The same error is returned without a round-trip if the client
+// did not declare the sampling capability when initializing.
+func (s *MCPServer) RequestSampling(
+	ctx context.Context,
+	request mcp.CreateMessageRequest,
+) (*mcp.CreateMessageResult, error) {
+	session := ClientSessionFromContext(ctx)
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	capabilities, initialized := clientCapabilities(s.SessionState(session.SessionID()))
+	if initialized && capabilities.Sampling == nil {
+		return nil, ErrSessionDoesNotSupportSampling
+	}
+
+	request.Method = string(mcp.MethodSamplingCreateMessage)
+	if samplingSession, ok := session.(SessionWithSampling); ok {
+		if s.clientRequestTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.clientRequestTimeout)
+			defer cancel()
+		}
+		return samplingSession.RequestSampling(ctx, request)
+	}
+
+	response, err := s.SendRequestToClient(ctx, request.Method, request.Params)
+	if errors.Is(err, ErrSessionDoesNotSupportRequests) {
+		return nil, ErrSessionDoesNotSupportSampling
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result mcp.CreateMessageResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sampling result: %w", err)
+	}
+	if contentMap, ok := result.Content.(map[string]any); ok {
+		content, err := mcp.ParseContent(contentMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sampling result content: %w", err)
+		}
+		result.Content = content
+	}
+	return &result, nil
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// sessionTestClientWithSampling implements the SessionWithSampling interface for testing
+type sessionTestClientWithSampling struct {
+	sessionTestClient
+	requests  []mcp.CreateMessageRequest
+	deadlines []bool
+}
+
+func (f *sessionTestClientWithSampling) RequestSampling(ctx context.Context, request mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	f.requests = append(f.requests, request)
+	_, hasDeadline := ctx.Deadline()
+	f.deadlines = append(f.deadlines, hasDeadline)
+	return &mcp.CreateMessageResult{
+		SamplingMessage: mcp.SamplingMessage{Role: mcp.RoleAssistant, Content: mcp.NewTextContent("Paris")},
+		Model:           "test-model",
+	}, nil
+}
+
+func TestMCPServer_RequestSampling(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	request := mcp.CreateMessageRequest{}
+	request.Params.MaxTokens = 10
+
+	_, err := server.RequestSampling(context.Background(), request)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+
+	plainSession := &sessionTestClient{sessionID: "plain"}
+	_, err = server.RequestSampling(server.WithContext(context.Background(), plainSession), request)
+	assert.ErrorIs(t, err, ErrSessionDoesNotSupportSampling)
+
+	session := &sessionTestClientWithSampling{sessionTestClient: sessionTestClient{sessionID: "sampling"}}
+	result, err := server.RequestSampling(server.WithContext(context.Background(), session), request)
+	require.NoError(t, err)
+	assert.Equal(t, "test-model", result.Model)
+	require.Len(t, session.requests, 1)
+	assert.Equal(t, string(mcp.MethodSamplingCreateMessage), session.requests[0].Method)
+	assert.Equal(t, 10, session.requests[0].Params.MaxTokens)
+	assert.False(t, session.deadlines[0], "no deadline without WithClientRequestTimeout")
+
+	timeoutServer := NewMCPServer("test-server", "1.0.0", WithClientRequestTimeout(time.Minute))
+	_, err = timeoutServer.RequestSampling(timeoutServer.WithContext(context.Background(), session), request)
+	require.NoError(t, err)
+	require.Len(t, session.deadlines, 2)
+	assert.True(t, session.deadlines[1], "WithClientRequestTimeout applies to SessionWithSampling")
+}
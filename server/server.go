@@ -2,12 +2,17 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/zillow/mcp-go/mcp"
 )
@@ -20,8 +25,9 @@ type resourceEntry struct {
 
 // resourceTemplateEntry holds both a template and its handler
 type resourceTemplateEntry struct {
-	template mcp.ResourceTemplate
-	handler  ResourceTemplateHandlerFunc
+	template   mcp.ResourceTemplate
+	handler    ResourceTemplateHandlerFunc
+	completion ResourceTemplateCompletionResultFunc
 }
 
 // ServerOption is a function that configures an MCPServer.
@@ -33,10 +39,27 @@ type ResourceHandlerFunc func(ctx context.Context, request mcp.ReadResourceReque
 // ResourceTemplateHandlerFunc is a function that returns a resource template.
 type ResourceTemplateHandlerFunc func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)
 
+// ResourceTemplateCompletionFunc suggests values for the template variable
+// named argument, given the partial value the user typed so far.
+type ResourceTemplateCompletionFunc func(ctx context.Context, argument, value string) ([]string, error)
+
+// ResourceTemplateCompletionResultFunc suggests values for the template
+// variable named argument like ResourceTemplateCompletionFunc, and can also
+// report the total number of values and whether there are more than the ones
+// returned, e.g. when it only fetches the first page of a large set.
+type ResourceTemplateCompletionResultFunc func(ctx context.Context, argument, value string) (*mcp.Completion, error)
+
 // PromptHandlerFunc handles prompt requests with given arguments.
 type PromptHandlerFunc func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
 
 // ToolHandlerFunc handles tool calls with given arguments.
+//
+// Errors that originate from the tool itself should be reported in the result
+// with IsError set, so the LLM can see them. A handler that partially succeeds
+// may return both a non-nil result describing what succeeded and an error; the
+// result is then preferred and sent to the client with IsError set and the
+// error's message appended as text content, instead of a JSON-RPC error
+// response. The error is still reported to the OnError hooks.
 type ToolHandlerFunc func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
 
 // ToolHandlerMiddleware is a middleware function that wraps a ToolHandlerFunc.
@@ -45,10 +68,33 @@ type ToolHandlerMiddleware func(ToolHandlerFunc) ToolHandlerFunc
 // ToolFilterFunc is a function that filters tools based on context, typically using session information.
 type ToolFilterFunc func(ctx context.Context, tools []mcp.Tool) []mcp.Tool
 
+// ScopeCheckerFunc reports whether the caller identified by ctx holds all of
+// the given scopes, returning a non-nil error if it does not.
+type ScopeCheckerFunc func(ctx context.Context, scopes []string) error
+
+// ToolAuthorizerFunc decides whether the caller identified by ctx may call
+// tool with the given request, returning a non-nil error to deny the call.
+type ToolAuthorizerFunc func(ctx context.Context, tool mcp.Tool, request mcp.CallToolRequest) error
+
 // ServerTool combines a Tool with its ToolHandlerFunc.
 type ServerTool struct {
 	Tool    mcp.Tool
 	Handler ToolHandlerFunc
+	// RequiredScopes lists the scopes a caller must hold to call the tool.
+	// They are verified by the ScopeCheckerFunc set with WithScopeChecker.
+	RequiredScopes []string
+}
+
+// ServerResource combines a Resource with its ResourceHandlerFunc.
+type ServerResource struct {
+	Resource mcp.Resource
+	Handler  ResourceHandlerFunc
+}
+
+// ServerPrompt combines a Prompt with its PromptHandlerFunc.
+type ServerPrompt struct {
+	Prompt  mcp.Prompt
+	Handler PromptHandlerFunc
 }
 
 // serverKey is the context key for storing the server instance
@@ -62,6 +108,77 @@ func ServerFromContext(ctx context.Context) *MCPServer {
 	return nil
 }
 
+// requestStartKey is the context key for storing the time a message was received
+type requestStartKey struct{}
+
+// RequestStartFromContext returns the time HandleMessage started processing
+// the current message. Hooks can use it to measure request latency, e.g. by
+// reporting time.Since(start) from OnSuccess or OnError.
+func RequestStartFromContext(ctx context.Context) (time.Time, bool) {
+	start, ok := ctx.Value(requestStartKey{}).(time.Time)
+	return start, ok
+}
+
+// requestMethodKey and requestIDKey are the context keys for storing the
+// method and id of the message being handled
+type (
+	requestMethodKey struct{}
+	requestIDKey     struct{}
+)
+
+// MethodFromContext returns the JSON-RPC method of the request or
+// notification being handled, e.g. "tools/call", for use in handlers and
+// hooks.
+func MethodFromContext(ctx context.Context) (mcp.MCPMethod, bool) {
+	method, ok := ctx.Value(requestMethodKey{}).(mcp.MCPMethod)
+	return method, ok
+}
+
+// RequestIDFromContext returns the JSON-RPC id of the request being handled,
+// as decoded by parseRequestID. It returns false while handling a
+// notification, which has no id.
+func RequestIDFromContext(ctx context.Context) (mcp.RequestId, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(mcp.RequestId)
+	return id, ok
+}
+
+// parseRequestID decodes the id of an incoming message so that it is echoed
+// back unchanged in the response. String ids stay strings and numbers become
+// float64, except integers a float64 cannot represent exactly, which are kept
+// as json.Number. A missing or null id yields nil, marking a notification.
+// Ids that are neither strings nor numbers are rejected, as JSON-RPC
+// requires.
+func parseRequestID(raw json.RawMessage) (any, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var id any
+	if err := decoder.Decode(&id); err != nil {
+		return nil, err
+	}
+	switch id := id.(type) {
+	case string:
+		return id, nil
+	case json.Number:
+		f, err := id.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid request id %s: %w", raw, err)
+		}
+		if strings.ContainsAny(string(id), ".eE") {
+			return f, nil
+		}
+		if n, err := id.Int64(); err != nil || int64(f) != n {
+			return id, nil
+		}
+		return f, nil
+	default:
+		return nil, fmt.Errorf("request id must be a string or number, got %s", raw)
+	}
+}
+
 // UnparsableMessageError is attached to the RequestError when json.Unmarshal
 // fails on the request.
 type UnparsableMessageError struct {
@@ -71,6 +188,9 @@ type UnparsableMessageError struct {
 }
 
 func (e *UnparsableMessageError) Error() string {
+	if e.method == "" {
+		return fmt.Sprintf("unparsable message: %s", e.err)
+	}
 	return fmt.Sprintf("unparsable %s request: %s", e.method, e.err)
 }
 
@@ -117,6 +237,17 @@ func (e *requestError) Unwrap() error {
 	return e.err
 }
 
+// handlerErrorCode returns the code to answer a handler's error with: that
+// of an *mcp.JSONRPCErrorError in its chain, e.g. the error of a composed
+// server a call was forwarded to, or code otherwise.
+func handlerErrorCode(err error, code int) int {
+	var rpcErr *mcp.JSONRPCErrorError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code
+	}
+	return code
+}
+
 // NotificationHandlerFunc handles incoming notifications.
 type NotificationHandlerFunc func(ctx context.Context, notification mcp.JSONRPCNotification)
 
@@ -131,21 +262,41 @@ type MCPServer struct {
 	notificationHandlersMu sync.RWMutex
 	capabilitiesMu         sync.RWMutex
 	toolFiltersMu          sync.RWMutex
+	subscriptionsMu        sync.RWMutex // guards the resource subscriptions in session states
 
 	name                   string
 	version                string
 	instructions           string
+	instructionsFunc       InstructionsFunc
 	resources              map[string]resourceEntry
 	resourceTemplates      map[string]resourceTemplateEntry
+	resourceMatchers       []resourceMatcherEntry
 	prompts                map[string]mcp.Prompt
 	promptHandlers         map[string]PromptHandlerFunc
 	tools                  map[string]ServerTool
 	toolHandlerMiddlewares []ToolHandlerMiddleware
 	toolFilters            []ToolFilterFunc
+	scopeChecker           ScopeCheckerFunc
+	toolAuthorizer         ToolAuthorizerFunc
+	toolDryRun             bool
+	responseCorrelationID  bool
+	strictToolNames        bool
+	lenientJSONRPCVersion  bool
+	debugMethods           bool
+	completionHandler      CompletionHandlerFunc
 	notificationHandlers   map[string]NotificationHandlerFunc
 	capabilities           serverCapabilities
 	paginationLimit        *int
+	maxToolResultBytes     int
+	maxRequestBytes        int
+	clientRequestTimeout   time.Duration
+	toolResultOverflowErr  bool
+	toolSchemaLintf        func(format string, v ...any)
+	resourceChunkSize      int
+	requestDedup           *requestDedup
 	sessions               sync.Map
+	sessionStates          sync.Map
+	sessionClosed          sync.Map // session ID -> chan struct{}, closed on unregister
 	hooks                  *Hooks
 }
 
@@ -158,10 +309,12 @@ func WithPaginationLimit(limit int) ServerOption {
 
 // serverCapabilities defines the supported features of the MCP server
 type serverCapabilities struct {
-	tools     *toolCapabilities
-	resources *resourceCapabilities
-	prompts   *promptCapabilities
-	logging   bool
+	tools        *toolCapabilities
+	resources    *resourceCapabilities
+	prompts      *promptCapabilities
+	logging      bool
+	completions  bool
+	experimental map[string]any
 }
 
 // resourceCapabilities defines the supported resource-related features
@@ -214,6 +367,26 @@ func WithToolFilter(
 	}
 }
 
+// WithScopeChecker sets the function used to verify the RequiredScopes of a
+// tool before its handler is called, typically against credentials that an
+// HTTPContextFunc stored in the context. Calls to a tool with required scopes
+// are rejected if no scope checker is set.
+func WithScopeChecker(checker ScopeCheckerFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.scopeChecker = checker
+	}
+}
+
+// WithToolAuthorizer sets a function that authorizes every tool call before
+// its handler runs, after the tool's RequiredScopes have been checked. A
+// denied call fails with mcp.FORBIDDEN and an error wrapping ErrForbidden
+// and the authorizer's error.
+func WithToolAuthorizer(authorizer ToolAuthorizerFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.toolAuthorizer = authorizer
+	}
+}
+
 // WithRecovery adds a middleware that recovers from panics in tool handlers.
 func WithRecovery() ServerOption {
 	return WithToolHandlerMiddleware(func(next ToolHandlerFunc) ToolHandlerFunc {
@@ -228,6 +401,39 @@ func WithRecovery() ServerOption {
 	})
 }
 
+// WithCancellationAsToolError adds a middleware that turns a tool handler
+// returning context.Canceled or context.DeadlineExceeded into a tool result
+// with IsError set and a "cancelled" or "timed out" message, so the client
+// sees a tool error rather than an INTERNAL_ERROR response. Partial results
+// returned along with the error are passed on unchanged.
+func WithCancellationAsToolError() ServerOption {
+	return WithToolHandlerMiddleware(func(next ToolHandlerFunc) ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if result != nil || err == nil {
+				return result, err
+			}
+			switch {
+			case errors.Is(err, context.Canceled):
+				return mcp.NewToolResultError(fmt.Sprintf("tool %s cancelled", request.Params.Name)), nil
+			case errors.Is(err, context.DeadlineExceeded):
+				return mcp.NewToolResultError(fmt.Sprintf("tool %s timed out", request.Params.Name)), nil
+			}
+			return result, err
+		}
+	})
+}
+
+// WithLenientJSONRPCVersion makes the server accept messages without a
+// jsonrpc field, treating them as JSON-RPC 2.0, for clients that omit it.
+// Messages with any version other than "2.0" are still rejected with
+// INVALID_REQUEST. By default, a missing version is rejected as well.
+func WithLenientJSONRPCVersion() ServerOption {
+	return func(s *MCPServer) {
+		s.lenientJSONRPCVersion = true
+	}
+}
+
 // WithHooks allows adding hooks that will be called before or after
 // either [all] requests or before / after specific request methods, or else
 // prior to returning an error to the client.
@@ -264,6 +470,34 @@ func WithLogging() ServerOption {
 	}
 }
 
+// WithCompletions enables the completions capability, telling clients the
+// server offers autocompletion suggestions. It is enabled as well when a
+// resource template with a completion function or a completion handler is
+// added.
+func WithCompletions() ServerOption {
+	return func(s *MCPServer) {
+		s.capabilities.completions = true
+	}
+}
+
+// WithExperimentalCapabilities adds experimental, non-standard capabilities
+// to those advertised under capabilities.experimental in the initialize
+// response, keyed by feature name. Using the option several times merges the
+// maps, with later values replacing earlier ones for the same feature.
+// Clients can check for them with mcp.ServerCapabilities.HasExperimental,
+// and the server for the client's with
+// mcp.ClientCapabilities.HasExperimental on the initialize request.
+func WithExperimentalCapabilities(experimental map[string]any) ServerOption {
+	return func(s *MCPServer) {
+		if s.capabilities.experimental == nil {
+			s.capabilities.experimental = make(map[string]any, len(experimental))
+		}
+		for feature, value := range experimental {
+			s.capabilities.experimental[feature] = value
+		}
+	}
+}
+
 // WithInstructions sets the server instructions for the client returned in the initialize response
 func WithInstructions(instructions string) ServerOption {
 	return func(s *MCPServer) {
@@ -271,6 +505,20 @@ func WithInstructions(instructions string) ServerOption {
 	}
 }
 
+// InstructionsFunc computes the instructions returned to a client in the
+// initialize response.
+type InstructionsFunc func(ctx context.Context, request mcp.InitializeRequest) string
+
+// WithInstructionsFunc sets a function computing the instructions returned in
+// the initialize response from the request and its context, e.g. to give
+// each tenant of a multi-tenant server its own guidance. It overrides
+// WithInstructions.
+func WithInstructionsFunc(fn InstructionsFunc) ServerOption {
+	return func(s *MCPServer) {
+		s.instructionsFunc = fn
+	}
+}
+
 // NewMCPServer creates a new MCP server instance with the given name, version and options
 func NewMCPServer(
 	name, version string,
@@ -300,23 +548,83 @@ func NewMCPServer(
 	return s
 }
 
-// AddResource registers a new resource and its handler
-func (s *MCPServer) AddResource(
-	resource mcp.Resource,
-	handler ResourceHandlerFunc,
-) {
+// NewMCPServerWithAllCapabilities creates a new MCP server like NewMCPServer,
+// with resource capabilities including subscriptions, prompt and tool
+// capabilities with list change notifications, and logging enabled. The
+// extra options are applied afterwards and may override these.
+func NewMCPServerWithAllCapabilities(
+	name, version string,
+	extra ...ServerOption,
+) *MCPServer {
+	opts := []ServerOption{
+		WithResourceCapabilities(true, true),
+		WithPromptCapabilities(true),
+		WithToolCapabilities(true),
+		WithLogging(),
+	}
+	return NewMCPServer(name, version, append(opts, extra...)...)
+}
+
+// implicitlyRegisterCapabilities calls register under the capabilities lock
+// unless check reports that the capability is already enabled.
+func (s *MCPServer) implicitlyRegisterCapabilities(check func() bool, register func()) {
 	s.capabilitiesMu.RLock()
-	if s.capabilities.resources == nil {
+	if check() {
 		s.capabilitiesMu.RUnlock()
+		return
+	}
+	s.capabilitiesMu.RUnlock()
 
-		s.capabilitiesMu.Lock()
-		if s.capabilities.resources == nil {
-			s.capabilities.resources = &resourceCapabilities{}
-		}
-		s.capabilitiesMu.Unlock()
-	} else {
-		s.capabilitiesMu.RUnlock()
+	s.capabilitiesMu.Lock()
+	if !check() {
+		register()
 	}
+	s.capabilitiesMu.Unlock()
+}
+
+// implicitlyRegisterResourceCapabilities enables the resources capability,
+// without subscriptions or list change notifications, unless it already is.
+func (s *MCPServer) implicitlyRegisterResourceCapabilities() {
+	s.implicitlyRegisterCapabilities(
+		func() bool { return s.capabilities.resources != nil },
+		func() { s.capabilities.resources = &resourceCapabilities{} },
+	)
+}
+
+// implicitlyRegisterPromptCapabilities enables the prompts capability,
+// without list change notifications, unless it already is.
+func (s *MCPServer) implicitlyRegisterPromptCapabilities() {
+	s.implicitlyRegisterCapabilities(
+		func() bool { return s.capabilities.prompts != nil },
+		func() { s.capabilities.prompts = &promptCapabilities{} },
+	)
+}
+
+// implicitlyRegisterCompletionCapabilities enables the completions
+// capability unless it already is.
+func (s *MCPServer) implicitlyRegisterCompletionCapabilities() {
+	s.implicitlyRegisterCapabilities(
+		func() bool { return s.capabilities.completions },
+		func() { s.capabilities.completions = true },
+	)
+}
+
+// implicitlyRegisterToolCapabilities enables the tools capability, without
+// list change notifications, unless it already is.
+func (s *MCPServer) implicitlyRegisterToolCapabilities() {
+	s.implicitlyRegisterCapabilities(
+		func() bool { return s.capabilities.tools != nil },
+		func() { s.capabilities.tools = &toolCapabilities{} },
+	)
+}
+
+// AddResource registers a new resource and its handler, enabling the
+// resources capability if it was not enabled with WithResourceCapabilities.
+func (s *MCPServer) AddResource(
+	resource mcp.Resource,
+	handler ResourceHandlerFunc,
+) {
+	s.implicitlyRegisterResourceCapabilities()
 
 	s.resourcesMu.Lock()
 	s.resources[resource.URI] = resourceEntry{
@@ -352,23 +660,49 @@ func (s *MCPServer) AddResourceTemplate(
 	template mcp.ResourceTemplate,
 	handler ResourceTemplateHandlerFunc,
 ) {
-	s.capabilitiesMu.RLock()
-	if s.capabilities.resources == nil {
-		s.capabilitiesMu.RUnlock()
+	s.AddResourceTemplateWithCompletion(template, handler, nil)
+}
 
-		s.capabilitiesMu.Lock()
-		if s.capabilities.resources == nil {
-			s.capabilities.resources = &resourceCapabilities{}
+// AddResourceTemplateWithCompletion registers a new resource template and its
+// handler, along with a completion function answering completion/complete
+// requests for the template's variables.
+func (s *MCPServer) AddResourceTemplateWithCompletion(
+	template mcp.ResourceTemplate,
+	handler ResourceTemplateHandlerFunc,
+	completion ResourceTemplateCompletionFunc,
+) {
+	var completionResult ResourceTemplateCompletionResultFunc
+	if completion != nil {
+		completionResult = func(ctx context.Context, argument, value string) (*mcp.Completion, error) {
+			values, err := completion(ctx, argument, value)
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.Completion{Values: values}, nil
 		}
-		s.capabilitiesMu.Unlock()
-	} else {
-		s.capabilitiesMu.RUnlock()
+	}
+	s.AddResourceTemplateWithCompletionResult(template, handler, completionResult)
+}
+
+// AddResourceTemplateWithCompletionResult registers a new resource template
+// and its handler like AddResourceTemplateWithCompletion, with a completion
+// function that also reports the total number of values and whether more are
+// available.
+func (s *MCPServer) AddResourceTemplateWithCompletionResult(
+	template mcp.ResourceTemplate,
+	handler ResourceTemplateHandlerFunc,
+	completion ResourceTemplateCompletionResultFunc,
+) {
+	s.implicitlyRegisterResourceCapabilities()
+	if completion != nil {
+		s.implicitlyRegisterCompletionCapabilities()
 	}
 
 	s.resourcesMu.Lock()
 	s.resourceTemplates[template.URITemplate.Raw()] = resourceTemplateEntry{
-		template: template,
-		handler:  handler,
+		template:   template,
+		handler:    handler,
+		completion: completion,
 	}
 	s.resourcesMu.Unlock()
 
@@ -379,20 +713,10 @@ func (s *MCPServer) AddResourceTemplate(
 	}
 }
 
-// AddPrompt registers a new prompt handler with the given name
+// AddPrompt registers a new prompt handler with the given name, enabling the
+// prompts capability if it was not enabled with WithPromptCapabilities.
 func (s *MCPServer) AddPrompt(prompt mcp.Prompt, handler PromptHandlerFunc) {
-	s.capabilitiesMu.RLock()
-	if s.capabilities.prompts == nil {
-		s.capabilitiesMu.RUnlock()
-
-		s.capabilitiesMu.Lock()
-		if s.capabilities.prompts == nil {
-			s.capabilities.prompts = &promptCapabilities{}
-		}
-		s.capabilitiesMu.Unlock()
-	} else {
-		s.capabilitiesMu.RUnlock()
-	}
+	s.implicitlyRegisterPromptCapabilities()
 
 	s.promptsMu.Lock()
 	s.prompts[prompt.Name] = prompt
@@ -411,20 +735,10 @@ func (s *MCPServer) AddTool(tool mcp.Tool, handler ToolHandlerFunc) {
 	s.AddTools(ServerTool{Tool: tool, Handler: handler})
 }
 
-// AddTools registers multiple tools at once
+// AddTools registers multiple tools at once, enabling the tools capability if
+// it was not enabled with WithToolCapabilities.
 func (s *MCPServer) AddTools(tools ...ServerTool) {
-	s.capabilitiesMu.RLock()
-	if s.capabilities.tools == nil {
-		s.capabilitiesMu.RUnlock()
-
-		s.capabilitiesMu.Lock()
-		if s.capabilities.tools == nil {
-			s.capabilities.tools = &toolCapabilities{}
-		}
-		s.capabilitiesMu.Unlock()
-	} else {
-		s.capabilitiesMu.RUnlock()
-	}
+	s.prepareTools(tools)
 
 	s.toolsMu.Lock()
 	for _, entry := range tools {
@@ -439,12 +753,44 @@ func (s *MCPServer) AddTools(tools ...ServerTool) {
 	}
 }
 
-// SetTools replaces all existing tools with the provided list
+// SetTools replaces all existing tools with the provided list. The new set
+// is swapped in at once, so concurrent requests see either the old or the
+// new tools, never an empty or partial set.
 func (s *MCPServer) SetTools(tools ...ServerTool) {
+	s.prepareTools(tools)
+
+	toolMap := make(map[string]ServerTool, len(tools))
+	for _, entry := range tools {
+		toolMap[entry.Tool.Name] = entry
+	}
 	s.toolsMu.Lock()
-	s.tools = make(map[string]ServerTool, len(tools))
+	s.tools = toolMap
 	s.toolsMu.Unlock()
-	s.AddTools(tools...)
+
+	// When the list of available tools changes, servers that declared the listChanged capability SHOULD send a notification.
+	if s.capabilities.tools.listChanged {
+		// Send notification to all initialized sessions
+		s.SendNotificationToAllClients(mcp.MethodNotificationToolsListChanged, nil)
+	}
+}
+
+// prepareTools enables the tool capabilities and checks tools before they
+// are registered, panicking on invalid names with WithStrictToolNames.
+func (s *MCPServer) prepareTools(tools []ServerTool) {
+	s.implicitlyRegisterToolCapabilities()
+
+	if s.strictToolNames {
+		for _, entry := range tools {
+			if err := checkToolName(entry.Tool.Name); err != nil {
+				panic(err)
+			}
+		}
+	}
+	if s.toolSchemaLintf != nil {
+		for _, entry := range tools {
+			s.lintToolSchema(entry.Tool)
+		}
+	}
 }
 
 // DeleteTools removes a tool from the server
@@ -466,6 +812,55 @@ func (s *MCPServer) DeleteTools(names ...string) {
 	}
 }
 
+// Tools returns a snapshot of the tools registered on the server, sorted by
+// name. Session-specific tools and tool filters are not taken into account.
+func (s *MCPServer) Tools() []mcp.Tool {
+	s.toolsMu.RLock()
+	tools := make([]mcp.Tool, 0, len(s.tools))
+	for _, entry := range s.tools {
+		tools = append(tools, entry.Tool)
+	}
+	s.toolsMu.RUnlock()
+
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+	return tools
+}
+
+// Prompts returns a snapshot of the prompts registered on the server, sorted
+// by name. Session-specific prompts are not taken into account.
+func (s *MCPServer) Prompts() []mcp.Prompt {
+	s.promptsMu.RLock()
+	prompts := make([]mcp.Prompt, 0, len(s.prompts))
+	for _, prompt := range s.prompts {
+		prompts = append(prompts, prompt)
+	}
+	s.promptsMu.RUnlock()
+
+	sort.Slice(prompts, func(i, j int) bool {
+		return prompts[i].Name < prompts[j].Name
+	})
+	return prompts
+}
+
+// Resources returns a snapshot of the resources registered on the server,
+// sorted by URI. Resource templates and session-specific resources are not
+// included.
+func (s *MCPServer) Resources() []mcp.Resource {
+	s.resourcesMu.RLock()
+	resources := make([]mcp.Resource, 0, len(s.resources))
+	for _, entry := range s.resources {
+		resources = append(resources, entry.resource)
+	}
+	s.resourcesMu.RUnlock()
+
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].URI < resources[j].URI
+	})
+	return resources
+}
+
 // AddNotificationHandler registers a new handler for incoming notifications
 func (s *MCPServer) AddNotificationHandler(
 	method string,
@@ -483,6 +878,13 @@ func (s *MCPServer) handleInitialize(
 ) (*mcp.InitializeResult, *requestError) {
 	capabilities := mcp.ServerCapabilities{}
 
+	// Capabilities are advertised when enabled with their option or by
+	// registering a tool, prompt or resource, regardless of how many are
+	// currently registered: tools may only be added per session after
+	// initialization, or all be deleted later on. The lock is released
+	// before running hooks and callbacks, which may register more.
+	s.capabilitiesMu.RLock()
+
 	// Only add resource capabilities if they're configured
 	if s.capabilities.resources != nil {
 		capabilities.Resources = &struct {
@@ -516,8 +918,26 @@ func (s *MCPServer) handleInitialize(
 		capabilities.Logging = &struct{}{}
 	}
 
+	if s.capabilities.completions {
+		capabilities.Completions = &struct{}{}
+	}
+
+	if len(s.capabilities.experimental) > 0 {
+		capabilities.Experimental = s.capabilities.experimental
+	}
+	s.capabilitiesMu.RUnlock()
+
+	// Answer with the requested version if supported. Otherwise answer with
+	// the latest one rather than failing, as the client decides whether it
+	// can work with it.
+	protocolVersion := request.Params.ProtocolVersion
+	if !slices.Contains(mcp.ValidProtocolVersions(), protocolVersion) {
+		s.hooks.onProtocolVersionMismatch(ctx, id, protocolVersion, mcp.LATEST_PROTOCOL_VERSION)
+		protocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	}
+
 	result := mcp.InitializeResult{
-		ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+		ProtocolVersion: protocolVersion,
 		ServerInfo: mcp.Implementation{
 			Name:    s.name,
 			Version: s.version,
@@ -525,8 +945,12 @@ func (s *MCPServer) handleInitialize(
 		Capabilities: capabilities,
 		Instructions: s.instructions,
 	}
+	if s.instructionsFunc != nil {
+		result.Instructions = s.instructionsFunc(ctx, request)
+	}
 
 	if session := ClientSessionFromContext(ctx); session != nil {
+		s.recordClientInfo(session, request)
 		session.Initialize()
 	}
 	return &result, nil
@@ -545,6 +969,21 @@ func listByPagination[T mcp.Named](
 	s *MCPServer,
 	cursor mcp.Cursor,
 	allElements []T,
+) ([]T, mcp.Cursor, error) {
+	return listByPaginationKey(ctx, s, cursor, allElements, func(element T) string {
+		return element.GetName()
+	})
+}
+
+// listByPaginationKey returns the page of allElements following cursor.
+// allElements must be sorted by key, which must be unique, as cursors hold
+// the key of the last element of the previous page.
+func listByPaginationKey[T any](
+	ctx context.Context,
+	s *MCPServer,
+	cursor mcp.Cursor,
+	allElements []T,
+	key func(T) string,
 ) ([]T, mcp.Cursor, error) {
 	startPos := 0
 	if cursor != "" {
@@ -554,7 +993,7 @@ func listByPagination[T mcp.Named](
 		}
 		cString := string(c)
 		startPos = sort.Search(len(allElements), func(i int) bool {
-			return allElements[i].GetName() > cString
+			return key(allElements[i]) > cString
 		})
 	}
 	endPos := len(allElements)
@@ -567,7 +1006,7 @@ func listByPagination[T mcp.Named](
 	// set the next cursor
 	nextCursor := func() mcp.Cursor {
 		if s.paginationLimit != nil && len(elementsToReturn) >= *s.paginationLimit {
-			nc := elementsToReturn[len(elementsToReturn)-1].GetName()
+			nc := key(elementsToReturn[len(elementsToReturn)-1])
 			toString := base64.StdEncoding.EncodeToString([]byte(nc))
 			return mcp.Cursor(toString)
 		}
@@ -583,11 +1022,42 @@ func (s *MCPServer) handleListResources(
 ) (*mcp.ListResourcesResult, *requestError) {
 	s.resourcesMu.RLock()
 	resources := make([]mcp.Resource, 0, len(s.resources))
-	for _, entry := range s.resources {
+	uris := make(map[string]struct{}, len(s.resources))
+	for uri, entry := range s.resources {
 		resources = append(resources, entry.resource)
+		uris[uri] = struct{}{}
 	}
 	s.resourcesMu.RUnlock()
 
+	// Add the resources listed by resource matchers
+	matched, err := s.listMatchedResources(ctx, uris)
+	if err != nil {
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INTERNAL_ERROR,
+			err:  err,
+		}
+	}
+	resources = append(resources, matched...)
+
+	// Merge in session-specific resources, which override global ones
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithResources); ok {
+		if sessionResources := session.GetSessionResources(); len(sessionResources) > 0 {
+			resourceMap := make(map[string]mcp.Resource, len(resources)+len(sessionResources))
+			for _, resource := range resources {
+				resourceMap[resource.URI] = resource
+			}
+			for uri, serverResource := range sessionResources {
+				resourceMap[uri] = serverResource.Resource
+			}
+
+			resources = make([]mcp.Resource, 0, len(resourceMap))
+			for _, resource := range resourceMap {
+				resources = append(resources, resource)
+			}
+		}
+	}
+
 	// Sort the resources by name
 	sort.Slice(resources, func(i, j int) bool {
 		return resources[i].Name < resources[j].Name
@@ -620,10 +1090,12 @@ func (s *MCPServer) handleListResourceTemplates(
 		templates = append(templates, entry.template)
 	}
 	s.resourcesMu.RUnlock()
+	// Templates are keyed by their URI template, as their names may collide
 	sort.Slice(templates, func(i, j int) bool {
-		return templates[i].Name < templates[j].Name
+		return templates[i].URITemplate.Raw() < templates[j].URITemplate.Raw()
 	})
-	templatesToReturn, nextCursor, err := listByPagination[mcp.ResourceTemplate](ctx, s, request.Params.Cursor, templates)
+	templatesToReturn, nextCursor, err := listByPaginationKey(ctx, s, request.Params.Cursor, templates,
+		func(template mcp.ResourceTemplate) string { return template.URITemplate.Raw() })
 	if err != nil {
 		return nil, &requestError{
 			id:   id,
@@ -645,6 +1117,21 @@ func (s *MCPServer) handleReadResource(
 	id any,
 	request mcp.ReadResourceRequest,
 ) (*mcp.ReadResourceResult, *requestError) {
+	// First check session-specific resources
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithResources); ok {
+		if serverResource, ok := session.GetSessionResources()[request.Params.URI]; ok {
+			contents, err := serverResource.Handler(ctx, request)
+			if err != nil {
+				return nil, &requestError{
+					id:   id,
+					code: handlerErrorCode(err, mcp.INTERNAL_ERROR),
+					err:  err,
+				}
+			}
+			return &mcp.ReadResourceResult{Contents: contents}, nil
+		}
+	}
+
 	s.resourcesMu.RLock()
 	// First try direct resource handlers
 	if entry, ok := s.resources[request.Params.URI]; ok {
@@ -654,7 +1141,7 @@ func (s *MCPServer) handleReadResource(
 		if err != nil {
 			return nil, &requestError{
 				id:   id,
-				code: mcp.INTERNAL_ERROR,
+				code: handlerErrorCode(err, mcp.INTERNAL_ERROR),
 				err:  err,
 			}
 		}
@@ -678,14 +1165,26 @@ func (s *MCPServer) handleReadResource(
 			break
 		}
 	}
+	// Finally, try the resource matchers in the order they were added
+	matchers := s.resourceMatchers
 	s.resourcesMu.RUnlock()
 
+	if !matched {
+		for _, entry := range matchers {
+			if entry.matcher(request.Params.URI) {
+				matchedHandler = ResourceTemplateHandlerFunc(entry.handler)
+				matched = true
+				break
+			}
+		}
+	}
+
 	if matched {
 		contents, err := matchedHandler(ctx, request)
 		if err != nil {
 			return nil, &requestError{
 				id:   id,
-				code: mcp.INTERNAL_ERROR,
+				code: handlerErrorCode(err, mcp.INTERNAL_ERROR),
 				err:  err,
 			}
 		}
@@ -716,6 +1215,24 @@ func (s *MCPServer) handleListPrompts(
 	}
 	s.promptsMu.RUnlock()
 
+	// Merge in session-specific prompts, which override global ones
+	if session, ok := ClientSessionFromContext(ctx).(SessionWithPrompts); ok {
+		if sessionPrompts := session.GetSessionPrompts(); len(sessionPrompts) > 0 {
+			promptMap := make(map[string]mcp.Prompt, len(prompts)+len(sessionPrompts))
+			for _, prompt := range prompts {
+				promptMap[prompt.Name] = prompt
+			}
+			for name, serverPrompt := range sessionPrompts {
+				promptMap[name] = serverPrompt.Prompt
+			}
+
+			prompts = make([]mcp.Prompt, 0, len(promptMap))
+			for _, prompt := range promptMap {
+				prompts = append(prompts, prompt)
+			}
+		}
+	}
+
 	// sort prompts by name
 	sort.Slice(prompts, func(i, j int) bool {
 		return prompts[i].Name < prompts[j].Name
@@ -742,9 +1259,23 @@ func (s *MCPServer) handleGetPrompt(
 	id any,
 	request mcp.GetPromptRequest,
 ) (*mcp.GetPromptResult, *requestError) {
-	s.promptsMu.RLock()
-	handler, ok := s.promptHandlers[request.Params.Name]
-	s.promptsMu.RUnlock()
+	var handler PromptHandlerFunc
+	var ok bool
+
+	// First check session-specific prompts
+	if session, typeAssertOk := ClientSessionFromContext(ctx).(SessionWithPrompts); typeAssertOk {
+		var serverPrompt ServerPrompt
+		if serverPrompt, ok = session.GetSessionPrompts()[request.Params.Name]; ok {
+			handler = serverPrompt.Handler
+		}
+	}
+
+	// If not found in session prompts, check global prompts
+	if !ok {
+		s.promptsMu.RLock()
+		handler, ok = s.promptHandlers[request.Params.Name]
+		s.promptsMu.RUnlock()
+	}
 
 	if !ok {
 		return nil, &requestError{
@@ -756,12 +1287,27 @@ func (s *MCPServer) handleGetPrompt(
 
 	result, err := handler(ctx, request)
 	if err != nil {
+		code := handlerErrorCode(err, mcp.INTERNAL_ERROR)
+		if errors.Is(err, ErrInvalidPromptArguments) {
+			code = mcp.INVALID_PARAMS
+		}
 		return nil, &requestError{
 			id:   id,
-			code: mcp.INTERNAL_ERROR,
+			code: code,
 			err:  err,
 		}
 	}
+	if result != nil {
+		for i, message := range result.Messages {
+			if !message.Role.IsValid() {
+				return nil, &requestError{
+					id:   id,
+					code: mcp.INTERNAL_ERROR,
+					err:  fmt.Errorf("prompt '%s' message %d has invalid role %q", request.Params.Name, i, message.Role),
+				}
+			}
+		}
+	}
 
 	return result, nil
 }
@@ -888,6 +1434,41 @@ func (s *MCPServer) handleToolCall(
 		}
 	}
 
+	if len(tool.RequiredScopes) > 0 {
+		if err := s.checkScopes(ctx, tool.RequiredScopes); err != nil {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.UNAUTHORIZED,
+				err:  fmt.Errorf("%w: tool '%s': %w", ErrUnauthorized, request.Params.Name, err),
+			}
+		}
+	}
+
+	if s.toolAuthorizer != nil {
+		if err := s.toolAuthorizer(ctx, tool.Tool, request); err != nil {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.FORBIDDEN,
+				err:  fmt.Errorf("%w: tool '%s': %w", ErrForbidden, request.Params.Name, err),
+			}
+		}
+	}
+
+	if s.toolDryRun && isDryRun(request.Params.Meta) {
+		result, err := dryRunTool(tool.Tool, request)
+		if err != nil {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.INTERNAL_ERROR,
+				err:  err,
+			}
+		}
+		return result, nil
+	}
+
+	ctx = withProgressToken(ctx, request.Params.Meta.GetProgressToken())
+	ctx = context.WithValue(ctx, calledToolKey{}, tool.Tool)
+
 	finalHandler := tool.Handler
 
 	s.middlewareMu.RLock()
@@ -900,6 +1481,125 @@ func (s *MCPServer) handleToolCall(
 	}
 
 	result, err := finalHandler(ctx, request)
+	if err != nil {
+		// Prefer a partial result over the error, so the client still sees
+		// the content the tool managed to produce.
+		if result == nil {
+			code := handlerErrorCode(err, mcp.INTERNAL_ERROR)
+			if errors.Is(err, ErrInvalidToolArguments) {
+				code = mcp.INVALID_PARAMS
+			}
+			return nil, &requestError{
+				id:   id,
+				code: code,
+				err:  err,
+			}
+		}
+		// Report the error and keep its message visible in a copy of the
+		// result, as the handler may hold on to the one it returned
+		s.hooks.onError(ctx, id, mcp.MethodToolsCall, &request, err)
+		partial := *result
+		partial.Content = append(slices.Clone(result.Content), mcp.NewTextContent(err.Error()))
+		partial.IsError = true
+		result = &partial
+	}
+
+	if result != nil && s.maxToolResultBytes > 0 {
+		result, err = s.limitToolResult(request.Params.Name, result)
+		if err != nil {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.INTERNAL_ERROR,
+				err:  err,
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// checkScopes verifies the caller holds the given scopes using the configured
+// scope checker.
+func (s *MCPServer) checkScopes(ctx context.Context, scopes []string) error {
+	if s.scopeChecker == nil {
+		return errors.New("no scope checker configured")
+	}
+	return s.scopeChecker(ctx, scopes)
+}
+
+// maxCompletionValues is the maximum number of values a completion result
+// may contain.
+const maxCompletionValues = 100
+
+// CompletionHandlerFunc answers completion/complete requests the server
+// has no completion function for, returning the suggested values for the
+// request's argument. A nil completion means no suggestions.
+type CompletionHandlerFunc func(ctx context.Context, request mcp.CompleteRequest) (*mcp.Completion, error)
+
+// SetCompletionHandler sets the handler answering completion/complete
+// requests for prompt arguments and for resource templates registered
+// without a completion function, and enables the completions capability.
+func (s *MCPServer) SetCompletionHandler(handler CompletionHandlerFunc) {
+	s.capabilitiesMu.Lock()
+	defer s.capabilitiesMu.Unlock()
+	s.completionHandler = handler
+	s.capabilities.completions = true
+}
+
+func (s *MCPServer) handleComplete(
+	ctx context.Context,
+	id any,
+	request mcp.CompleteRequest,
+) (*mcp.CompleteResult, *requestError) {
+	ref, _ := request.Params.Ref.(map[string]any)
+	refType, _ := ref["type"].(string)
+
+	s.capabilitiesMu.RLock()
+	handler := s.completionHandler
+	s.capabilitiesMu.RUnlock()
+	complete := func() (*mcp.Completion, error) {
+		if handler == nil {
+			return nil, nil
+		}
+		return handler(ctx, request)
+	}
+
+	switch refType {
+	case "ref/resource":
+		uri, _ := ref["uri"].(string)
+		s.resourcesMu.RLock()
+		entry, ok := s.resourceTemplates[uri]
+		s.resourcesMu.RUnlock()
+		if !ok && handler == nil {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.INVALID_PARAMS,
+				err:  fmt.Errorf("resource template '%s' not found: %w", uri, ErrResourceNotFound),
+			}
+		}
+		if ok && !slices.Contains(entry.template.URITemplate.Varnames(), request.Params.Argument.Name) {
+			return nil, &requestError{
+				id:   id,
+				code: mcp.INVALID_PARAMS,
+				err:  fmt.Errorf("resource template '%s' has no variable '%s'", uri, request.Params.Argument.Name),
+			}
+		}
+		if ok && entry.completion != nil {
+			complete = func() (*mcp.Completion, error) {
+				return entry.completion(ctx, request.Params.Argument.Name, request.Params.Argument.Value)
+			}
+		}
+	case "ref/prompt":
+		// Prompt arguments are only completed by the completion handler
+	default:
+		return nil, &requestError{
+			id:   id,
+			code: mcp.INVALID_PARAMS,
+			err:  fmt.Errorf("completion reference type '%s' %w", refType, ErrUnsupported),
+		}
+	}
+
+	completion, err := complete()
 	if err != nil {
 		return nil, &requestError{
 			id:   id,
@@ -908,6 +1608,22 @@ func (s *MCPServer) handleToolCall(
 		}
 	}
 
+	result := &mcp.CompleteResult{}
+	result.Completion.Values = []string{}
+	if completion == nil {
+		return result, nil
+	}
+	values := completion.Values
+	result.Completion.Total = completion.Total
+	result.Completion.HasMore = completion.HasMore
+	if len(values) > maxCompletionValues {
+		result.Completion.Total = max(result.Completion.Total, len(values))
+		result.Completion.HasMore = true
+		values = values[:maxCompletionValues]
+	}
+	if values != nil {
+		result.Completion.Values = values
+	}
 	return result, nil
 }
 
@@ -87,6 +87,12 @@ func TestRaceConditions(t *testing.T) {
 		require.NotNil(t, result, "List prompts result should not be nil")
 	})
 
+	runConcurrentOperation(&wg, testDuration, "snapshots", func() {
+		srv.Tools()
+		srv.Prompts()
+		srv.Resources()
+	})
+
 	// Add a persistent tool for testing tool calls
 	srv.AddTool(mcp.Tool{
 		Name:        "persistent-tool",
@@ -188,3 +194,40 @@ func TestConcurrentPromptAdd(t *testing.T) {
 		}
 	}, 1*time.Second, 10*time.Millisecond, "Deadlock detected: operation did not complete in time")
 }
+
+// TestConcurrentSetTools checks that tools/call never sees the transient
+// empty set of a SetTools replacing the tools with the same ones
+func TestConcurrentSetTools(t *testing.T) {
+	srv := NewMCPServer("test-server", "1.0.0")
+	handler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	tools := []ServerTool{
+		{Tool: mcp.NewTool("first"), Handler: handler},
+		{Tool: mcp.NewTool("second"), Handler: handler},
+	}
+	srv.SetTools(tools...)
+
+	var wg sync.WaitGroup
+	testDuration := 300 * time.Millisecond
+	runConcurrentOperation(&wg, testDuration, "set-tools", func() {
+		srv.SetTools(tools...)
+	})
+
+	var mu sync.Mutex
+	var failures []mcp.JSONRPCMessage
+	for _, name := range []string{"first", "second"} {
+		message := []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "` + name + `"}}`)
+		runConcurrentOperation(&wg, testDuration, "call-"+name, func() {
+			response := srv.HandleMessage(context.Background(), message)
+			if _, ok := response.(mcp.JSONRPCResponse); !ok {
+				mu.Lock()
+				failures = append(failures, response)
+				mu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+	assert.Empty(t, failures, "tools/call must always find the tools")
+}
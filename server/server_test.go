@@ -7,7 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -148,6 +151,188 @@ func TestMCPServer_Capabilities(t *testing.T) {
 	}
 }
 
+func TestMCPServer_CapabilitiesWithoutRegistrations(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0",
+		WithResourceCapabilities(false, false),
+		WithPromptCapabilities(false),
+		WithToolCapabilities(true),
+	)
+
+	// Tools that were registered and deleted again leave the capability in place
+	server.AddTool(mcp.NewTool("temporary"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	server.DeleteTools("temporary")
+
+	response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	initResult, ok := resp.Result.(mcp.InitializeResult)
+	require.True(t, ok)
+
+	require.NotNil(t, initResult.Capabilities.Tools, "tools capability should be advertised with zero global tools")
+	assert.True(t, initResult.Capabilities.Tools.ListChanged)
+	assert.NotNil(t, initResult.Capabilities.Prompts)
+	assert.NotNil(t, initResult.Capabilities.Resources)
+
+	// The advertised capabilities allow listing the (empty) tools
+	response = server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`))
+	resp, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	assert.Empty(t, resp.Result.(mcp.ListToolsResult).Tools)
+}
+
+func TestMCPServer_AddBeforeCapabilities(t *testing.T) {
+	toolHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	}
+	resourceHandler := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, nil
+	}
+	promptHandler := func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	}
+	newSession := func(t *testing.T, server *MCPServer) string {
+		session := &sessionTestClientWithResourcesAndPrompts{sessionTestClient: sessionTestClient{
+			sessionID:           "session-1",
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+			initialized:         true,
+		}}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		return session.SessionID()
+	}
+	newToolSession := func(t *testing.T, server *MCPServer) string {
+		session := &sessionTestClientWithTools{
+			sessionID:           "session-1",
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+			initialized:         true,
+		}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		return session.SessionID()
+	}
+
+	tests := []struct {
+		name       string
+		add        func(t *testing.T, server *MCPServer)
+		listMethod string
+		capability func(mcp.ServerCapabilities) bool
+	}{
+		{
+			name: "tool",
+			add: func(t *testing.T, server *MCPServer) {
+				server.AddTool(mcp.NewTool("test-tool"), toolHandler)
+			},
+			listMethod: "tools/list",
+			capability: func(c mcp.ServerCapabilities) bool { return c.Tools != nil },
+		},
+		{
+			name: "resource",
+			add: func(t *testing.T, server *MCPServer) {
+				server.AddResource(mcp.NewResource("test://resource", "Resource"), resourceHandler)
+			},
+			listMethod: "resources/list",
+			capability: func(c mcp.ServerCapabilities) bool { return c.Resources != nil },
+		},
+		{
+			name: "resource template",
+			add: func(t *testing.T, server *MCPServer) {
+				server.AddResourceTemplate(mcp.NewResourceTemplate("test://{id}", "Template"),
+					ResourceTemplateHandlerFunc(resourceHandler))
+			},
+			listMethod: "resources/templates/list",
+			capability: func(c mcp.ServerCapabilities) bool { return c.Resources != nil },
+		},
+		{
+			name: "prompt",
+			add: func(t *testing.T, server *MCPServer) {
+				server.AddPrompt(mcp.NewPrompt("test-prompt"), promptHandler)
+			},
+			listMethod: "prompts/list",
+			capability: func(c mcp.ServerCapabilities) bool { return c.Prompts != nil },
+		},
+		{
+			name: "session tool",
+			add: func(t *testing.T, server *MCPServer) {
+				require.NoError(t, server.AddSessionTool(newToolSession(t, server), mcp.NewTool("test-tool"), toolHandler))
+			},
+			listMethod: "tools/list",
+			capability: func(c mcp.ServerCapabilities) bool { return c.Tools != nil },
+		},
+		{
+			name: "session resource",
+			add: func(t *testing.T, server *MCPServer) {
+				require.NoError(t, server.AddSessionResource(newSession(t, server),
+					mcp.NewResource("test://resource", "Resource"), resourceHandler))
+			},
+			listMethod: "resources/list",
+			capability: func(c mcp.ServerCapabilities) bool { return c.Resources != nil },
+		},
+		{
+			name: "session prompt",
+			add: func(t *testing.T, server *MCPServer) {
+				require.NoError(t, server.AddSessionPrompt(newSession(t, server), mcp.NewPrompt("test-prompt"), promptHandler))
+			},
+			listMethod: "prompts/list",
+			capability: func(c mcp.ServerCapabilities) bool { return c.Prompts != nil },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0")
+			assert.NotPanics(t, func() { tt.add(t, server) })
+
+			response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+			resp, ok := response.(mcp.JSONRPCResponse)
+			require.True(t, ok)
+			initResult, ok := resp.Result.(mcp.InitializeResult)
+			require.True(t, ok)
+			assert.True(t, tt.capability(initResult.Capabilities), "capability should be enabled by the first registration")
+
+			response = server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"`+tt.listMethod+`"}`))
+			_, ok = response.(mcp.JSONRPCResponse)
+			assert.True(t, ok, "expected %s to succeed, got %#v", tt.listMethod, response)
+		})
+	}
+}
+
+func TestMCPServer_ExperimentalCapabilities(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0",
+		WithExperimentalCapabilities(map[string]any{
+			"vendorStreaming": true,
+			"batching":        map[string]any{"maxSize": 10},
+		}),
+		WithExperimentalCapabilities(map[string]any{
+			"batching": map[string]any{"maxSize": 20},
+		}),
+	)
+
+	response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+
+	var raw struct {
+		Result struct {
+			Capabilities struct {
+				Experimental map[string]any `json:"experimental"`
+			} `json:"capabilities"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, map[string]any{
+		"vendorStreaming": true,
+		"batching":        map[string]any{"maxSize": float64(20)},
+	}, raw.Result.Capabilities.Experimental)
+
+	var result struct {
+		Result mcp.InitializeResult `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.True(t, result.Result.Capabilities.HasExperimental("vendorStreaming"))
+	assert.True(t, result.Result.Capabilities.HasExperimental("batching"))
+	assert.False(t, result.Result.Capabilities.HasExperimental("other"))
+}
+
 func TestMCPServer_Tools(t *testing.T) {
 	tests := []struct {
 		name                  string
@@ -373,6 +558,58 @@ func TestMCPServer_Tools(t *testing.T) {
 	}
 }
 
+func TestMCPServer_RegistrySnapshots(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	noopTool := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	}
+	noopResource := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, nil
+	}
+
+	assert.Empty(t, server.Tools())
+	assert.Empty(t, server.Prompts())
+	assert.Empty(t, server.Resources())
+
+	server.AddTool(mcp.NewTool("search"), noopTool)
+	server.AddTool(mcp.NewTool("fetch"), noopTool)
+	server.AddPrompt(mcp.NewPrompt("summarize"), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+	server.AddResource(mcp.NewResource("test://b", "B"), noopResource)
+	server.AddResource(mcp.NewResource("test://a", "A"), noopResource)
+
+	tools := server.Tools()
+	require.Len(t, tools, 2)
+	assert.Equal(t, "fetch", tools[0].Name)
+	assert.Equal(t, "search", tools[1].Name)
+	prompts := server.Prompts()
+	require.Len(t, prompts, 1)
+	assert.Equal(t, "summarize", prompts[0].Name)
+	resources := server.Resources()
+	require.Len(t, resources, 2)
+	assert.Equal(t, "test://a", resources[0].URI)
+	assert.Equal(t, "test://b", resources[1].URI)
+
+	server.DeleteTools("fetch")
+	server.RemoveResource("test://a")
+
+	tools = server.Tools()
+	require.Len(t, tools, 1)
+	assert.Equal(t, "search", tools[0].Name)
+	resources = server.Resources()
+	require.Len(t, resources, 1)
+	assert.Equal(t, "test://b", resources[0].URI)
+
+	// Earlier snapshots are not affected by later changes.
+	assert.Len(t, prompts, 1)
+	server.AddPrompt(mcp.NewPrompt("translate"), func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+	assert.Len(t, prompts, 1)
+	assert.Len(t, server.Prompts(), 2)
+}
+
 func TestMCPServer_HandleValidMessages(t *testing.T) {
 	server := NewMCPServer("test-server", "1.0.0",
 		WithResourceCapabilities(true, true),
@@ -499,6 +736,46 @@ func TestMCPServer_HandlePagination(t *testing.T) {
 	}
 }
 
+func TestMCPServer_ResourceTemplatePagination(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithPaginationLimit(10))
+	var expected []string
+	for i := range 25 {
+		// All templates share a name, so only their URI templates tell them apart
+		uriTemplate := fmt.Sprintf("test://items/%02d/{id}", i)
+		server.AddResourceTemplate(mcp.NewResourceTemplate(uriTemplate, "Item"),
+			func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				return nil, nil
+			})
+		expected = append(expected, uriTemplate)
+	}
+
+	var listed []string
+	var cursor mcp.Cursor
+	pages := 0
+	for {
+		message := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "resources/templates/list", "params": {"cursor": %q}}`, cursor)
+		response := server.HandleMessage(context.Background(), []byte(message))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+		result, ok := resp.Result.(mcp.ListResourceTemplatesResult)
+		require.True(t, ok)
+
+		pages++
+		require.LessOrEqual(t, len(result.ResourceTemplates), 10)
+		for _, template := range result.ResourceTemplates {
+			listed = append(listed, template.URITemplate.Raw())
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		require.Less(t, pages, 10, "pagination does not end")
+		cursor = result.NextCursor
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, expected, listed, "every template is listed once, ordered by URI template")
+}
+
 func TestMCPServer_HandleNotifications(t *testing.T) {
 	server := createTestServer()
 	notificationReceived := false
@@ -793,6 +1070,30 @@ func TestMCPServer_PromptHandling(t *testing.T) {
 	}
 }
 
+func TestMCPServer_PromptInvalidRole(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddPrompt(
+		mcp.NewPrompt("bad-role"),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult("", []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent("Hi")),
+				mcp.NewPromptMessage("system", mcp.NewTextContent("Be brief")),
+			}), nil
+		},
+	)
+
+	response := server.HandleMessage(context.Background(), []byte(`{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "prompts/get",
+		"params": {"name": "bad-role"}
+	}`))
+	errorResponse, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok, "expected error, got %#v", response)
+	assert.Equal(t, mcp.INTERNAL_ERROR, errorResponse.Error.Code)
+	assert.Contains(t, errorResponse.Error.Message, `invalid role "system"`)
+}
+
 func TestMCPServer_HandleInvalidMessages(t *testing.T) {
 	var errs []error
 	hooks := &Hooks{}
@@ -812,11 +1113,21 @@ func TestMCPServer_HandleInvalidMessages(t *testing.T) {
 			name:        "Invalid JSON",
 			message:     `{"jsonrpc": "2.0", "id": 1, "method": "initialize"`,
 			expectedErr: mcp.PARSE_ERROR,
+			validateErr: func(t *testing.T, err error) {
+				unparsableErr := &UnparsableMessageError{}
+				ok := errors.As(err, &unparsableErr)
+				assert.True(t, ok, "Error should be UnparsableMessageError")
+				assert.Equal(t, json.RawMessage(`{"jsonrpc": "2.0", "id": 1, "method": "initialize"`), unparsableErr.GetMessage())
+			},
 		},
 		{
 			name:        "Invalid method",
 			message:     `{"jsonrpc": "2.0", "id": 1, "method": "nonexistent"}`,
 			expectedErr: mcp.METHOD_NOT_FOUND,
+			validateErr: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrUnsupported)
+				assert.Contains(t, err.Error(), "nonexistent")
+			},
 		},
 		{
 			name:        "Invalid parameters",
@@ -834,6 +1145,28 @@ func TestMCPServer_HandleInvalidMessages(t *testing.T) {
 			name:        "Missing JSONRPC version",
 			message:     `{"id": 1, "method": "initialize"}`,
 			expectedErr: mcp.INVALID_REQUEST,
+			validateErr: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrUnsupported)
+			},
+		},
+		{
+			name:        "Invalid notification",
+			message:     `{"jsonrpc": "2.0", "method": "notifications/initialized", "params": "invalid"}`,
+			expectedErr: mcp.PARSE_ERROR,
+			validateErr: func(t *testing.T, err error) {
+				unparsableErr := &UnparsableMessageError{}
+				ok := errors.As(err, &unparsableErr)
+				assert.True(t, ok, "Error should be UnparsableMessageError")
+				assert.Equal(t, mcp.MCPMethod("notifications/initialized"), unparsableErr.GetMethod())
+			},
+		},
+		{
+			name:        "Capability not enabled",
+			message:     `{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`,
+			expectedErr: mcp.METHOD_NOT_FOUND,
+			validateErr: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, ErrUnsupported)
+			},
 		},
 	}
 
@@ -859,6 +1192,57 @@ func TestMCPServer_HandleInvalidMessages(t *testing.T) {
 	}
 }
 
+func TestMCPServer_LenientJSONRPCVersion(t *testing.T) {
+	strict := NewMCPServer("test-server", "1.0.0")
+	lenient := NewMCPServer("test-server", "1.0.0", WithLenientJSONRPCVersion())
+
+	tests := []struct {
+		name      string
+		message   string
+		strictOK  bool
+		lenientOK bool
+	}{
+		{name: "version 2.0", message: `{"jsonrpc": "2.0", "id": 1, "method": "ping"}`, strictOK: true, lenientOK: true},
+		{name: "missing version", message: `{"id": 1, "method": "ping"}`, strictOK: false, lenientOK: true},
+		{name: "null version", message: `{"jsonrpc": null, "id": 1, "method": "ping"}`, strictOK: false, lenientOK: true},
+		{name: "wrong version", message: `{"jsonrpc": "1.0", "id": 1, "method": "ping"}`, strictOK: false, lenientOK: false},
+		{name: "empty version", message: `{"jsonrpc": "", "id": 1, "method": "ping"}`, strictOK: false, lenientOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, server := range []struct {
+				name string
+				srv  *MCPServer
+				ok   bool
+			}{{"strict", strict, tt.strictOK}, {"lenient", lenient, tt.lenientOK}} {
+				response := server.srv.HandleMessage(context.Background(), []byte(tt.message))
+				if server.ok {
+					resp, ok := response.(mcp.JSONRPCResponse)
+					require.True(t, ok, server.name)
+					assert.Equal(t, mcp.JSONRPC_VERSION, resp.JSONRPC, server.name)
+				} else {
+					errResp, ok := response.(mcp.JSONRPCError)
+					require.True(t, ok, server.name)
+					assert.Equal(t, mcp.INVALID_REQUEST, errResp.Error.Code, server.name)
+				}
+			}
+		})
+	}
+
+	// Notifications without a version are accepted too
+	called := make(chan struct{}, 1)
+	lenient.AddNotificationHandler("notifications/test", func(ctx context.Context, notification mcp.JSONRPCNotification) {
+		called <- struct{}{}
+	})
+	assert.Nil(t, lenient.HandleMessage(context.Background(), []byte(`{"method": "notifications/test"}`)))
+	select {
+	case <-called:
+	default:
+		t.Error("notification handler was not called")
+	}
+}
+
 func TestMCPServer_HandleUndefinedHandlers(t *testing.T) {
 	var errs []error
 	type beforeResult struct {
@@ -1141,6 +1525,89 @@ func TestMCPServer_Instructions(t *testing.T) {
 	}
 }
 
+func TestNewMCPServerWithAllCapabilities(t *testing.T) {
+	initialize := func(server *MCPServer) mcp.InitializeResult {
+		response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc": "2.0", "id": 1, "method": "initialize"}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		initResult, ok := resp.Result.(mcp.InitializeResult)
+		require.True(t, ok)
+		return initResult
+	}
+
+	explicit := NewMCPServer("test-server", "1.0.0",
+		WithResourceCapabilities(true, true),
+		WithPromptCapabilities(true),
+		WithToolCapabilities(true),
+		WithLogging(),
+		WithInstructions("Use the tools."),
+	)
+	preset := NewMCPServerWithAllCapabilities("test-server", "1.0.0", WithInstructions("Use the tools."))
+	assert.Equal(t, initialize(explicit), initialize(preset))
+
+	// Extra options override the preset
+	overridden := initialize(NewMCPServerWithAllCapabilities("test-server", "1.0.0", WithToolCapabilities(false)))
+	require.NotNil(t, overridden.Capabilities.Tools)
+	assert.False(t, overridden.Capabilities.Tools.ListChanged)
+}
+
+func TestMCPServer_InstructionsFunc(t *testing.T) {
+	type tenantKey struct{}
+
+	server := NewMCPServer("test-server", "1.0.0",
+		WithInstructions("Static instructions."),
+		WithInstructionsFunc(func(ctx context.Context, request mcp.InitializeRequest) string {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			return fmt.Sprintf("Instructions for %s using %s.", tenant, request.Params.ClientInfo.Name)
+		}),
+	)
+
+	for _, tenant := range []string{"acme", "globex"} {
+		ctx := context.WithValue(context.Background(), tenantKey{}, tenant)
+		response := server.HandleMessage(ctx, []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "initialize",
+			"params": {"protocolVersion": "2025-03-26", "clientInfo": {"name": "test-client", "version": "1.0.0"}}
+		}`))
+
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		initResult, ok := resp.Result.(mcp.InitializeResult)
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprintf("Instructions for %s using test-client.", tenant), initResult.Instructions)
+	}
+}
+
+func TestMCPServer_InstructionsFuncMayRegisterTools(t *testing.T) {
+	var server *MCPServer
+	server = NewMCPServer("test-server", "1.0.0",
+		WithInstructionsFunc(func(ctx context.Context, request mcp.InitializeRequest) string {
+			server.AddTool(mcp.NewTool("late"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText("ok"), nil
+			})
+			return "Use the late tool."
+		}),
+	)
+
+	done := make(chan mcp.JSONRPCMessage, 1)
+	go func() {
+		done <- server.HandleMessage(context.Background(), []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "initialize",
+			"params": {"protocolVersion": "2025-03-26", "clientInfo": {"name": "test-client", "version": "1.0.0"}}
+		}`))
+	}()
+	select {
+	case response := <-done:
+		assert.IsType(t, mcp.JSONRPCResponse{}, response)
+	case <-time.After(time.Second):
+		t.Fatal("initialize deadlocked registering a tool from the instructions func")
+	}
+	assert.Len(t, server.Tools(), 1)
+}
+
 func TestMCPServer_ResourceTemplates(t *testing.T) {
 	server := NewMCPServer("test-server", "1.0.0",
 		WithResourceCapabilities(true, true),
@@ -1292,6 +1759,247 @@ func (f fakeSession) Initialized() bool {
 
 var _ ClientSession = fakeSession{}
 
+func TestMCPServer_RequestStartFromContext(t *testing.T) {
+	const handlerDelay = 50 * time.Millisecond
+
+	successElapsed, errorElapsed := time.Duration(-1), time.Duration(-1)
+	hooks := &Hooks{}
+	hooks.AddOnSuccess(func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any) {
+		start, ok := RequestStartFromContext(ctx)
+		require.True(t, ok)
+		successElapsed = time.Since(start)
+	})
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		start, ok := RequestStartFromContext(ctx)
+		require.True(t, ok)
+		errorElapsed = time.Since(start)
+	})
+
+	server := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+	server.AddTool(mcp.NewTool("slow"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		time.Sleep(handlerDelay)
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow"}}`))
+	assert.GreaterOrEqual(t, successElapsed, handlerDelay)
+	assert.Less(t, successElapsed, handlerDelay+time.Second)
+
+	server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"missing"}}`))
+	assert.GreaterOrEqual(t, errorElapsed, time.Duration(0))
+	assert.Less(t, errorElapsed, handlerDelay)
+
+	_, ok := RequestStartFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func TestMCPServer_RequestMetadataFromContext(t *testing.T) {
+	var toolMethod, notificationMethod mcp.MCPMethod
+	var toolID mcp.RequestId
+	var toolHasID, notificationHasID bool
+
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddTool(mcp.NewTool("whoami"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		toolMethod, _ = MethodFromContext(ctx)
+		toolID, toolHasID = RequestIDFromContext(ctx)
+		return mcp.NewToolResultText("done"), nil
+	})
+	server.AddNotificationHandler("notifications/custom", func(ctx context.Context, notification mcp.JSONRPCNotification) {
+		notificationMethod, _ = MethodFromContext(ctx)
+		_, notificationHasID = RequestIDFromContext(ctx)
+	})
+
+	server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":"req-7","method":"tools/call","params":{"name":"whoami"}}`))
+	assert.Equal(t, mcp.MethodToolsCall, toolMethod)
+	assert.True(t, toolHasID)
+	assert.Equal(t, "req-7", toolID)
+
+	server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"whoami"}}`))
+	assert.Equal(t, float64(8), toolID)
+
+	server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/custom"}`))
+	assert.Equal(t, mcp.MCPMethod("notifications/custom"), notificationMethod)
+	assert.False(t, notificationHasID)
+
+	_, ok := MethodFromContext(context.Background())
+	assert.False(t, ok)
+	_, ok = RequestIDFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func TestMCPServer_RequestIDTypes(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+	encode := func(message mcp.JSONRPCMessage) string {
+		t.Helper()
+		data, err := json.Marshal(message)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		wantID string
+	}{
+		{name: "string", id: `"abc"`, wantID: `"id":"abc"`},
+		{name: "numeric string", id: `"1"`, wantID: `"id":"1"`},
+		{name: "integer", id: `42`, wantID: `"id":42`},
+		{name: "integer beyond float64 precision", id: `9007199254740993`, wantID: `"id":9007199254740993`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			success := server.HandleMessage(context.Background(), []byte(
+				`{"jsonrpc":"2.0","id":`+tt.id+`,"method":"ping"}`))
+			require.IsType(t, mcp.JSONRPCResponse{}, success)
+			assert.Contains(t, encode(success), tt.wantID)
+
+			failure := server.HandleMessage(context.Background(), []byte(
+				`{"jsonrpc":"2.0","id":`+tt.id+`,"method":"unknown/method"}`))
+			require.IsType(t, mcp.JSONRPCError{}, failure)
+			assert.Contains(t, encode(failure), tt.wantID)
+		})
+	}
+
+	t.Run("string id is not coerced", func(t *testing.T) {
+		response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":"7","method":"ping"}`))
+		assert.Equal(t, "7", response.(mcp.JSONRPCResponse).ID)
+	})
+
+	t.Run("null id when the message cannot be parsed", func(t *testing.T) {
+		response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":"abc",`))
+		require.IsType(t, mcp.JSONRPCError{}, response)
+		assert.Contains(t, encode(response), `"id":null`)
+	})
+
+	t.Run("null id for ids that are not strings or numbers", func(t *testing.T) {
+		for _, id := range []string{`true`, `{"n":1}`, `[1]`} {
+			response := server.HandleMessage(context.Background(), []byte(
+				`{"jsonrpc":"2.0","id":`+id+`,"method":"ping"}`))
+			errorResponse, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok, "id %s", id)
+			assert.Equal(t, mcp.INVALID_REQUEST, errorResponse.Error.Code)
+			assert.Contains(t, encode(response), `"id":null`)
+		}
+	})
+}
+
+func TestMCPServer_HookOrder(t *testing.T) {
+	var calls []string
+	hooks := &Hooks{}
+	hooks.AddOnRequestInitialization(func(ctx context.Context, id any, message any) error {
+		calls = append(calls, "onRequestInitialization")
+		return nil
+	})
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		calls = append(calls, "beforeAny")
+	})
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest) {
+		calls = append(calls, "beforeCallTool")
+	})
+	hooks.AddAfterCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult) {
+		calls = append(calls, "afterCallTool")
+	})
+	hooks.AddOnSuccess(func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any) {
+		calls = append(calls, "onSuccess")
+	})
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		calls = append(calls, "onError")
+	})
+
+	server := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+	server.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls = append(calls, "handler")
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo"}}`))
+	assert.Equal(t, []string{
+		"onRequestInitialization",
+		"beforeAny",
+		"beforeCallTool",
+		"handler",
+		"onSuccess",
+		"afterCallTool",
+	}, calls)
+
+	// Failing requests skip the after hooks and report to onError instead
+	calls = nil
+	server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"missing"}}`))
+	assert.Equal(t, []string{
+		"onRequestInitialization",
+		"beforeAny",
+		"beforeCallTool",
+		"onError",
+	}, calls)
+}
+
+func TestMCPServer_OnRequestInitializationRejects(t *testing.T) {
+	errQuotaExceeded := errors.New("quota exceeded")
+	errNoToken := errors.New("missing token")
+
+	tests := []struct {
+		name         string
+		hookErr      error
+		expectedCode int
+	}{
+		{
+			name:         "Plain error",
+			hookErr:      errQuotaExceeded,
+			expectedCode: mcp.INVALID_REQUEST,
+		},
+		{
+			name:         "Custom code",
+			hookErr:      &RequestRejectedError{Code: mcp.UNAUTHORIZED, Err: errNoToken},
+			expectedCode: mcp.UNAUTHORIZED,
+		},
+		{
+			name:         "Custom code without error",
+			hookErr:      &RequestRejectedError{Code: mcp.UNAUTHORIZED},
+			expectedCode: mcp.UNAUTHORIZED,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handlerCalled, beforeAnyCalled, laterHookCalled bool
+			var hookErrs []error
+
+			hooks := &Hooks{}
+			hooks.AddOnRequestInitialization(func(ctx context.Context, id any, message any) error {
+				return tt.hookErr
+			})
+			hooks.AddOnRequestInitialization(func(ctx context.Context, id any, message any) error {
+				laterHookCalled = true
+				return nil
+			})
+			hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+				beforeAnyCalled = true
+			})
+			hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+				hookErrs = append(hookErrs, err)
+			})
+
+			server := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+			server.AddTool(mcp.NewTool("guarded"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				handlerCalled = true
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"guarded"}}`))
+			errResp, ok := response.(mcp.JSONRPCError)
+			require.True(t, ok)
+			assert.Equal(t, tt.expectedCode, errResp.Error.Code)
+			assert.Equal(t, tt.hookErr.Error(), errResp.Error.Message)
+
+			assert.False(t, handlerCalled, "handler should not run for a rejected request")
+			assert.False(t, beforeAnyCalled, "method hooks should not run for a rejected request")
+			assert.False(t, laterHookCalled, "later hooks should not run for a rejected request")
+			require.Len(t, hookErrs, 1)
+			assert.ErrorIs(t, hookErrs[0], tt.hookErr)
+		})
+	}
+}
+
 func TestMCPServer_WithHooks(t *testing.T) {
 	// Create hook counters to verify calls
 	var (
@@ -1457,6 +2165,49 @@ func TestMCPServer_WithHooks(t *testing.T) {
 	assert.IsType(t, afterPingData[0].res, onSuccessData[0].res, "OnSuccess result should be same type as AfterPing result")
 }
 
+func TestMCPServer_ProtocolVersionMismatch(t *testing.T) {
+	type mismatch struct {
+		requested, supported string
+	}
+	var mismatches []mismatch
+	var server *MCPServer
+	hooks := &Hooks{}
+	hooks.AddOnProtocolVersionMismatch(func(ctx context.Context, id any, requested, supported string) {
+		mismatches = append(mismatches, mismatch{requested, supported})
+		// Hooks may register, e.g. a tool explaining the mismatch
+		server.AddTool(mcp.NewTool("upgrade-help"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("upgrade your client"), nil
+		})
+	})
+	server = NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+
+	initialize := func(version string) string {
+		message := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": %q}}`, version)
+		done := make(chan mcp.JSONRPCMessage, 1)
+		go func() { done <- server.HandleMessage(context.Background(), []byte(message)) }()
+		var response mcp.JSONRPCMessage
+		select {
+		case response = <-done:
+		case <-time.After(time.Second):
+			t.Fatal("initialize deadlocked")
+		}
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+		return resp.Result.(mcp.InitializeResult).ProtocolVersion
+	}
+
+	assert.Equal(t, mcp.LATEST_PROTOCOL_VERSION, initialize(mcp.LATEST_PROTOCOL_VERSION))
+	assert.Empty(t, mismatches, "supported versions are not reported")
+
+	assert.Equal(t, mcp.LATEST_PROTOCOL_VERSION, initialize("2099-01-01"), "unknown versions are answered with the latest")
+	assert.Equal(t, []mismatch{{"2099-01-01", mcp.LATEST_PROTOCOL_VERSION}}, mismatches)
+	assert.Len(t, server.Tools(), 1)
+
+	versions := mcp.ValidProtocolVersions()
+	versions[0] = "2099-01-01"
+	assert.Equal(t, mcp.LATEST_PROTOCOL_VERSION, mcp.ValidProtocolVersions()[0], "the supported versions cannot be changed")
+}
+
 func TestMCPServer_SessionHooks(t *testing.T) {
 	var (
 		registerCalled   bool
@@ -1527,6 +2278,50 @@ func TestMCPServer_SessionHooks_NilHooks(t *testing.T) {
 	server.UnregisterSession(ctx, testSession.SessionID())
 }
 
+func TestMCPServer_HooksRemoval(t *testing.T) {
+	var calls []string
+
+	hooks := &Hooks{}
+	hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+		calls = append(calls, "beforeAny")
+	})
+	hooks.AddBeforePing(func(ctx context.Context, id any, message *mcp.PingRequest) {
+		calls = append(calls, "beforePing1")
+	})
+	hooks.AddBeforePing(func(ctx context.Context, id any, message *mcp.PingRequest) {
+		calls = append(calls, "beforePing2")
+	})
+	hooks.AddAfterPing(func(ctx context.Context, id any, message *mcp.PingRequest, result *mcp.EmptyResult) {
+		calls = append(calls, "afterPing")
+	})
+
+	server := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+	ping := []byte(`{"jsonrpc": "2.0", "id": 1, "method": "ping"}`)
+
+	server.HandleMessage(context.Background(), ping)
+	assert.Equal(t, []string{"beforeAny", "beforePing1", "beforePing2", "afterPing"}, calls,
+		"hooks should run in registration order")
+
+	// Clearing one kind leaves the others in place
+	calls = nil
+	hooks.ClearBeforePing()
+	server.HandleMessage(context.Background(), ping)
+	assert.Equal(t, []string{"beforeAny", "afterPing"}, calls)
+
+	// Removing all hooks stops every hook from firing
+	calls = nil
+	hooks.RemoveAll()
+	server.HandleMessage(context.Background(), ping)
+	assert.Empty(t, calls)
+
+	// Hooks can be registered again after removal
+	hooks.AddAfterPing(func(ctx context.Context, id any, message *mcp.PingRequest, result *mcp.EmptyResult) {
+		calls = append(calls, "afterPing")
+	})
+	server.HandleMessage(context.Background(), ping)
+	assert.Equal(t, []string{"afterPing"}, calls)
+}
+
 func TestMCPServer_WithRecover(t *testing.T) {
 	panicToolHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		panic("test panic")
@@ -1560,6 +2355,382 @@ func TestMCPServer_WithRecover(t *testing.T) {
 	assert.Nil(t, errorResponse.Error.Data)
 }
 
+func TestMCPServer_CancellationAsToolError(t *testing.T) {
+	server := NewMCPServer(
+		"test-server",
+		"1.0.0",
+		WithCancellationAsToolError(),
+	)
+
+	server.AddTool(mcp.NewTool("cancelled-tool"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, fmt.Errorf("waiting for upstream: %w", context.Canceled)
+	})
+	server.AddTool(mcp.NewTool("slow-tool"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	server.AddTool(mcp.NewTool("failing-tool"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("nothing processed")
+	})
+
+	call := func(name string) mcp.JSONRPCMessage {
+		return server.HandleMessage(context.Background(), []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {"name": "`+name+`"}
+		}`))
+	}
+
+	for name, message := range map[string]string{
+		"cancelled-tool": "tool cancelled-tool cancelled",
+		"slow-tool":      "tool slow-tool timed out",
+	} {
+		response, ok := call(name).(mcp.JSONRPCResponse)
+		require.True(t, ok, name)
+		result, ok := response.Result.(mcp.CallToolResult)
+		require.True(t, ok, name)
+		assert.True(t, result.IsError, name)
+		require.Len(t, result.Content, 1, name)
+		assert.Equal(t, message, result.Content[0].(mcp.TextContent).Text)
+	}
+
+	errorResponse, ok := call("failing-tool").(mcp.JSONRPCError)
+	require.True(t, ok)
+	assert.Equal(t, mcp.INTERNAL_ERROR, errorResponse.Error.Code)
+}
+
+func TestMCPServer_ToolCallPartialResult(t *testing.T) {
+	var hookErrs []error
+	hooks := &Hooks{}
+	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+		hookErrs = append(hookErrs, err)
+	})
+	server := NewMCPServer("test-server", "1.0.0", WithHooks(hooks))
+
+	errItem := errors.New("item 4 failed")
+	partialResult := mcp.NewToolResultText("processed 3 of 5 items")
+	server.AddTool(
+		mcp.NewTool("partial-tool"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return partialResult, errItem
+		},
+	)
+	server.AddTool(
+		mcp.NewTool("failing-tool"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return nil, errors.New("nothing processed")
+		},
+	)
+
+	response := server.HandleMessage(context.Background(), []byte(`{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "tools/call",
+		"params": {
+			"name": "partial-tool"
+		}
+	}`))
+
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok, "partial result should be sent as a successful response")
+	result, ok := resp.Result.(mcp.CallToolResult)
+	require.True(t, ok)
+	assert.True(t, result.IsError)
+	require.Len(t, result.Content, 2)
+	textContent, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Equal(t, "processed 3 of 5 items", textContent.Text)
+	errorContent, ok := result.Content[1].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Equal(t, "item 4 failed", errorContent.Text, "the error is appended to the result")
+	require.Len(t, hookErrs, 1)
+	assert.ErrorIs(t, hookErrs[0], errItem)
+	assert.Len(t, partialResult.Content, 1, "the handler's result is left unchanged")
+
+	// Without a result the error is still sent as a JSON-RPC error
+	response = server.HandleMessage(context.Background(), []byte(`{
+		"jsonrpc": "2.0",
+		"id": 2,
+		"method": "tools/call",
+		"params": {
+			"name": "failing-tool"
+		}
+	}`))
+
+	errorResponse, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok)
+	assert.Equal(t, mcp.INTERNAL_ERROR, errorResponse.Error.Code)
+	assert.Equal(t, "nothing processed", errorResponse.Error.Message)
+}
+
+func TestMCPServer_ToolRequiredScopes(t *testing.T) {
+	type scopesKey struct{}
+	checker := func(ctx context.Context, scopes []string) error {
+		granted, _ := ctx.Value(scopesKey{}).([]string)
+		for _, scope := range scopes {
+			if !slices.Contains(granted, scope) {
+				return fmt.Errorf("missing scope %q", scope)
+			}
+		}
+		return nil
+	}
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	callTool := func(server *MCPServer, ctx context.Context) mcp.JSONRPCMessage {
+		return server.HandleMessage(ctx, []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {
+				"name": "admin-tool"
+			}
+		}`))
+	}
+
+	server := NewMCPServer("test-server", "1.0.0", WithScopeChecker(checker))
+	server.AddTools(ServerTool{
+		Tool:           mcp.NewTool("admin-tool"),
+		Handler:        handler,
+		RequiredScopes: []string{"admin", "write"},
+	})
+
+	ctx := context.WithValue(context.Background(), scopesKey{}, []string{"admin", "write", "read"})
+	_, ok := callTool(server, ctx).(mcp.JSONRPCResponse)
+	assert.True(t, ok, "caller with all scopes should be allowed")
+
+	ctx = context.WithValue(context.Background(), scopesKey{}, []string{"admin"})
+	errorResponse, ok := callTool(server, ctx).(mcp.JSONRPCError)
+	require.True(t, ok, "caller missing a scope should be rejected")
+	assert.Equal(t, mcp.UNAUTHORIZED, errorResponse.Error.Code)
+	assert.Contains(t, errorResponse.Error.Message, `missing scope "write"`)
+
+	// Without a scope checker, tools requiring scopes cannot be called
+	server = NewMCPServer("test-server", "1.0.0")
+	server.AddTools(ServerTool{
+		Tool:           mcp.NewTool("admin-tool"),
+		Handler:        handler,
+		RequiredScopes: []string{"admin"},
+	})
+	errorResponse, ok = callTool(server, context.Background()).(mcp.JSONRPCError)
+	require.True(t, ok)
+	assert.Equal(t, mcp.UNAUTHORIZED, errorResponse.Error.Code)
+}
+
+func TestMCPServer_ToolAuthorizer(t *testing.T) {
+	type tokenKey struct{}
+	authorizer := func(ctx context.Context, tool mcp.Tool, request mcp.CallToolRequest) error {
+		if hint := tool.Annotations.DestructiveHint; hint != nil && !*hint {
+			return nil
+		}
+		if token, _ := ctx.Value(tokenKey{}).(string); token == "" {
+			return errors.New("authentication required")
+		}
+		return nil
+	}
+	var called []string
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = append(called, request.Params.Name)
+		return mcp.NewToolResultText("ok"), nil
+	}
+	server := NewMCPServer("test-server", "1.0.0", WithToolAuthorizer(authorizer))
+	callTool := func(ctx context.Context, name string) mcp.JSONRPCMessage {
+		return server.HandleMessage(ctx, []byte(fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {
+				"name": %q
+			}
+		}`, name)))
+	}
+
+	server.AddTool(mcp.NewTool("delete-repo", mcp.WithDestructiveHintAnnotation(true)), handler)
+	server.AddTool(mcp.NewTool("list-repos", mcp.WithDestructiveHintAnnotation(false)), handler)
+
+	errorResponse, ok := callTool(context.Background(), "delete-repo").(mcp.JSONRPCError)
+	require.True(t, ok, "unauthenticated caller should not reach a destructive tool")
+	assert.Equal(t, mcp.FORBIDDEN, errorResponse.Error.Code)
+	assert.Contains(t, errorResponse.Error.Message, "authentication required")
+	assert.Empty(t, called)
+
+	_, ok = callTool(context.Background(), "list-repos").(mcp.JSONRPCResponse)
+	assert.True(t, ok, "read-only tool should be allowed without a token")
+
+	ctx := context.WithValue(context.Background(), tokenKey{}, "secret")
+	_, ok = callTool(ctx, "delete-repo").(mcp.JSONRPCResponse)
+	assert.True(t, ok, "authenticated caller should be allowed")
+	assert.Equal(t, []string{"list-repos", "delete-repo"}, called)
+}
+
+func TestMCPServer_CompletionCapability(t *testing.T) {
+	completions := func(server *MCPServer) *struct{} {
+		response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc": "2.0", "id": 1, "method": "initialize"}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		return resp.Result.(mcp.InitializeResult).Capabilities.Completions
+	}
+	readResource := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, nil
+	}
+
+	server := NewMCPServer("test-server", "1.0.0")
+	server.AddResourceTemplate(mcp.NewResourceTemplate("plain://{name}", "Plain"), readResource)
+	assert.Nil(t, completions(server), "not advertised without completions")
+
+	server.SetCompletionHandler(func(ctx context.Context, request mcp.CompleteRequest) (*mcp.Completion, error) {
+		return &mcp.Completion{Values: []string{request.Params.Argument.Value + "-completed"}}, nil
+	})
+	assert.NotNil(t, completions(server), "advertised after SetCompletionHandler")
+
+	// The handler completes prompt arguments and templates without a completion function
+	for _, ref := range []string{
+		`{"type": "ref/prompt", "name": "greeting"}`,
+		`{"type": "ref/resource", "uri": "plain://{name}"}`,
+	} {
+		response := server.HandleMessage(context.Background(), []byte(`{
+			"jsonrpc": "2.0",
+			"id": 2,
+			"method": "completion/complete",
+			"params": {"ref": `+ref+`, "argument": {"name": "name", "value": "al"}}
+		}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success for %s, got %#v", ref, response)
+		assert.Equal(t, []string{"al-completed"}, resp.Result.(mcp.CompleteResult).Completion.Values, ref)
+	}
+
+	server = NewMCPServer("test-server", "1.0.0")
+	server.AddResourceTemplateWithCompletion(mcp.NewResourceTemplate("repo://{name}", "Repository"), readResource,
+		func(ctx context.Context, argument, value string) ([]string, error) {
+			return nil, nil
+		})
+	assert.NotNil(t, completions(server), "advertised with a template completion function")
+
+	assert.NotNil(t, completions(NewMCPServer("test-server", "1.0.0", WithCompletions())))
+}
+
+func TestMCPServer_ResourceTemplateCompletion(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+
+	repos := []string{"mcp-go", "mcp-server", "other"}
+	server.AddResourceTemplateWithCompletion(
+		mcp.NewResourceTemplate("repo://{owner}/{repo}", "Repository"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, nil
+		},
+		func(ctx context.Context, argument, value string) ([]string, error) {
+			if argument != "repo" {
+				return nil, nil
+			}
+			var values []string
+			for _, repo := range repos {
+				if strings.HasPrefix(repo, value) {
+					values = append(values, repo)
+				}
+			}
+			return values, nil
+		},
+	)
+	server.AddResourceTemplate(
+		mcp.NewResourceTemplate("plain://{name}", "Plain"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return nil, nil
+		},
+	)
+
+	complete := func(uri, argument, value string) mcp.JSONRPCMessage {
+		return server.HandleMessage(context.Background(), []byte(fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "completion/complete",
+			"params": {
+				"ref": {"type": "ref/resource", "uri": %q},
+				"argument": {"name": %q, "value": %q}
+			}
+		}`, uri, argument, value)))
+	}
+
+	resp, ok := complete("repo://{owner}/{repo}", "repo", "mcp").(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	result, ok := resp.Result.(mcp.CompleteResult)
+	require.True(t, ok)
+	assert.Equal(t, []string{"mcp-go", "mcp-server"}, result.Completion.Values)
+
+	// Templates without a completion func return no values
+	resp, ok = complete("plain://{name}", "name", "x").(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	result, ok = resp.Result.(mcp.CompleteResult)
+	require.True(t, ok)
+	assert.Empty(t, result.Completion.Values)
+
+	errResp, ok := complete("repo://{owner}/{repo}", "branch", "").(mcp.JSONRPCError)
+	require.True(t, ok, "unknown template variables should be rejected")
+	assert.Equal(t, mcp.INVALID_PARAMS, errResp.Error.Code)
+
+	errResp, ok = complete("missing://{x}", "x", "").(mcp.JSONRPCError)
+	require.True(t, ok, "unknown templates should be rejected")
+	assert.Equal(t, mcp.INVALID_PARAMS, errResp.Error.Code)
+}
+
+func TestMCPServer_ResourceTemplateCompletionResult(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+
+	readHandler := func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return nil, nil
+	}
+	numbers := func(n int) []string {
+		values := make([]string, n)
+		for i := range values {
+			values[i] = strconv.Itoa(i)
+		}
+		return values
+	}
+	server.AddResourceTemplateWithCompletionResult(
+		mcp.NewResourceTemplate("paged://{id}", "Paged"),
+		readHandler,
+		func(ctx context.Context, argument, value string) (*mcp.Completion, error) {
+			return &mcp.Completion{Values: numbers(100), Total: 500, HasMore: true}, nil
+		},
+	)
+	server.AddResourceTemplateWithCompletion(
+		mcp.NewResourceTemplate("large://{id}", "Large"),
+		readHandler,
+		func(ctx context.Context, argument, value string) ([]string, error) {
+			return numbers(250), nil
+		},
+	)
+
+	complete := func(uri string) mcp.CompleteResult {
+		resp, ok := server.HandleMessage(context.Background(), []byte(fmt.Sprintf(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "completion/complete",
+			"params": {
+				"ref": {"type": "ref/resource", "uri": %q},
+				"argument": {"name": "id", "value": ""}
+			}
+		}`, uri))).(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		result, ok := resp.Result.(mcp.CompleteResult)
+		require.True(t, ok)
+		return result
+	}
+
+	result := complete("paged://{id}")
+	assert.Len(t, result.Completion.Values, 100)
+	assert.Equal(t, 500, result.Completion.Total)
+	assert.True(t, result.Completion.HasMore)
+
+	// Values beyond the limit of 100 are cut off and counted in the total
+	result = complete("large://{id}")
+	assert.Len(t, result.Completion.Values, 100)
+	assert.Equal(t, 250, result.Completion.Total)
+	assert.True(t, result.Completion.HasMore)
+}
+
 func getTools(length int) []mcp.Tool {
 	list := make([]mcp.Tool, 0, 10000)
 	for i := 0; i < length; i++ {
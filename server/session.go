@@ -30,6 +30,30 @@ type SessionWithTools interface {
 	SetSessionTools(tools map[string]ServerTool)
 }
 
+// SessionWithResources is an extension of ClientSession that can store
+// session-specific resource data
+type SessionWithResources interface {
+	ClientSession
+	// GetSessionResources returns the resources specific to this session, keyed by URI
+	// This method must be thread-safe for concurrent access
+	GetSessionResources() map[string]ServerResource
+	// SetSessionResources sets resources specific to this session
+	// This method must be thread-safe for concurrent access
+	SetSessionResources(resources map[string]ServerResource)
+}
+
+// SessionWithPrompts is an extension of ClientSession that can store
+// session-specific prompt data
+type SessionWithPrompts interface {
+	ClientSession
+	// GetSessionPrompts returns the prompts specific to this session, keyed by name
+	// This method must be thread-safe for concurrent access
+	GetSessionPrompts() map[string]ServerPrompt
+	// SetSessionPrompts sets prompts specific to this session
+	// This method must be thread-safe for concurrent access
+	SetSessionPrompts(prompts map[string]ServerPrompt)
+}
+
 // clientSessionKey is the context key for storing current client notification channel.
 type clientSessionKey struct{}
 
@@ -41,12 +65,39 @@ func ClientSessionFromContext(ctx context.Context) ClientSession {
 	return nil
 }
 
+// sessionClosedKey is the context key for the channel closed when the
+// current session is unregistered.
+type sessionClosedKey struct{}
+
 // WithContext sets the current client session and returns the provided context
 func (s *MCPServer) WithContext(
 	ctx context.Context,
 	session ClientSession,
 ) context.Context {
-	return context.WithValue(ctx, clientSessionKey{}, session)
+	ctx = context.WithValue(ctx, clientSessionKey{}, session)
+	if closed, ok := s.sessionClosed.Load(session.SessionID()); ok {
+		ctx = context.WithValue(ctx, sessionClosedKey{}, closed)
+	}
+	return ctx
+}
+
+// sessionClosedFromContext returns the channel closed when the session in
+// ctx is unregistered, or nil if the session was not registered when the
+// context was created.
+func sessionClosedFromContext(ctx context.Context) <-chan struct{} {
+	closed, _ := ctx.Value(sessionClosedKey{}).(chan struct{})
+	return closed
+}
+
+// sessionUnregistered reports whether the session in ctx has been
+// unregistered since the context was created.
+func sessionUnregistered(ctx context.Context) bool {
+	select {
+	case <-sessionClosedFromContext(ctx):
+		return true
+	default:
+		return false
+	}
 }
 
 // RegisterSession saves session that should be notified in case if some server attributes changed.
@@ -58,6 +109,8 @@ func (s *MCPServer) RegisterSession(
 	if _, exists := s.sessions.LoadOrStore(sessionID, session); exists {
 		return ErrSessionExists
 	}
+	s.sessionStates.Store(sessionID, &SessionState{})
+	s.sessionClosed.Store(sessionID, make(chan struct{}))
 	s.hooks.RegisterSession(ctx, session)
 	return nil
 }
@@ -71,6 +124,13 @@ func (s *MCPServer) UnregisterSession(
 	if !ok {
 		return
 	}
+	// Discarding the state also drops the session's resource subscriptions
+	s.sessionStates.Delete(sessionID)
+	// Notifications sent on behalf of the session after this point, e.g.
+	// by handlers still running, fail with ErrSessionClosed.
+	if closed, ok := s.sessionClosed.LoadAndDelete(sessionID); ok {
+		close(closed.(chan struct{}))
+	}
 	if session, ok := sessionValue.(ClientSession); ok {
 		s.hooks.UnregisterSession(ctx, session)
 	}
@@ -117,7 +177,9 @@ func (s *MCPServer) SendNotificationToAllClients(
 	})
 }
 
-// SendNotificationToClient sends a notification to the current client
+// SendNotificationToClient sends a notification to the current client.
+// ErrSessionClosed is returned if the session has been unregistered since
+// ctx was created.
 func (s *MCPServer) SendNotificationToClient(
 	ctx context.Context,
 	method string,
@@ -127,6 +189,9 @@ func (s *MCPServer) SendNotificationToClient(
 	if session == nil || !session.Initialized() {
 		return ErrNotificationNotInitialized
 	}
+	if sessionUnregistered(ctx) {
+		return ErrSessionClosed
+	}
 
 	notification := mcp.JSONRPCNotification{
 		JSONRPC: mcp.JSONRPC_VERSION,
@@ -159,6 +224,58 @@ func (s *MCPServer) SendNotificationToClient(
 	}
 }
 
+// SendNotificationToClientWait sends a notification to the current client
+// like SendNotificationToClient, but if the notification channel is full it
+// waits for buffer space until ctx is done instead of failing immediately.
+// The returned error then wraps both ErrNotificationChannelBlocked and the
+// context error. If the session is unregistered while waiting,
+// ErrSessionClosed is returned.
+func (s *MCPServer) SendNotificationToClientWait(
+	ctx context.Context,
+	method string,
+	params map[string]any,
+) error {
+	session := ClientSessionFromContext(ctx)
+	if session == nil || !session.Initialized() {
+		return ErrNotificationNotInitialized
+	}
+	if sessionUnregistered(ctx) {
+		return ErrSessionClosed
+	}
+
+	notification := mcp.JSONRPCNotification{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		Notification: mcp.Notification{
+			Method: method,
+			Params: mcp.NotificationParams{
+				AdditionalFields: params,
+			},
+		},
+	}
+
+	select {
+	case session.NotificationChannel() <- notification:
+		return nil
+	case <-sessionClosedFromContext(ctx):
+		return ErrSessionClosed
+	case <-ctx.Done():
+		err := fmt.Errorf("%w: %w", ErrNotificationChannelBlocked, ctx.Err())
+		// Channel stayed blocked, if there's an error hook, use it
+		if s.hooks != nil && len(s.hooks.OnError) > 0 {
+			// Copy hooks pointer to local variable to avoid race condition
+			hooks := s.hooks
+			go func(sessionID string, hooks *Hooks) {
+				// Use the error hook to report the blocked channel
+				hooks.onError(ctx, nil, "notification", map[string]any{
+					"method":    method,
+					"sessionID": sessionID,
+				}, fmt.Errorf("notification channel blocked for session %s: %w", sessionID, err))
+			}(session.SessionID(), hooks)
+		}
+		return err
+	}
+}
+
 // SendNotificationToSpecificClient sends a notification to a specific client by session ID
 func (s *MCPServer) SendNotificationToSpecificClient(
 	sessionID string,
@@ -224,6 +341,10 @@ func (s *MCPServer) AddSessionTools(sessionID string, tools ...ServerTool) error
 		return ErrSessionDoesNotSupportTools
 	}
 
+	// Like the global tools, session tools are only listed with the
+	// capability enabled
+	s.implicitlyRegisterToolCapabilities()
+
 	// Get existing tools (this should return a thread-safe copy)
 	sessionTools := session.GetSessionTools()
 
@@ -246,23 +367,7 @@ func (s *MCPServer) AddSessionTools(sessionID string, tools ...ServerTool) error
 	// It only makes sense to send tool notifications to initialized sessions --
 	// if we're not initialized yet the client can't possibly have sent their
 	// initial tools/list message
-	if session.Initialized() {
-		// Send notification only to this session
-		if err := s.SendNotificationToSpecificClient(sessionID, "notifications/tools/list_changed", nil); err != nil {
-			// Log the error but don't fail the operation
-			// The tools were successfully added, but notification failed
-			if s.hooks != nil && len(s.hooks.OnError) > 0 {
-				hooks := s.hooks
-				go func(sID string, hooks *Hooks) {
-					ctx := context.Background()
-					hooks.onError(ctx, nil, "notification", map[string]any{
-						"method":    "notifications/tools/list_changed",
-						"sessionID": sID,
-					}, fmt.Errorf("failed to send notification after adding tools: %w", err))
-				}(sessionID, hooks)
-			}
-		}
-	}
+	s.notifySessionListChanged(session, mcp.MethodNotificationToolsListChanged, "adding tools")
 
 	return nil
 }
@@ -304,23 +409,180 @@ func (s *MCPServer) DeleteSessionTools(sessionID string, names ...string) error
 	// It only makes sense to send tool notifications to initialized sessions --
 	// if we're not initialized yet the client can't possibly have sent their
 	// initial tools/list message
-	if session.Initialized() {
-		// Send notification only to this session
-		if err := s.SendNotificationToSpecificClient(sessionID, "notifications/tools/list_changed", nil); err != nil {
-			// Log the error but don't fail the operation
-			// The tools were successfully deleted, but notification failed
-			if s.hooks != nil && len(s.hooks.OnError) > 0 {
-				hooks := s.hooks
-				go func(sID string, hooks *Hooks) {
-					ctx := context.Background()
-					hooks.onError(ctx, nil, "notification", map[string]any{
-						"method":    "notifications/tools/list_changed",
-						"sessionID": sID,
-					}, fmt.Errorf("failed to send notification after deleting tools: %w", err))
-				}(sessionID, hooks)
-			}
-		}
+	s.notifySessionListChanged(session, mcp.MethodNotificationToolsListChanged, "deleting tools")
+
+	return nil
+}
+
+// AddSessionResource adds a resource for a specific session
+func (s *MCPServer) AddSessionResource(sessionID string, resource mcp.Resource, handler ResourceHandlerFunc) error {
+	return s.AddSessionResources(sessionID, ServerResource{Resource: resource, Handler: handler})
+}
+
+// AddSessionResources adds resources for a specific session
+func (s *MCPServer) AddSessionResources(sessionID string, resources ...ServerResource) error {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session, ok := sessionValue.(SessionWithResources)
+	if !ok {
+		return ErrSessionDoesNotSupportResources
+	}
+
+	// Like the global resources, session resources are only listed with the
+	// capability enabled
+	s.implicitlyRegisterResourceCapabilities()
+
+	// Copy existing resources into a new map to avoid concurrent modification issues
+	sessionResources := session.GetSessionResources()
+	newSessionResources := make(map[string]ServerResource, len(sessionResources)+len(resources))
+	for k, v := range sessionResources {
+		newSessionResources[k] = v
+	}
+	for _, resource := range resources {
+		newSessionResources[resource.Resource.URI] = resource
+	}
+
+	session.SetSessionResources(newSessionResources)
+
+	// Uninitialized sessions can't have listed resources yet, so they are
+	// not notified
+	s.notifySessionListChanged(session, mcp.MethodNotificationResourcesListChanged, "adding resources")
+
+	return nil
+}
+
+// DeleteSessionResources removes resources from a specific session by URI
+func (s *MCPServer) DeleteSessionResources(sessionID string, uris ...string) error {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session, ok := sessionValue.(SessionWithResources)
+	if !ok {
+		return ErrSessionDoesNotSupportResources
+	}
+
+	sessionResources := session.GetSessionResources()
+	if sessionResources == nil {
+		return nil
+	}
+
+	// Copy existing resources except those being deleted
+	newSessionResources := make(map[string]ServerResource, len(sessionResources))
+	for k, v := range sessionResources {
+		newSessionResources[k] = v
+	}
+	for _, uri := range uris {
+		delete(newSessionResources, uri)
+	}
+
+	session.SetSessionResources(newSessionResources)
+
+	// Uninitialized sessions can't have listed resources yet, so they are
+	// not notified
+	s.notifySessionListChanged(session, mcp.MethodNotificationResourcesListChanged, "deleting resources")
+
+	return nil
+}
+
+// AddSessionPrompt adds a prompt for a specific session
+func (s *MCPServer) AddSessionPrompt(sessionID string, prompt mcp.Prompt, handler PromptHandlerFunc) error {
+	return s.AddSessionPrompts(sessionID, ServerPrompt{Prompt: prompt, Handler: handler})
+}
+
+// AddSessionPrompts adds prompts for a specific session
+func (s *MCPServer) AddSessionPrompts(sessionID string, prompts ...ServerPrompt) error {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session, ok := sessionValue.(SessionWithPrompts)
+	if !ok {
+		return ErrSessionDoesNotSupportPrompts
+	}
+
+	// Like the global prompts, session prompts are only listed with the
+	// capability enabled
+	s.implicitlyRegisterPromptCapabilities()
+
+	// Copy existing prompts into a new map to avoid concurrent modification issues
+	sessionPrompts := session.GetSessionPrompts()
+	newSessionPrompts := make(map[string]ServerPrompt, len(sessionPrompts)+len(prompts))
+	for k, v := range sessionPrompts {
+		newSessionPrompts[k] = v
+	}
+	for _, prompt := range prompts {
+		newSessionPrompts[prompt.Prompt.Name] = prompt
 	}
 
+	session.SetSessionPrompts(newSessionPrompts)
+
+	// Uninitialized sessions can't have listed prompts yet, so they are not
+	// notified
+	s.notifySessionListChanged(session, mcp.MethodNotificationPromptsListChanged, "adding prompts")
+
 	return nil
 }
+
+// DeleteSessionPrompts removes prompts from a specific session by name
+func (s *MCPServer) DeleteSessionPrompts(sessionID string, names ...string) error {
+	sessionValue, ok := s.sessions.Load(sessionID)
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	session, ok := sessionValue.(SessionWithPrompts)
+	if !ok {
+		return ErrSessionDoesNotSupportPrompts
+	}
+
+	sessionPrompts := session.GetSessionPrompts()
+	if sessionPrompts == nil {
+		return nil
+	}
+
+	// Copy existing prompts except those being deleted
+	newSessionPrompts := make(map[string]ServerPrompt, len(sessionPrompts))
+	for k, v := range sessionPrompts {
+		newSessionPrompts[k] = v
+	}
+	for _, name := range names {
+		delete(newSessionPrompts, name)
+	}
+
+	session.SetSessionPrompts(newSessionPrompts)
+
+	// Uninitialized sessions can't have listed prompts yet, so they are not
+	// notified
+	s.notifySessionListChanged(session, mcp.MethodNotificationPromptsListChanged, "deleting prompts")
+
+	return nil
+}
+
+// notifySessionListChanged sends a list_changed notification with the given
+// method to session if it is initialized. A failure to send is reported to the
+// error hooks rather than returned, since the change itself has been applied.
+func (s *MCPServer) notifySessionListChanged(session ClientSession, method string, action string) {
+	if !session.Initialized() {
+		return
+	}
+
+	sessionID := session.SessionID()
+	if err := s.SendNotificationToSpecificClient(sessionID, method, nil); err != nil {
+		if s.hooks != nil && len(s.hooks.OnError) > 0 {
+			hooks := s.hooks
+			go func(sID string, hooks *Hooks) {
+				ctx := context.Background()
+				hooks.onError(ctx, nil, "notification", map[string]any{
+					"method":    method,
+					"sessionID": sID,
+				}, fmt.Errorf("failed to send notification after %s: %w", action, err))
+			}(sessionID, hooks)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"sync"
+)
+
+// SessionState is key-value storage for application data that lives as long
+// as a client session, e.g. a database transaction or a cursor kept across
+// tool calls. It is safe for concurrent use. Keys are compared like map keys;
+// as with context keys, unexported key types avoid collisions between
+// packages.
+type SessionState struct {
+	mu     sync.RWMutex
+	values map[any]any
+}
+
+// Get returns the value stored for key, if any.
+func (s *SessionState) Get(key any) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores value for key, replacing any previous value.
+func (s *SessionState) Set(key, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[any]any)
+	}
+	s.values[key] = value
+}
+
+// Delete removes the value stored for key.
+func (s *SessionState) Delete(key any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// SessionState returns the state of the registered session with the given
+// ID, created when the session is registered. It returns nil if no such
+// session is registered. The state is discarded when the session is
+// unregistered.
+func (s *MCPServer) SessionState(sessionID string) *SessionState {
+	state, ok := s.sessionStates.Load(sessionID)
+	if !ok {
+		return nil
+	}
+	return state.(*SessionState)
+}
+
+// SessionStateFromContext returns the state of the current session, for use
+// in handlers and hooks:
+//
+//	state := server.SessionStateFromContext(ctx)
+//	state.Set(txKey{}, tx)
+//
+// It returns nil if ctx carries no registered session.
+func SessionStateFromContext(ctx context.Context) *SessionState {
+	srv := ServerFromContext(ctx)
+	session := ClientSessionFromContext(ctx)
+	if srv == nil || session == nil {
+		return nil
+	}
+	return srv.SessionState(session.SessionID())
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_SessionState(t *testing.T) {
+	type counterKey struct{}
+
+	server := NewMCPServer("test-server", "1.0.0", WithToolCapabilities(true))
+	server.AddTool(mcp.NewTool("count"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		state := SessionStateFromContext(ctx)
+		if state == nil {
+			return nil, fmt.Errorf("no session state")
+		}
+		count, _ := state.Get(counterKey{})
+		n, _ := count.(int)
+		state.Set(counterKey{}, n+1)
+		return mcp.NewToolResultText(fmt.Sprint(n + 1)), nil
+	})
+
+	newSession := func(id string) *sessionTestClient {
+		session := &sessionTestClient{
+			sessionID:           id,
+			notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+			initialized:         true,
+		}
+		require.NoError(t, server.RegisterSession(context.Background(), session))
+		return session
+	}
+	callCount := func(session ClientSession) string {
+		ctx := server.WithContext(context.Background(), session)
+		response := server.HandleMessage(ctx, []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "count"}}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected success, got %#v", response)
+		result, ok := resp.Result.(mcp.CallToolResult)
+		require.True(t, ok)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+
+	first := newSession("session-1")
+	second := newSession("session-2")
+	_, ok := server.sessionStates.Load(first.SessionID())
+	assert.True(t, ok, "state is created with the session")
+
+	assert.Equal(t, "1", callCount(first))
+	assert.Equal(t, "2", callCount(first))
+	assert.Equal(t, "1", callCount(second), "state is not shared between sessions")
+
+	state := server.SessionState(first.SessionID())
+	require.NotNil(t, state)
+	value, ok := state.Get(counterKey{})
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	state.Delete(counterKey{})
+	_, ok = state.Get(counterKey{})
+	assert.False(t, ok)
+
+	server.UnregisterSession(context.Background(), first.SessionID())
+	assert.Nil(t, server.SessionState(first.SessionID()), "state is discarded with the session")
+	_, ok = server.sessionStates.Load(first.SessionID())
+	assert.False(t, ok)
+
+	assert.Nil(t, SessionStateFromContext(context.Background()))
+}
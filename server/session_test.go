@@ -98,9 +98,68 @@ func (f *sessionTestClientWithTools) SetSessionTools(tools map[string]ServerTool
 	f.sessionTools = toolsCopy
 }
 
-// Verify that both implementations satisfy their respective interfaces
+// sessionTestClientWithResourcesAndPrompts implements the SessionWithResources
+// and SessionWithPrompts interfaces for testing
+type sessionTestClientWithResourcesAndPrompts struct {
+	sessionTestClient
+	sessionResources map[string]ServerResource
+	sessionPrompts   map[string]ServerPrompt
+	mu               sync.RWMutex
+}
+
+func (f *sessionTestClientWithResourcesAndPrompts) GetSessionResources() map[string]ServerResource {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.sessionResources == nil {
+		return nil
+	}
+	resourcesCopy := make(map[string]ServerResource, len(f.sessionResources))
+	for k, v := range f.sessionResources {
+		resourcesCopy[k] = v
+	}
+	return resourcesCopy
+}
+
+func (f *sessionTestClientWithResourcesAndPrompts) SetSessionResources(resources map[string]ServerResource) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sessionResources = make(map[string]ServerResource, len(resources))
+	for k, v := range resources {
+		f.sessionResources[k] = v
+	}
+}
+
+func (f *sessionTestClientWithResourcesAndPrompts) GetSessionPrompts() map[string]ServerPrompt {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.sessionPrompts == nil {
+		return nil
+	}
+	promptsCopy := make(map[string]ServerPrompt, len(f.sessionPrompts))
+	for k, v := range f.sessionPrompts {
+		promptsCopy[k] = v
+	}
+	return promptsCopy
+}
+
+func (f *sessionTestClientWithResourcesAndPrompts) SetSessionPrompts(prompts map[string]ServerPrompt) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sessionPrompts = make(map[string]ServerPrompt, len(prompts))
+	for k, v := range prompts {
+		f.sessionPrompts[k] = v
+	}
+}
+
+// Verify that the implementations satisfy their respective interfaces
 var _ ClientSession = &sessionTestClient{}
 var _ SessionWithTools = &sessionTestClientWithTools{}
+var _ SessionWithResources = &sessionTestClientWithResourcesAndPrompts{}
+var _ SessionWithPrompts = &sessionTestClientWithResourcesAndPrompts{}
 
 func TestSessionWithTools_Integration(t *testing.T) {
 	server := NewMCPServer("test-server", "1.0.0", WithToolCapabilities(true))
@@ -802,3 +861,324 @@ func TestMCPServer_NotificationChannelBlocked(t *testing.T) {
 	assert.Equal(t, "blocked-session", localErrorSessionID, "Session ID should be captured in the error hook")
 	assert.Equal(t, "broadcast-message", localErrorMethod, "Method should be captured in the error hook")
 }
+
+func TestMCPServer_SendNotificationToClientWait(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+
+	// A size-1 channel that is already full
+	notificationChan := make(chan mcp.JSONRPCNotification, 1)
+	session := &sessionTestClient{
+		sessionID:           "slow-session",
+		notificationChannel: notificationChan,
+	}
+	session.Initialize()
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	notificationChan <- mcp.JSONRPCNotification{}
+
+	sessionCtx := server.WithContext(context.Background(), session)
+
+	t.Run("succeeds when the consumer drains in time", func(t *testing.T) {
+		go func() {
+			// Slow consumer
+			time.Sleep(20 * time.Millisecond)
+			<-notificationChan
+		}()
+
+		ctx, cancel := context.WithTimeout(sessionCtx, time.Second)
+		defer cancel()
+		err := server.SendNotificationToClientWait(ctx, "waited-message", nil)
+		require.NoError(t, err)
+
+		notification := <-notificationChan
+		assert.Equal(t, "waited-message", notification.Method)
+	})
+
+	t.Run("fails when the deadline is exceeded", func(t *testing.T) {
+		notificationChan <- mcp.JSONRPCNotification{}
+
+		ctx, cancel := context.WithTimeout(sessionCtx, 20*time.Millisecond)
+		defer cancel()
+		err := server.SendNotificationToClientWait(ctx, "dropped-message", nil)
+		assert.ErrorIs(t, err, ErrNotificationChannelBlocked)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("fails when the session is unregistered while waiting", func(t *testing.T) {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			server.UnregisterSession(context.Background(), session.SessionID())
+		}()
+
+		ctx, cancel := context.WithTimeout(sessionCtx, time.Second)
+		defer cancel()
+		err := server.SendNotificationToClientWait(ctx, "dropped-message", nil)
+		assert.ErrorIs(t, err, ErrSessionClosed)
+		assert.NoError(t, ctx.Err(), "should not wait for the deadline")
+	})
+}
+
+func TestMCPServer_SendNotificationAfterUnregister(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0")
+
+	session := &sessionTestClient{
+		sessionID:           "session-1",
+		notificationChannel: make(chan mcp.JSONRPCNotification, 10),
+		initialized:         true,
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	sessionCtx := server.WithContext(context.Background(), session)
+	require.NoError(t, server.SendNotificationToClient(sessionCtx, "before", nil))
+
+	server.UnregisterSession(context.Background(), session.SessionID())
+
+	err := server.SendNotificationToClient(sessionCtx, "after", nil)
+	assert.ErrorIs(t, err, ErrSessionClosed)
+	assert.EqualError(t, err, "session closed")
+	assert.ErrorIs(t, server.SendNotificationToClientWait(sessionCtx, "after", nil), ErrSessionClosed)
+	assert.Len(t, session.notificationChannel, 1, "only the notification sent before unregistering is queued")
+
+	// Registering a session with the same ID again does not revive contexts
+	// of the old registration
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+	assert.ErrorIs(t, server.SendNotificationToClient(sessionCtx, "after", nil), ErrSessionClosed)
+	assert.NoError(t, server.SendNotificationToClient(server.WithContext(context.Background(), session), "new", nil))
+}
+
+func TestMCPServer_SessionResources(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithResourceCapabilities(false, true))
+	server.AddResource(mcp.NewResource("test://global", "global"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: "test://global", Text: "global"}}, nil
+		})
+
+	sessionChan := make(chan mcp.JSONRPCNotification, 10)
+	session := &sessionTestClientWithResourcesAndPrompts{
+		sessionTestClient: sessionTestClient{
+			sessionID:           "session-1",
+			notificationChannel: sessionChan,
+			initialized:         true,
+		},
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	err := server.AddSessionResource(session.SessionID(), mcp.NewResource("test://session", "session"),
+		func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{mcp.TextResourceContents{URI: "test://session", Text: "session"}}, nil
+		})
+	require.NoError(t, err)
+
+	select {
+	case notification := <-sessionChan:
+		assert.Equal(t, mcp.MethodNotificationResourcesListChanged, notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected notification not received")
+	}
+
+	sessionCtx := server.WithContext(context.Background(), session)
+
+	response := server.HandleMessage(sessionCtx, []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	listResult, ok := resp.Result.(mcp.ListResourcesResult)
+	require.True(t, ok)
+	require.Len(t, listResult.Resources, 2)
+	assert.Equal(t, "global", listResult.Resources[0].Name)
+	assert.Equal(t, "session", listResult.Resources[1].Name)
+
+	response = server.HandleMessage(sessionCtx, []byte(`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"test://session"}}`))
+	resp, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	readResult, ok := resp.Result.(mcp.ReadResourceResult)
+	require.True(t, ok)
+	assert.Equal(t, "session", readResult.Contents[0].(mcp.TextResourceContents).Text)
+
+	// Other sessions don't see the resource
+	response = server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"test://session"}}`))
+	errResp, ok := response.(mcp.JSONRPCError)
+	require.True(t, ok)
+	assert.Equal(t, mcp.RESOURCE_NOT_FOUND, errResp.Error.Code)
+
+	require.NoError(t, server.DeleteSessionResources(session.SessionID(), "test://session"))
+
+	select {
+	case notification := <-sessionChan:
+		assert.Equal(t, mcp.MethodNotificationResourcesListChanged, notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected notification not received")
+	}
+	assert.Empty(t, session.GetSessionResources())
+
+	// Sessions must implement SessionWithResources
+	plainSession := &sessionTestClient{sessionID: "session-2", notificationChannel: make(chan mcp.JSONRPCNotification, 1)}
+	require.NoError(t, server.RegisterSession(context.Background(), plainSession))
+	err = server.DeleteSessionResources(plainSession.SessionID(), "test://session")
+	assert.ErrorIs(t, err, ErrSessionDoesNotSupportResources)
+}
+
+func TestMCPServer_SessionPrompts(t *testing.T) {
+	server := NewMCPServer("test-server", "1.0.0", WithPromptCapabilities(true))
+	server.AddPrompt(mcp.NewPrompt("greeting"),
+		func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return mcp.NewGetPromptResult("global", nil), nil
+		})
+
+	sessionChan := make(chan mcp.JSONRPCNotification, 10)
+	session := &sessionTestClientWithResourcesAndPrompts{
+		sessionTestClient: sessionTestClient{
+			sessionID:           "session-1",
+			notificationChannel: sessionChan,
+			initialized:         true,
+		},
+	}
+	require.NoError(t, server.RegisterSession(context.Background(), session))
+
+	// Add session-specific prompts, one overriding the global prompt
+	err := server.AddSessionPrompts(session.SessionID(),
+		ServerPrompt{
+			Prompt: mcp.NewPrompt("greeting"),
+			Handler: func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				return mcp.NewGetPromptResult("session", nil), nil
+			},
+		},
+		ServerPrompt{Prompt: mcp.NewPrompt("session-only")},
+	)
+	require.NoError(t, err)
+
+	select {
+	case notification := <-sessionChan:
+		assert.Equal(t, mcp.MethodNotificationPromptsListChanged, notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected notification not received")
+	}
+
+	sessionCtx := server.WithContext(context.Background(), session)
+
+	response := server.HandleMessage(sessionCtx, []byte(`{"jsonrpc":"2.0","id":1,"method":"prompts/list"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	listResult, ok := resp.Result.(mcp.ListPromptsResult)
+	require.True(t, ok)
+	require.Len(t, listResult.Prompts, 2)
+	assert.Equal(t, "greeting", listResult.Prompts[0].Name)
+	assert.Equal(t, "session-only", listResult.Prompts[1].Name)
+
+	response = server.HandleMessage(sessionCtx, []byte(`{"jsonrpc":"2.0","id":2,"method":"prompts/get","params":{"name":"greeting"}}`))
+	resp, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	getResult, ok := resp.Result.(mcp.GetPromptResult)
+	require.True(t, ok)
+	assert.Equal(t, "session", getResult.Description)
+
+	// Deleting the override falls back to the global prompt
+	require.NoError(t, server.DeleteSessionPrompts(session.SessionID(), "greeting"))
+
+	select {
+	case notification := <-sessionChan:
+		assert.Equal(t, mcp.MethodNotificationPromptsListChanged, notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected notification not received")
+	}
+
+	response = server.HandleMessage(sessionCtx, []byte(`{"jsonrpc":"2.0","id":3,"method":"prompts/get","params":{"name":"greeting"}}`))
+	resp, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	getResult, ok = resp.Result.(mcp.GetPromptResult)
+	require.True(t, ok)
+	assert.Equal(t, "global", getResult.Description)
+}
+
+func TestMCPServer_DeleteSessionResourcesAndPromptsUninitialized(t *testing.T) {
+	// Like tools, resources and prompts can be changed on a session before it
+	// is initialized, e.g. in the RegisterSession hook. The changes apply but
+	// no notification is sent and no error hook is called.
+	errorChan := make(chan error)
+	hooks := &Hooks{}
+	hooks.AddOnError(
+		func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+			errorChan <- err
+		},
+	)
+
+	server := NewMCPServer("test-server", "1.0.0",
+		WithResourceCapabilities(false, true),
+		WithPromptCapabilities(true),
+		WithHooks(hooks),
+	)
+	ctx := context.Background()
+
+	sessionChan := make(chan mcp.JSONRPCNotification, 1)
+	session := &sessionTestClientWithResourcesAndPrompts{
+		sessionTestClient: sessionTestClient{
+			sessionID:           "uninitialized-session",
+			notificationChannel: sessionChan,
+			initialized:         false,
+		},
+		sessionResources: map[string]ServerResource{
+			"test://delete": {Resource: mcp.NewResource("test://delete", "delete")},
+			"test://keep":   {Resource: mcp.NewResource("test://keep", "keep")},
+		},
+		sessionPrompts: map[string]ServerPrompt{
+			"prompt-to-delete": {Prompt: mcp.NewPrompt("prompt-to-delete")},
+			"prompt-to-keep":   {Prompt: mcp.NewPrompt("prompt-to-keep")},
+		},
+	}
+	require.NoError(t, server.RegisterSession(ctx, session))
+
+	require.NoError(t, server.DeleteSessionResources(session.SessionID(), "test://delete"))
+	require.NoError(t, server.DeleteSessionPrompts(session.SessionID(), "prompt-to-delete"))
+
+	select {
+	case err := <-errorChan:
+		t.Errorf("Expected error hooks not to be called, got error: %v", err)
+	case <-time.After(25 * time.Millisecond): // No errors
+	}
+
+	select {
+	case <-sessionChan:
+		t.Error("Expected no notification to be sent for uninitialized session")
+	default:
+	}
+
+	assert.Len(t, session.GetSessionResources(), 1)
+	assert.Contains(t, session.GetSessionResources(), "test://keep")
+	assert.Len(t, session.GetSessionPrompts(), 1)
+	assert.Contains(t, session.GetSessionPrompts(), "prompt-to-keep")
+
+	// The remaining entries are served once the session is initialized
+	session.Initialize()
+	sessionCtx := server.WithContext(ctx, session)
+
+	response := server.HandleMessage(sessionCtx, []byte(`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`))
+	resp, ok := response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	resources := resp.Result.(mcp.ListResourcesResult).Resources
+	require.Len(t, resources, 1)
+	assert.Equal(t, "test://keep", resources[0].URI)
+
+	response = server.HandleMessage(sessionCtx, []byte(`{"jsonrpc":"2.0","id":2,"method":"prompts/list"}`))
+	resp, ok = response.(mcp.JSONRPCResponse)
+	require.True(t, ok)
+	prompts := resp.Result.(mcp.ListPromptsResult).Prompts
+	require.Len(t, prompts, 1)
+	assert.Equal(t, "prompt-to-keep", prompts[0].Name)
+
+	// Subsequent deletions notify the initialized session
+	require.NoError(t, server.DeleteSessionResources(session.SessionID(), "test://keep"))
+	select {
+	case notification := <-sessionChan:
+		assert.Equal(t, mcp.MethodNotificationResourcesListChanged, notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected notification not received for initialized session")
+	}
+
+	require.NoError(t, server.DeleteSessionPrompts(session.SessionID(), "prompt-to-keep"))
+	select {
+	case notification := <-sessionChan:
+		assert.Equal(t, mcp.MethodNotificationPromptsListChanged, notification.Method)
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Expected notification not received for initialized session")
+	}
+
+	assert.Empty(t, session.GetSessionResources())
+	assert.Empty(t, session.GetSessionPrompts())
+}
@@ -3,12 +3,15 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -29,6 +32,58 @@ type sseSession struct {
 	notificationChannel chan mcp.JSONRPCNotification
 	initialized         atomic.Bool
 	tools               sync.Map // stores session-specific tools
+	resources           sync.Map // stores session-specific resources
+	prompts             sync.Map // stores session-specific prompts
+	pendingRequests     sync.Map // request ID -> chan sseClientResponse, for requests awaiting a response
+	writeMu             sync.Mutex
+	writerClosed        bool // guarded by writeMu, set once writer must not be used
+}
+
+// writeStreamedEvent writes response as a message event straight to the SSE
+// stream, flushing as its reader resources are read, rather than queuing it
+// in memory. It reports false, writing nothing, if response has no reader
+// contents.
+func (s *sseSession) writeStreamedEvent(response mcp.JSONRPCMessage) (bool, error) {
+	if _, _, ok := streamedResponse(response); !ok {
+		return false, nil
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if s.writerClosed {
+		discardStreamedResponse(response)
+		return true, ErrSessionClosed
+	}
+
+	if _, err := io.WriteString(s.writer, "event: message\ndata: "); err != nil {
+		discardStreamedResponse(response)
+		return true, err
+	}
+	_, err := writeStreamedResponse(s.writer, s.flusher.Flush, response)
+	// End the event even if cut short, for the client to move on
+	fmt.Fprint(s.writer, "\n\n")
+	s.flusher.Flush()
+	return true, err
+}
+
+// closeWriter stops writeStreamedEvent from using the writer, once the SSE
+// handler returns.
+func (s *sseSession) closeWriter() {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.writerClosed = true
+}
+
+// sseClientResponse is a client's response to a request sent by the server,
+// received on the message endpoint.
+type sseClientResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	} `json:"error"`
 }
 
 // SSEContextFunc is a function that takes an existing context and the current
@@ -52,6 +107,76 @@ func (s *sseSession) Initialized() bool {
 	return s.initialized.Load()
 }
 
+// SendRequest sends a request to the client as a message event and waits
+// for the client to post its response to the message endpoint.
+func (s *sseSession) SendRequest(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	id := s.requestID.Add(1)
+	request := mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      id,
+		Params:  params,
+		Request: mcp.Request{Method: method},
+	}
+	eventData, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	responses := make(chan sseClientResponse, 1)
+	s.pendingRequests.Store(id, responses)
+	defer s.pendingRequests.Delete(id)
+
+	select {
+	case s.eventQueue <- fmt.Sprintf("event: message\ndata: %s\n\n", eventData):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.done:
+		return nil, ErrSessionClosed
+	}
+
+	select {
+	case response := <-responses:
+		if response.Error != nil {
+			return nil, &mcp.JSONRPCErrorError{
+				Code:    response.Error.Code,
+				Message: response.Error.Message,
+				Data:    response.Error.Data,
+			}
+		}
+		return response.Result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.done:
+		return nil, ErrSessionClosed
+	}
+}
+
+// deliverResponse passes a response posted by the client to the SendRequest
+// call waiting for it. Responses to requests nobody waits for, e.g. to
+// keep-alive pings or requests that timed out, are dropped.
+func (s *sseSession) deliverResponse(response sseClientResponse) {
+	id, err := strconv.ParseInt(string(response.ID), 10, 64)
+	if err != nil {
+		return
+	}
+	if responses, ok := s.pendingRequests.LoadAndDelete(id); ok {
+		responses.(chan sseClientResponse) <- response
+	}
+}
+
+// parseClientResponse reports whether a message posted by the client is a
+// response to a request sent by the server, and decodes it if so.
+func parseClientResponse(message json.RawMessage) (sseClientResponse, bool) {
+	var response sseClientResponse
+	if err := json.Unmarshal(message, &response); err != nil {
+		return response, false
+	}
+	if response.Method != "" || len(response.ID) == 0 {
+		return response, false
+	}
+	return response, response.Result != nil || response.Error != nil
+}
+
 func (s *sseSession) GetSessionTools() map[string]ServerTool {
 	tools := make(map[string]ServerTool)
 	s.tools.Range(func(key, value any) bool {
@@ -76,9 +201,60 @@ func (s *sseSession) SetSessionTools(tools map[string]ServerTool) {
 	}
 }
 
+func (s *sseSession) GetSessionResources() map[string]ServerResource {
+	resources := make(map[string]ServerResource)
+	s.resources.Range(func(key, value any) bool {
+		if resource, ok := value.(ServerResource); ok {
+			resources[key.(string)] = resource
+		}
+		return true
+	})
+	return resources
+}
+
+func (s *sseSession) SetSessionResources(resources map[string]ServerResource) {
+	// Clear existing resources
+	s.resources.Range(func(key, _ any) bool {
+		s.resources.Delete(key)
+		return true
+	})
+
+	// Set new resources
+	for uri, resource := range resources {
+		s.resources.Store(uri, resource)
+	}
+}
+
+func (s *sseSession) GetSessionPrompts() map[string]ServerPrompt {
+	prompts := make(map[string]ServerPrompt)
+	s.prompts.Range(func(key, value any) bool {
+		if prompt, ok := value.(ServerPrompt); ok {
+			prompts[key.(string)] = prompt
+		}
+		return true
+	})
+	return prompts
+}
+
+func (s *sseSession) SetSessionPrompts(prompts map[string]ServerPrompt) {
+	// Clear existing prompts
+	s.prompts.Range(func(key, _ any) bool {
+		s.prompts.Delete(key)
+		return true
+	})
+
+	// Set new prompts
+	for name, prompt := range prompts {
+		s.prompts.Store(name, prompt)
+	}
+}
+
 var (
-	_ ClientSession    = (*sseSession)(nil)
-	_ SessionWithTools = (*sseSession)(nil)
+	_ ClientSession        = (*sseSession)(nil)
+	_ SessionWithTools     = (*sseSession)(nil)
+	_ SessionWithResources = (*sseSession)(nil)
+	_ SessionWithPrompts   = (*sseSession)(nil)
+	_ SessionWithRequests  = (*sseSession)(nil)
 )
 
 // SSEServer implements a Server-Sent Events (SSE) based MCP server.
@@ -91,14 +267,22 @@ type SSEServer struct {
 	useFullURLForMessageEndpoint bool
 	messageEndpoint              string
 	sseEndpoint                  string
+	healthEndpoint               string
 	sessions                     sync.Map
 	srv                          *http.Server
 	contextFunc                  HTTPContextFunc
 	dynamicBasePathFunc          DynamicBasePathFunc
+	idGenerator                  func() string
 
 	keepAlive         bool
 	keepAliveInterval time.Duration
 
+	handlerPoolSize  int
+	handlerQueueSize int
+	handlerPool      *handlerPool
+
+	synchronousResponses bool
+
 	mu sync.RWMutex
 }
 
@@ -139,6 +323,12 @@ func (s *SSEServer) setBaseURL(baseURL string) {
 	s.baseURL = baseURL
 }
 
+func (s *SSEServer) setIDGenerator(fn func() string) {
+	if fn != nil {
+		s.idGenerator = fn
+	}
+}
+
 // WithBasePath adds a new option for setting a static base path.
 //
 // Deprecated: Use WithStaticBasePath instead. This will be removed in a future version.
@@ -167,8 +357,10 @@ func WithAppendQueryToMessageEndpoint() SSEOption {
 }
 
 // WithUseFullURLForMessageEndpoint controls whether the SSE server returns a complete URL (including baseURL)
-// or just the path portion for the message endpoint. Set to false when clients will concatenate
-// the baseURL themselves to avoid malformed URLs like "http://localhost/mcphttp://localhost/mcp/message".
+// or just the path portion for the message endpoint. By default the path, including the path of the
+// baseURL and the sessionId query, is sent so clients resolve it against the origin they connected to,
+// which keeps working behind TLS-terminating or reverse proxies. Set to true for clients that cannot
+// resolve relative endpoints.
 func WithUseFullURLForMessageEndpoint(useFullURLForMessageEndpoint bool) SSEOption {
 	return sseOption(func(s *SSEServer) {
 		s.useFullURLForMessageEndpoint = useFullURLForMessageEndpoint
@@ -182,6 +374,49 @@ func WithSSEEndpoint(endpoint string) SSEOption {
 	})
 }
 
+// WithHealthEndpoint serves a status document at the given path, relative to
+// the base path. A GET to it returns 200 with the server name, version and
+// number of active sessions, and does not require a session.
+func WithHealthEndpoint(endpoint string) SSEOption {
+	return sseOption(func(s *SSEServer) {
+		s.healthEndpoint = endpoint
+	})
+}
+
+// WithHandlerPool runs message handlers on a pool of at most size goroutines
+// shared across all sessions, instead of one goroutine per message, bounding
+// the number of requests handled concurrently. Messages arriving while all
+// workers are busy are queued, see WithHandlerQueueSize; when the queue is
+// full, the message is rejected with a 503 response carrying a JSON-RPC
+// error with the message of ErrServerBusy.
+func WithHandlerPool(size int) SSEOption {
+	return sseOption(func(s *SSEServer) {
+		s.handlerPoolSize = size
+	})
+}
+
+// WithHandlerQueueSize sets how many messages may wait for a worker of the
+// pool configured with WithHandlerPool before new ones are rejected. It
+// defaults to 100.
+func WithHandlerQueueSize(size int) SSEOption {
+	return sseOption(func(s *SSEServer) {
+		s.handlerQueueSize = size
+	})
+}
+
+// WithSynchronousHTTPResponse makes the message endpoint answer each request
+// in the body of the POST response, with status 200, instead of responding
+// 202 Accepted and sending the result as an event on the SSE stream. This
+// supports clients that simply POST a request and read the response body.
+// Notifications are still answered with 202, and the client still needs a
+// session from the SSE endpoint, which carries server notifications and
+// requests.
+func WithSynchronousHTTPResponse() SSEOption {
+	return sseOption(func(s *SSEServer) {
+		s.synchronousResponses = true
+	})
+}
+
 // WithSSEContextFunc sets a function that will be called to customise the context
 // to the server using the incoming request.
 //
@@ -200,9 +435,10 @@ func NewSSEServer(server *MCPServer, opts ...SSEOption) *SSEServer {
 		server:                       server,
 		sseEndpoint:                  "/sse",
 		messageEndpoint:              "/message",
-		useFullURLForMessageEndpoint: true,
+		useFullURLForMessageEndpoint: false,
 		keepAlive:                    false,
 		keepAliveInterval:            10 * time.Second,
+		idGenerator:                  func() string { return uuid.New().String() },
 	}
 
 	// Apply all options
@@ -210,6 +446,14 @@ func NewSSEServer(server *MCPServer, opts ...SSEOption) *SSEServer {
 		opt.applyToSSE(s)
 	}
 
+	if s.handlerPoolSize > 0 {
+		queueSize := s.handlerQueueSize
+		if queueSize <= 0 {
+			queueSize = defaultHandlerQueueSize
+		}
+		s.handlerPool = newHandlerPool(s.handlerPoolSize, queueSize)
+	}
+
 	return s
 }
 
@@ -283,7 +527,7 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sessionID := uuid.New().String()
+	sessionID := s.idGenerator()
 	session := &sseSession{
 		writer:              w,
 		flusher:             flusher,
@@ -365,16 +609,23 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 	if s.appendQueryToMessageEndpoint && len(r.URL.RawQuery) > 0 {
 		endpoint += "&" + r.URL.RawQuery
 	}
+	// Streamed responses may be written as soon as the client knows the
+	// endpoint
+	session.writeMu.Lock()
 	fmt.Fprintf(w, "event: endpoint\ndata: %s\r\n\r\n", endpoint)
 	flusher.Flush()
+	session.writeMu.Unlock()
 
 	// Main event loop - this runs in the HTTP handler goroutine
+	defer session.closeWriter()
 	for {
 		select {
 		case event := <-session.eventQueue:
-			// Write the event to the response
+			// Write the event to the response, in between streamed responses
+			session.writeMu.Lock()
 			fmt.Fprint(w, event)
 			flusher.Flush()
+			session.writeMu.Unlock()
 		case <-r.Context().Done():
 			close(session.done)
 			return
@@ -388,6 +639,14 @@ func (s *SSEServer) handleSSE(w http.ResponseWriter, r *http.Request) {
 // for the given request. This is the canonical way to compute the message endpoint for a client.
 // It handles both dynamic and static path modes, and honors the WithUseFullURLForMessageEndpoint flag.
 func (s *SSEServer) GetMessageEndpointForClient(r *http.Request, sessionID string) string {
+	if mountPath, ok := r.Context().Value(mountPathKey{}).(string); ok {
+		endpointPath := normalizeURLPath(mountPath, s.messageEndpoint)
+		if s.useFullURLForMessageEndpoint && s.baseURL != "" {
+			endpointPath = urlOrigin(s.baseURL) + endpointPath
+		}
+		return fmt.Sprintf("%s?sessionId=%s", endpointPath, sessionID)
+	}
+
 	basePath := s.basePath
 	if s.dynamicBasePathFunc != nil {
 		basePath = s.dynamicBasePathFunc(r, sessionID)
@@ -396,6 +655,10 @@ func (s *SSEServer) GetMessageEndpointForClient(r *http.Request, sessionID strin
 	endpointPath := normalizeURLPath(basePath, s.messageEndpoint)
 	if s.useFullURLForMessageEndpoint && s.baseURL != "" {
 		endpointPath = s.baseURL + endpointPath
+	} else if baseURLPath, err := s.GetUrlPath(s.baseURL); err == nil && baseURLPath != "" {
+		// Keep the path the server is routed under, so the client can
+		// resolve the endpoint against the origin it connected to
+		endpointPath = strings.TrimSuffix(baseURLPath, "/") + endpointPath
 	}
 
 	return fmt.Sprintf("%s?sessionId=%s", endpointPath, sessionID)
@@ -421,34 +684,60 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 	}
 	session := sessionI.(*sseSession)
 
-	// Set the client context before handling the message
-	ctx := s.server.WithContext(r.Context(), session)
+	// Set the client context before handling the message. Reader resources
+	// are streamed to the client rather than read into the response.
+	ctx := withResourceStreaming(s.server.WithContext(r.Context(), session))
 	if s.contextFunc != nil {
 		ctx = s.contextFunc(ctx, r)
 	}
 
+	if s.server.maxRequestBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, int64(s.server.maxRequestBytes))
+	}
+
 	// Parse message as raw JSON
 	var rawMessage json.RawMessage
 	if err := json.NewDecoder(r.Body).Decode(&rawMessage); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			s.writeJSONRPCError(w, nil, mcp.INVALID_REQUEST, "Request too large")
+			return
+		}
 		s.writeJSONRPCError(w, nil, mcp.PARSE_ERROR, "Parse error")
 		return
 	}
 
+	// Responses to requests sent by the server are passed to the waiting
+	// caller instead of being handled as messages.
+	if response, ok := parseClientResponse(rawMessage); ok {
+		session.deliverResponse(response)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if s.synchronousResponses {
+		s.respondSynchronously(ctx, w, rawMessage)
+		return
+	}
+
 	// Create a context that preserves all values from parent ctx but won't be canceled when the parent is canceled.
 	// this is required because the http ctx will be canceled when the client disconnects
 	detachedCtx := context.WithoutCancel(ctx)
 
-	// quick return request, send 202 Accepted with no body, then deal the message and sent response via SSE
-	w.WriteHeader(http.StatusAccepted)
-
 	// Create a new context for handling the message that will be canceled when the message handling is done
 	messageCtx, cancel := context.WithCancel(detachedCtx)
 
-	go func(ctx context.Context) {
+	handle := func() {
 		defer cancel()
 		// Use the context that will be canceled when session is done
 		// Process message through MCPServer
-		response := s.server.HandleMessage(ctx, rawMessage)
+		response := s.server.HandleMessage(messageCtx, rawMessage)
+		if streamed, err := session.writeStreamedEvent(response); streamed {
+			if err != nil {
+				log.Printf("failed to stream response: %v", err)
+			}
+			return
+		}
 		// Only send response if there is one (not for notifications)
 		if response != nil {
 			var message string
@@ -471,7 +760,106 @@ func (s *SSEServer) handleMessage(w http.ResponseWriter, r *http.Request) {
 				log.Printf("Event queue full for session %s", sessionID)
 			}
 		}
-	}(messageCtx)
+	}
+
+	if s.handlerPool != nil {
+		if !s.handlerPool.submit(handle) {
+			cancel()
+			s.writeBusyError(w, rawMessage)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	// quick return request, send 202 Accepted with no body, then deal the message and sent response via SSE
+	w.WriteHeader(http.StatusAccepted)
+	go handle()
+}
+
+// respondSynchronously handles a message while the client waits and writes
+// the response to w, for WithSynchronousHTTPResponse. Unlike asynchronous
+// handling, the message context is canceled if the client disconnects.
+func (s *SSEServer) respondSynchronously(ctx context.Context, w http.ResponseWriter, rawMessage json.RawMessage) {
+	var response mcp.JSONRPCMessage
+	handled := make(chan struct{})
+	handle := func() {
+		defer close(handled)
+		response = s.server.HandleMessage(ctx, rawMessage)
+	}
+
+	if s.handlerPool != nil {
+		if !s.handlerPool.submit(handle) {
+			s.writeBusyError(w, rawMessage)
+			return
+		}
+	} else {
+		handle()
+	}
+	<-handled
+
+	// Notifications have no response
+	if response == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if _, _, ok := streamedResponse(response); ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		flush := func() {}
+		if flusher, ok := w.(http.Flusher); ok {
+			flush = flusher.Flush
+		}
+		if _, err := writeStreamedResponse(w, flush, response); err != nil {
+			log.Printf("failed to stream response: %v", err)
+		}
+		return
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("failed to marshal response: %v", err)
+		data, _ = json.Marshal(createErrorResponse(nil, mcp.INTERNAL_ERROR, "internal error"))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// healthStatus is the document returned by the health endpoint.
+type healthStatus struct {
+	Status   string `json:"status"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Sessions int    `json:"sessions"`
+}
+
+// handleHealth reports the server status without requiring a session.
+func (s *SSEServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessions := 0
+	s.sessions.Range(func(_, _ any) bool {
+		sessions++
+		return true
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_ = json.NewEncoder(w).Encode(healthStatus{
+		Status:   "ok",
+		Name:     s.server.name,
+		Version:  s.server.version,
+		Sessions: sessions,
+	})
 }
 
 // writeJSONRPCError writes a JSON-RPC error response with the given error details.
@@ -494,6 +882,21 @@ func (s *SSEServer) writeJSONRPCError(
 	}
 }
 
+// writeBusyError answers a message rejected because the handler pool's queue
+// is full with a 503 carrying a JSON-RPC error.
+func (s *SSEServer) writeBusyError(w http.ResponseWriter, rawMessage json.RawMessage) {
+	var baseMessage struct {
+		ID any `json:"id"`
+	}
+	_ = json.Unmarshal(rawMessage, &baseMessage)
+
+	response := createErrorResponse(baseMessage.ID, mcp.INTERNAL_ERROR, ErrServerBusy.Error())
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", "1")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
 // SendEventToSession sends an event to a specific SSE session identified by sessionID.
 // Returns an error if the session is not found or closed.
 func (s *SSEServer) SendEventToSession(
@@ -604,6 +1007,8 @@ func (s *SSEServer) CompleteMessagePath() string {
 //	mux.Handle("/mcp/{tenant}/sse", sseServer.SSEHandler())
 //	mux.Handle("/mcp/{tenant}/message", sseServer.MessageHandler())
 //
+// RegisterHandlers registers this handler and MessageHandler at once.
+//
 // For non-dynamic cases, use ServeHTTP method instead.
 func (s *SSEServer) SSEHandler() http.Handler {
 	return http.HandlerFunc(s.handleSSE)
@@ -638,6 +1043,136 @@ func (s *SSEServer) MessageHandler() http.Handler {
 	return http.HandlerFunc(s.handleMessage)
 }
 
+// HealthHandler returns an http.Handler for the health endpoint, for mounting
+// it on a custom router. See WithHealthEndpoint.
+func (s *SSEServer) HealthHandler() http.Handler {
+	return http.HandlerFunc(s.handleHealth)
+}
+
+// RegisterHandlers registers SSEHandler and MessageHandler on mux with the
+// given patterns, after checking that the message endpoint told to clients
+// is served by messagePattern:
+//
+//	sseServer := NewSSEServer(mcpServer,
+//		WithDynamicBasePath(func(r *http.Request, sessionID string) string {
+//			return "/mcp/" + r.PathValue("tenant")
+//		}),
+//	)
+//	err := sseServer.RegisterHandlers(mux, "/mcp/{tenant}/sse", "/mcp/{tenant}/message")
+//
+// With WithDynamicBasePath, the patterns must be the same base path followed
+// by the SSE and message endpoints. Otherwise, they must be the complete SSE
+// and message paths. Patterns may start with a method or host, as accepted by
+// http.ServeMux. Mismatched patterns are reported with an error wrapping
+// ErrInvalidHandlerPatterns, and nothing is registered.
+func (s *SSEServer) RegisterHandlers(mux *http.ServeMux, ssePattern, messagePattern string) error {
+	ssePath, ok := patternPath(ssePattern)
+	if !ok {
+		return fmt.Errorf("%w: SSE pattern %q has no path", ErrInvalidHandlerPatterns, ssePattern)
+	}
+	messagePath, ok := patternPath(messagePattern)
+	if !ok {
+		return fmt.Errorf("%w: message pattern %q has no path", ErrInvalidHandlerPatterns, messagePattern)
+	}
+
+	if s.dynamicBasePathFunc != nil {
+		sseEndpoint := normalizeURLPath(s.sseEndpoint)
+		messageEndpoint := normalizeURLPath(s.messageEndpoint)
+		if !strings.HasSuffix(ssePath, sseEndpoint) {
+			return fmt.Errorf("%w: SSE pattern %q does not end with the SSE endpoint %q",
+				ErrInvalidHandlerPatterns, ssePattern, sseEndpoint)
+		}
+		if !strings.HasSuffix(messagePath, messageEndpoint) {
+			return fmt.Errorf("%w: message pattern %q does not end with the message endpoint %q",
+				ErrInvalidHandlerPatterns, messagePattern, messageEndpoint)
+		}
+		if sseBase, messageBase := strings.TrimSuffix(ssePath, sseEndpoint),
+			strings.TrimSuffix(messagePath, messageEndpoint); sseBase != messageBase {
+			return fmt.Errorf("%w: SSE pattern base path %q differs from message pattern base path %q",
+				ErrInvalidHandlerPatterns, sseBase, messageBase)
+		}
+	} else {
+		if expected := s.CompleteSsePath(); ssePath != expected {
+			return fmt.Errorf("%w: SSE pattern %q does not match the SSE path %q",
+				ErrInvalidHandlerPatterns, ssePattern, expected)
+		}
+		if expected := s.CompleteMessagePath(); messagePath != expected {
+			return fmt.Errorf("%w: message pattern %q does not match the message path %q",
+				ErrInvalidHandlerPatterns, messagePattern, expected)
+		}
+	}
+
+	mux.Handle(ssePattern, s.SSEHandler())
+	mux.Handle(messagePattern, s.MessageHandler())
+	return nil
+}
+
+// patternPath returns the path of an http.ServeMux pattern, without the
+// method and host it may start with.
+func patternPath(pattern string) (string, bool) {
+	i := strings.Index(pattern, "/")
+	if i < 0 {
+		return "", false
+	}
+	return pattern[i:], true
+}
+
+// mountPathKey is the context key under which Handler records the path the
+// SSE server is mounted at.
+type mountPathKey struct{}
+
+// Handler returns an http.Handler serving the SSE, message and health
+// endpoints relative to wherever it is mounted, so it can be embedded in any
+// router without configuring a base path:
+//
+//	mux.Handle("/api/mcp/", sseServer.Handler())
+//	// or, equivalently
+//	mux.Handle("/api/mcp/", http.StripPrefix("/api/mcp", sseServer.Handler()))
+//
+// Requests are routed by the endpoint path they end with, and clients are
+// told the message endpoint under the same prefix as the SSE endpoint they
+// connected to. Base path options are ignored; if a base URL is set, only its
+// scheme and host are used.
+func (s *SSEServer) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		ssePath := normalizeURLPath(s.sseEndpoint)
+		switch {
+		case strings.HasSuffix(path, ssePath):
+			mountPath := strings.TrimSuffix(originalRequestPath(r), ssePath)
+			ctx := context.WithValue(r.Context(), mountPathKey{}, mountPath)
+			s.handleSSE(w, r.WithContext(ctx))
+		case strings.HasSuffix(path, normalizeURLPath(s.messageEndpoint)):
+			s.handleMessage(w, r)
+		case s.healthEndpoint != "" && strings.HasSuffix(path, normalizeURLPath(s.healthEndpoint)):
+			s.handleHealth(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// originalRequestPath returns the request path as sent by the client, before
+// any prefix was stripped by a router.
+func originalRequestPath(r *http.Request) string {
+	if r.RequestURI != "" {
+		if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+			return u.Path
+		}
+	}
+	return r.URL.Path
+}
+
+// urlOrigin returns the scheme and host of rawURL, or rawURL itself if it
+// cannot be parsed.
+func urlOrigin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return u.Scheme + "://" + u.Host
+}
+
 // ServeHTTP implements the http.Handler interface.
 func (s *SSEServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if s.dynamicBasePathFunc != nil {
@@ -660,6 +1195,10 @@ func (s *SSEServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		s.handleMessage(w, r)
 		return
 	}
+	if s.healthEndpoint != "" && path == normalizeURLPath(s.basePath, s.healthEndpoint) {
+		s.handleHealth(w, r)
+		return
+	}
 
 	http.NotFound(w, r)
 }
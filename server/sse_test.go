@@ -10,11 +10,14 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zillow/mcp-go/mcp"
 )
@@ -72,7 +75,7 @@ func TestSSEServer(t *testing.T) {
 		}
 
 		// Extract message endpoint URL
-		messageURL := strings.TrimSpace(
+		messageURL := testServer.URL + strings.TrimSpace(
 			strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
 		)
 
@@ -150,7 +153,7 @@ func TestSSEServer(t *testing.T) {
 				}
 
 				endpointEvent := string(buf[:n])
-				messageURL := strings.TrimSpace(
+				messageURL := testServer.URL + strings.TrimSpace(
 					strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
 				)
 
@@ -381,11 +384,11 @@ func TestSSEServer(t *testing.T) {
 		}
 
 		endpointEvent := string(buf[:n])
-		messageURL := strings.TrimSpace(
+		messageURL := ts.URL + strings.TrimSpace(
 			strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
 		)
 
-		// The messageURL should already be correct since we set the baseURL correctly
+		// The messageURL is relative to the origin and keeps the /mcp prefix of the baseURL
 		// Test message endpoint
 		initRequest := map[string]any{
 			"jsonrpc": "2.0",
@@ -415,6 +418,120 @@ func TestSSEServer(t *testing.T) {
 		cancel()
 	})
 
+	t.Run("Handler mounts under arbitrary prefix", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0")
+		sseServer := NewSSEServer(mcpServer)
+
+		mux := http.NewServeMux()
+		mux.Handle("/api/v1/mcp/", sseServer.Handler())
+		mux.Handle("/stripped/", http.StripPrefix("/stripped", sseServer.Handler()))
+
+		ts := httptest.NewServer(mux)
+		defer ts.Close()
+
+		for _, prefix := range []string{"/api/v1/mcp", "/stripped"} {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			req, err := http.NewRequestWithContext(ctx, "GET", ts.URL+prefix+"/sse", nil)
+			if err != nil {
+				t.Fatalf("Failed to create request: %v", err)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Failed to connect to SSE endpoint: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", resp.StatusCode)
+			}
+
+			buf := make([]byte, 1024)
+			n, err := resp.Body.Read(buf)
+			if err != nil {
+				t.Fatalf("Failed to read SSE response: %v", err)
+			}
+			endpoint := strings.TrimSpace(
+				strings.Split(strings.Split(string(buf[:n]), "data: ")[1], "\n")[0],
+			)
+			if !strings.HasPrefix(endpoint, prefix+"/message?sessionId=") {
+				t.Errorf("Expected endpoint under %s, got %s", prefix, endpoint)
+			}
+
+			initRequest := map[string]any{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"method":  "initialize",
+				"params": map[string]any{
+					"protocolVersion": "2024-11-05",
+					"clientInfo": map[string]any{
+						"name":    "test-client",
+						"version": "1.0.0",
+					},
+				},
+			}
+			requestBody, _ := json.Marshal(initRequest)
+
+			postResp, err := http.Post(ts.URL+endpoint, "application/json", bytes.NewBuffer(requestBody))
+			if err != nil {
+				t.Fatalf("Failed to send message: %v", err)
+			}
+			postResp.Body.Close()
+			if postResp.StatusCode != http.StatusAccepted {
+				t.Errorf("Expected status 202, got %d", postResp.StatusCode)
+			}
+
+			cancel()
+			resp.Body.Close()
+		}
+	})
+
+	t.Run("Message endpoint is relative by default", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0")
+		r := httptest.NewRequest(http.MethodGet, "/mcp/sse", nil)
+
+		sseServer := NewSSEServer(mcpServer, WithBaseURL("https://example.com/mcp"))
+		if got := sseServer.GetMessageEndpointForClient(r, "abc"); got != "/mcp/message?sessionId=abc" {
+			t.Errorf("Expected relative endpoint /mcp/message?sessionId=abc, got %s", got)
+		}
+
+		sseServer = NewSSEServer(mcpServer,
+			WithBaseURL("https://example.com/mcp"),
+			WithUseFullURLForMessageEndpoint(true),
+		)
+		if got := sseServer.GetMessageEndpointForClient(r, "abc"); got != "https://example.com/mcp/message?sessionId=abc" {
+			t.Errorf("Expected full endpoint https://example.com/mcp/message?sessionId=abc, got %s", got)
+		}
+	})
+
+	t.Run("Uses custom ID generator for session IDs", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0")
+		var next int
+		sseServer := NewSSEServer(mcpServer, WithIDGenerator(func() string {
+			next++
+			return fmt.Sprintf("session-%d", next)
+		}))
+		ts := httptest.NewServer(sseServer)
+		defer ts.Close()
+
+		for i := 1; i <= 2; i++ {
+			sseResp, err := http.Get(fmt.Sprintf("%s/sse", ts.URL))
+			if err != nil {
+				t.Fatalf("Failed to connect to SSE endpoint: %v", err)
+			}
+
+			endpointEvent, err := readSSEEvent(sseResp)
+			sseResp.Body.Close()
+			if err != nil {
+				t.Fatalf("Failed to read SSE response: %v", err)
+			}
+
+			expected := fmt.Sprintf("event: endpoint\ndata: /message?sessionId=session-%d\r\n\r\n", i)
+			if endpointEvent != expected {
+				t.Errorf("Expected endpoint event %q, got %q", expected, endpointEvent)
+			}
+		}
+	})
+
 	t.Run("test useFullURLForMessageEndpoint", func(t *testing.T) {
 		mcpServer := NewMCPServer("test", "1.0.0")
 		sseServer := NewSSEServer(mcpServer)
@@ -464,8 +581,8 @@ func TestSSEServer(t *testing.T) {
 		}
 		// Extract message endpoint and check correctness
 		messageURL := strings.TrimSpace(strings.Split(strings.Split(endpointEventStr, "data: ")[1], "\n")[0])
-		if !strings.HasPrefix(messageURL, sseServer.messageEndpoint) {
-			t.Errorf("Expected messageURL to be %s, got %s", sseServer.messageEndpoint, messageURL)
+		if !strings.HasPrefix(messageURL, "/mcp"+sseServer.messageEndpoint+"?sessionId=") {
+			t.Errorf("Expected messageURL to be /mcp%s, got %s", sseServer.messageEndpoint, messageURL)
 		}
 
 		// The messageURL should already be correct since we set the baseURL correctly
@@ -484,7 +601,7 @@ func TestSSEServer(t *testing.T) {
 		}
 		requestBody, _ := json.Marshal(initRequest)
 
-		resp, err = http.Post(sseServer.baseURL+messageURL, "application/json", bytes.NewBuffer(requestBody))
+		resp, err = http.Post(ts.URL+messageURL, "application/json", bytes.NewBuffer(requestBody))
 		if err != nil {
 			t.Fatalf("Failed to send message: %v", err)
 		}
@@ -595,7 +712,7 @@ func TestSSEServer(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to read SSE response: %v", err)
 		}
-		messageURL := strings.TrimSpace(
+		messageURL := testServer.URL + strings.TrimSpace(
 			strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
 		)
 
@@ -789,7 +906,7 @@ func TestSSEServer(t *testing.T) {
 				if err != nil {
 					t.Fatalf("Failed to read endpoint data: %v", err)
 				}
-				messageURL = strings.TrimSpace(strings.TrimPrefix(dataLine, "data: "))
+				messageURL = testServer.URL + strings.TrimSpace(strings.TrimPrefix(dataLine, "data: "))
 
 				_, err = reader.ReadString('\n')
 				if err != nil {
@@ -985,6 +1102,62 @@ func TestSSEServer(t *testing.T) {
 			t.Errorf("Expected id 1, got %v", response["id"])
 		}
 	})
+	t.Run("TestRegisterHandlers", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0")
+		sseServer := NewSSEServer(
+			mcpServer,
+			WithDynamicBasePath(func(r *http.Request, sessionID string) string {
+				return "/mcp/" + r.PathValue("tenant")
+			}),
+		)
+
+		mux := http.NewServeMux()
+		for _, patterns := range [][2]string{
+			{"/mcp/{tenant}/events", "/mcp/{tenant}/message"},
+			{"/mcp/{tenant}/sse", "/mcp/{tenant}/messages"},
+			{"/mcp/{tenant}/sse", "/api/{tenant}/message"},
+			{"sse", "/mcp/{tenant}/message"},
+		} {
+			err := sseServer.RegisterHandlers(mux, patterns[0], patterns[1])
+			assert.ErrorIs(t, err, ErrInvalidHandlerPatterns, "patterns %v", patterns)
+		}
+		require.NoError(t, sseServer.RegisterHandlers(mux, "GET /mcp/{tenant}/sse", "POST /mcp/{tenant}/message"))
+
+		ts := httptest.NewServer(mux)
+		defer ts.Close()
+
+		resp, err := http.Get(ts.URL + "/mcp/tenant123/sse")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		reader := bufio.NewReader(resp.Body)
+		var endpointEvent strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			endpointEvent.WriteString(line)
+			if line == "\n" || line == "\r\n" {
+				break // End of SSE frame
+			}
+		}
+		messageURL := strings.TrimSpace(strings.Split(strings.Split(endpointEvent.String(), "data: ")[1], "\n")[0])
+		assert.True(t, strings.HasPrefix(messageURL, "/mcp/tenant123/message?sessionId="), messageURL)
+
+		resp2, err := http.Post(ts.URL+messageURL, "application/json",
+			strings.NewReader(`{"jsonrpc": "2.0", "id": 1, "method": "ping"}`))
+		require.NoError(t, err)
+		resp2.Body.Close()
+		assert.Equal(t, http.StatusAccepted, resp2.StatusCode)
+
+		t.Run("Static paths", func(t *testing.T) {
+			sseServer := NewSSEServer(mcpServer, WithStaticBasePath("/mcp"))
+			mux := http.NewServeMux()
+			err := sseServer.RegisterHandlers(mux, "/sse", "/message")
+			assert.ErrorIs(t, err, ErrInvalidHandlerPatterns)
+			assert.NoError(t, sseServer.RegisterHandlers(mux, "/mcp/sse", "/mcp/message"))
+		})
+	})
 	t.Run("TestSSEHandlerRequiresDynamicBasePath", func(t *testing.T) {
 		mcpServer := NewMCPServer("test", "1.0.0")
 		sseServer := NewSSEServer(mcpServer)
@@ -1273,7 +1446,7 @@ func TestSSEServer(t *testing.T) {
 		}
 
 		// Extract message endpoint URL
-		messageURL := strings.TrimSpace(
+		messageURL := testServer.URL + strings.TrimSpace(
 			strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
 		)
 
@@ -1357,7 +1530,7 @@ func TestSSEServer(t *testing.T) {
 		require.NoError(t, err, "Failed to read SSE response")
 		require.Contains(t, endpointEvent, "event: endpoint", "Expected endpoint event")
 
-		messageURL := strings.TrimSpace(
+		messageURL := testServer.URL + strings.TrimSpace(
 			strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
 		)
 
@@ -1403,6 +1576,263 @@ func TestSSEServer(t *testing.T) {
 		}
 	})
 
+	t.Run("Handler pool bounds concurrent handlers", func(t *testing.T) {
+		const poolSize, queueSize = 2, 30
+
+		mcpServer := NewMCPServer("test", "1.0.0")
+		release := make(chan struct{})
+		var running, maxRunning, completed atomic.Int32
+		mcpServer.AddTool(mcp.NewTool("block"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			n := running.Add(1)
+			for {
+				max := maxRunning.Load()
+				if n <= max || maxRunning.CompareAndSwap(max, n) {
+					break
+				}
+			}
+			<-release
+			running.Add(-1)
+			completed.Add(1)
+			return mcp.NewToolResultText("done"), nil
+		})
+
+		testServer := NewTestServer(mcpServer, WithHandlerPool(poolSize), WithHandlerQueueSize(queueSize))
+		defer testServer.Close()
+
+		sseResp, err := http.Get(fmt.Sprintf("%s/sse", testServer.URL))
+		require.NoError(t, err, "Failed to connect to SSE endpoint")
+		defer sseResp.Body.Close()
+
+		endpointEvent, err := readSSEEvent(sseResp)
+		require.NoError(t, err, "Failed to read SSE response")
+		messageURL := testServer.URL + strings.TrimSpace(
+			strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
+		)
+
+		post := func(id int) *http.Response {
+			body := fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"block"}}`, id)
+			resp, err := http.Post(messageURL, "application/json", strings.NewReader(body))
+			require.NoError(t, err, "Failed to send message")
+			return resp
+		}
+
+		// Occupy all workers so that further messages queue
+		for i := 1; i <= poolSize; i++ {
+			resp := post(i)
+			resp.Body.Close()
+			require.Equal(t, http.StatusAccepted, resp.StatusCode)
+		}
+		require.Eventually(t, func() bool { return running.Load() == poolSize }, 2*time.Second, 10*time.Millisecond)
+
+		baseline := runtime.NumGoroutine()
+		for i := poolSize + 1; i <= poolSize+queueSize; i++ {
+			resp := post(i)
+			resp.Body.Close()
+			require.Equal(t, http.StatusAccepted, resp.StatusCode)
+		}
+		assert.Less(t, runtime.NumGoroutine()-baseline, 10, "queued messages should not start goroutines")
+
+		// The queue is full
+		resp := post(poolSize + queueSize + 1)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		var errResp mcp.JSONRPCError
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		resp.Body.Close()
+		assert.Equal(t, float64(poolSize+queueSize+1), errResp.ID)
+		assert.Equal(t, ErrServerBusy.Error(), errResp.Error.Message)
+
+		close(release)
+		require.Eventually(t, func() bool { return completed.Load() == poolSize+queueSize }, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, int32(poolSize), maxRunning.Load())
+	})
+
+	t.Run("Synchronous HTTP responses", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0")
+		mcpServer.AddTool(mcp.NewTool("echo", mcp.WithString("message")), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(request.Params.Arguments["message"].(string)), nil
+		})
+
+		testServer := NewTestServer(mcpServer, WithSynchronousHTTPResponse())
+		defer testServer.Close()
+
+		sseResp, err := http.Get(fmt.Sprintf("%s/sse", testServer.URL))
+		require.NoError(t, err, "Failed to connect to SSE endpoint")
+		defer sseResp.Body.Close()
+
+		endpointEvent, err := readSSEEvent(sseResp)
+		require.NoError(t, err, "Failed to read SSE response")
+		messageURL := testServer.URL + strings.TrimSpace(
+			strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
+		)
+		post := func(body string) *http.Response {
+			resp, err := http.Post(messageURL, "application/json", strings.NewReader(body))
+			require.NoError(t, err, "Failed to send message")
+			return resp
+		}
+
+		resp := post(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test-client","version":"1.0.0"}}}`)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = post(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+		resp = post(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"message":"hello"}}}`)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		var response struct {
+			ID     int             `json:"id"`
+			Result json.RawMessage `json:"result"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, 2, response.ID)
+		result, err := mcp.ParseCallToolResult(&response.Result)
+		require.NoError(t, err)
+		text, ok := result.FirstText()
+		require.True(t, ok)
+		assert.Equal(t, "hello", text)
+	})
+
+	t.Run("Routes client responses to server requests", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0")
+		mcpServer.AddTool(mcp.NewTool("list-roots"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := mcpServer.SendRequestToClient(ctx, "roots/list", nil)
+			if err != nil {
+				return nil, err
+			}
+			return mcp.NewToolResultText(string(result)), nil
+		})
+
+		testServer := NewTestServer(mcpServer)
+		defer testServer.Close()
+
+		sseResp, err := http.Get(fmt.Sprintf("%s/sse", testServer.URL))
+		require.NoError(t, err, "Failed to connect to SSE endpoint")
+		defer sseResp.Body.Close()
+
+		endpointEvent, err := readSSEEvent(sseResp)
+		require.NoError(t, err, "Failed to read SSE response")
+		messageURL := testServer.URL + strings.TrimSpace(
+			strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
+		)
+		post := func(body string) {
+			resp, err := http.Post(messageURL, "application/json", strings.NewReader(body))
+			require.NoError(t, err, "Failed to send message")
+			resp.Body.Close()
+			require.Equal(t, http.StatusAccepted, resp.StatusCode)
+		}
+		readMessage := func() map[string]any {
+			event, err := readSSEEvent(sseResp)
+			require.NoError(t, err, "Failed to read SSE event")
+			var message map[string]any
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(strings.Split(event, "data: ")[1])), &message))
+			return message
+		}
+
+		post(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","clientInfo":{"name":"test-client","version":"1.0.0"}}}`)
+		readMessage()
+
+		post(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list-roots"}}`)
+		request := readMessage()
+		require.Equal(t, "roots/list", request["method"])
+		require.NotNil(t, request["id"])
+
+		// A response to a request the server is not waiting for is dropped
+		post(`{"jsonrpc":"2.0","id":12345,"result":{}}`)
+
+		requestID, err := json.Marshal(request["id"])
+		require.NoError(t, err)
+		post(fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":{"roots":[{"uri":"file:///tmp"}]}}`, requestID))
+
+		response := readMessage()
+		assert.Equal(t, float64(2), response["id"])
+		result := response["result"].(map[string]any)
+		content := result["content"].([]any)[0].(map[string]any)
+		assert.JSONEq(t, `{"roots":[{"uri":"file:///tmp"}]}`, content["text"].(string))
+	})
+
+	t.Run("Rejects message bodies over the request size limit", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0", WithMaxRequestBytes(1024))
+		var called atomic.Bool
+		mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			called.Store(true)
+			return mcp.NewToolResultText("echo"), nil
+		})
+
+		testServer := NewTestServer(mcpServer)
+		defer testServer.Close()
+
+		sseResp, err := http.Get(fmt.Sprintf("%s/sse", testServer.URL))
+		require.NoError(t, err, "Failed to connect to SSE endpoint")
+		defer sseResp.Body.Close()
+
+		endpointEvent, err := readSSEEvent(sseResp)
+		require.NoError(t, err, "Failed to read SSE response")
+		messageURL := testServer.URL + strings.TrimSpace(
+			strings.Split(strings.Split(endpointEvent, "data: ")[1], "\n")[0],
+		)
+
+		body := fmt.Sprintf(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":%q}}}`,
+			strings.Repeat("x", 64*1024),
+		)
+		resp, err := http.Post(messageURL, "application/json", strings.NewReader(body))
+		require.NoError(t, err, "Failed to send message")
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		var errResp mcp.JSONRPCError
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		assert.Equal(t, mcp.INVALID_REQUEST, errResp.Error.Code)
+		assert.Equal(t, "Request too large", errResp.Error.Message)
+		assert.False(t, called.Load(), "oversized message should not reach the handler")
+
+		// Messages within the limit are still handled
+		resp, err = http.Post(messageURL, "application/json",
+			strings.NewReader(`{"jsonrpc":"2.0","id":2,"method":"ping"}`))
+		require.NoError(t, err, "Failed to send message")
+		resp.Body.Close()
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	})
+
+	t.Run("Health endpoint reports server status", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0")
+		testServer := NewTestServer(mcpServer,
+			WithStaticBasePath("/mcp"),
+			WithHealthEndpoint("/health"),
+		)
+		defer testServer.Close()
+
+		sseResp, err := http.Get(fmt.Sprintf("%s/mcp/sse", testServer.URL))
+		require.NoError(t, err)
+		defer sseResp.Body.Close()
+		_, err = readSSEEvent(sseResp)
+		require.NoError(t, err)
+
+		resp, err := http.Get(fmt.Sprintf("%s/mcp/health", testServer.URL))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		var status map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+		require.Equal(t, map[string]any{
+			"status":   "ok",
+			"name":     "test",
+			"version":  "1.0.0",
+			"sessions": float64(1),
+		}, status)
+
+		postResp, err := http.Post(fmt.Sprintf("%s/mcp/health", testServer.URL), "application/json", nil)
+		require.NoError(t, err)
+		postResp.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, postResp.StatusCode)
+	})
+
 	t.Run("Start() then Shutdown() should not deadlock", func(t *testing.T) {
 		mcpServer := NewMCPServer("test", "1.0.0")
 		sseServer := NewSSEServer(mcpServer, WithBaseURL("http://localhost:0"))
@@ -4,11 +4,13 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"sync/atomic"
 	"syscall"
 
@@ -136,10 +138,24 @@ func (s *StdioServer) processInputStream(ctx context.Context, reader *bufio.Read
 			if err == io.EOF {
 				return nil
 			}
+			if errors.Is(err, ErrRequestTooLarge) {
+				s.errLogger.Printf("Error reading input: message exceeds %d bytes", s.server.maxRequestBytes)
+				response := createErrorResponse(nil, mcp.INVALID_REQUEST, "Request too large")
+				if err := s.writeResponse(response, stdout); err != nil {
+					return fmt.Errorf("failed to write response: %w", err)
+				}
+				continue
+			}
 			s.errLogger.Printf("Error reading input: %v", err)
 			return err
 		}
 
+		// Blank lines carry no message; skip them rather than answering
+		// with a parse error.
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
 		if err := s.processMessage(ctx, line, stdout); err != nil {
 			if err == io.EOF {
 				return nil
@@ -154,7 +170,10 @@ func (s *StdioServer) processInputStream(ctx context.Context, reader *bufio.Read
 // It uses channels to make the read operation cancellable via context.
 // Returns the read line and any error encountered. If the context is cancelled,
 // returns an empty string and the context's error. EOF is returned when the input
-// stream is closed.
+// stream is closed; a final message not terminated by a newline is returned
+// before EOF. Lines are not limited by the reader's buffer size; a line
+// longer than the limit set by WithMaxRequestBytes is discarded and
+// ErrRequestTooLarge returned instead.
 func (s *StdioServer) readNextLine(ctx context.Context, reader *bufio.Reader) (string, error) {
 	readChan := make(chan string, 1)
 	errChan := make(chan error, 1)
@@ -166,7 +185,11 @@ func (s *StdioServer) readNextLine(ctx context.Context, reader *bufio.Reader) (s
 		case <-done:
 			return
 		default:
-			line, err := reader.ReadString('\n')
+			line, err := readLimitedLine(reader, s.server.maxRequestBytes)
+			if err == io.EOF && line != "" {
+				// The next call reports EOF.
+				err = nil
+			}
 			if err != nil {
 				select {
 				case errChan <- err:
@@ -230,6 +253,7 @@ func (s *StdioServer) processMessage(
 	// Parse the message as raw JSON
 	var rawMessage json.RawMessage
 	if err := json.Unmarshal([]byte(line), &rawMessage); err != nil {
+		s.errLogger.Printf("Error parsing message %q: %v", truncateForLog(line, 200), err)
 		response := createErrorResponse(nil, mcp.PARSE_ERROR, "Parse error")
 		return s.writeResponse(response, writer)
 	}
@@ -247,6 +271,15 @@ func (s *StdioServer) processMessage(
 	return nil
 }
 
+// truncateForLog shortens s to at most n bytes for inclusion in a log line.
+func truncateForLog(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
 // writeResponse marshals and writes a JSON-RPC response message followed by a newline.
 // Returns an error if marshaling or writing fails.
 func (s *StdioServer) writeResponse(
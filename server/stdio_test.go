@@ -2,11 +2,14 @@ package server
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/zillow/mcp-go/mcp"
@@ -267,4 +270,113 @@ func TestStdioServer(t *testing.T) {
 			t.Errorf("unexpected server error: %v", err)
 		}
 	})
+
+	t.Run("Skips blank lines and reads long and unterminated lines", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0")
+		mcpServer.AddTool(mcp.NewTool("echo"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			text, _ := request.Params.Arguments["text"].(string)
+			return mcp.NewToolResultText(text), nil
+		})
+
+		var logs strings.Builder
+		stdioServer := NewStdioServer(mcpServer)
+		stdioServer.SetErrorLogger(log.New(&logs, "", 0))
+
+		// Larger than the default bufio.Reader buffer of 4096 bytes
+		longText := strings.Repeat("x", 64*1024)
+		input := strings.Join([]string{
+			`{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+			``,
+			`   `,
+			fmt.Sprintf(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"text":%q}}}`, longText),
+			`{not json`,
+			`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+		}, "\n")
+
+		var stdout bytes.Buffer
+		err := stdioServer.Listen(context.Background(), strings.NewReader(input), &stdout)
+		if err != nil {
+			t.Fatalf("unexpected server error: %v", err)
+		}
+
+		var responses []map[string]any
+		scanner := bufio.NewScanner(&stdout)
+		scanner.Buffer(nil, 1024*1024)
+		for scanner.Scan() {
+			var response map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			responses = append(responses, response)
+		}
+
+		if len(responses) != 4 {
+			t.Fatalf("Expected 4 responses, got %d: %v", len(responses), responses)
+		}
+		if responses[0]["id"] != float64(1) || responses[3]["id"] != float64(3) {
+			t.Errorf("Expected responses to pings 1 and 3, got %v and %v", responses[0], responses[3])
+		}
+
+		result := responses[1]["result"].(map[string]any)
+		content := result["content"].([]any)[0].(map[string]any)
+		if content["text"] != longText {
+			t.Errorf("Expected the long text to be echoed back, got %d bytes", len(content["text"].(string)))
+		}
+
+		if responses[2]["error"].(map[string]any)["code"] != float64(mcp.PARSE_ERROR) {
+			t.Errorf("Expected a parse error, got %v", responses[2])
+		}
+		if !strings.Contains(logs.String(), "Error parsing message") {
+			t.Errorf("Expected the malformed message to be logged, got %q", logs.String())
+		}
+	})
+
+	t.Run("Rejects lines over the request size limit", func(t *testing.T) {
+		mcpServer := NewMCPServer("test", "1.0.0", WithMaxRequestBytes(1024))
+
+		var logs strings.Builder
+		stdioServer := NewStdioServer(mcpServer)
+		stdioServer.SetErrorLogger(log.New(&logs, "", 0))
+
+		// Much larger than both the limit and the bufio.Reader buffer
+		hugeText := strings.Repeat("x", 1024*1024)
+		input := strings.Join([]string{
+			`{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+			fmt.Sprintf(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"text":%q}}}`, hugeText),
+			`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+			fmt.Sprintf(`{"jsonrpc":"2.0","id":4,"method":"ping","params":{"pad":%q}}`, hugeText),
+		}, "\n")
+
+		var stdout bytes.Buffer
+		err := stdioServer.Listen(context.Background(), strings.NewReader(input), &stdout)
+		if err != nil {
+			t.Fatalf("unexpected server error: %v", err)
+		}
+
+		var responses []map[string]any
+		scanner := bufio.NewScanner(&stdout)
+		for scanner.Scan() {
+			var response map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			responses = append(responses, response)
+		}
+
+		if len(responses) != 4 {
+			t.Fatalf("Expected 4 responses, got %d: %v", len(responses), responses)
+		}
+		if responses[0]["id"] != float64(1) || responses[2]["id"] != float64(3) {
+			t.Errorf("Expected responses to pings 1 and 3, got %v and %v", responses[0], responses[2])
+		}
+		for _, response := range []map[string]any{responses[1], responses[3]} {
+			errObj, ok := response["error"].(map[string]any)
+			if !ok || errObj["code"] != float64(mcp.INVALID_REQUEST) {
+				t.Errorf("Expected an invalid request error, got %v", response)
+			}
+		}
+		if !strings.Contains(logs.String(), "exceeds 1024 bytes") {
+			t.Errorf("Expected the oversized message to be logged, got %q", logs.String())
+		}
+	})
 }
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// toolDefinition is a tool as read by AddToolsFromJSON, in the form tools are
+// listed in tools/list.
+type toolDefinition struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	InputSchema json.RawMessage     `json:"inputSchema"`
+	Annotations *mcp.ToolAnnotation `json:"annotations,omitempty"`
+}
+
+// AddToolsFromJSON registers tools defined outside of Go code, e.g. generated
+// from an OpenAPI spec or kept in a config file. r must hold a JSON array of
+// tool definitions in the form tools/list returns them:
+//
+//	[{"name": "get_weather", "description": "...", "inputSchema": {"type": "object", ...}}]
+//
+// The input schema is used as is. handlerResolver is called with each tool's
+// name and must return its handler. Either all tools are registered or, if a
+// definition is invalid or no handler is found for it, none are and the
+// returned error wraps ErrInvalidToolDefinition.
+func (s *MCPServer) AddToolsFromJSON(r io.Reader, handlerResolver func(name string) ToolHandlerFunc) error {
+	var definitions []toolDefinition
+	if err := json.NewDecoder(r).Decode(&definitions); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToolDefinition, err)
+	}
+
+	tools := make([]ServerTool, 0, len(definitions))
+	seen := make(map[string]bool, len(definitions))
+	for i, definition := range definitions {
+		if definition.Name == "" {
+			return fmt.Errorf("%w: tool %d has no name", ErrInvalidToolDefinition, i)
+		}
+		if s.strictToolNames {
+			if err := checkToolName(definition.Name); err != nil {
+				return err
+			}
+		}
+		if seen[definition.Name] {
+			return fmt.Errorf("%w: tool %s is defined more than once", ErrInvalidToolDefinition, definition.Name)
+		}
+		seen[definition.Name] = true
+
+		schema := bytes.TrimSpace(definition.InputSchema)
+		if len(schema) == 0 || schema[0] != '{' {
+			return fmt.Errorf("%w: tool %s: inputSchema must be a JSON object", ErrInvalidToolDefinition, definition.Name)
+		}
+
+		handler := handlerResolver(definition.Name)
+		if handler == nil {
+			return fmt.Errorf("%w: tool %s: no handler", ErrInvalidToolDefinition, definition.Name)
+		}
+
+		tool := mcp.NewToolWithRawSchema(definition.Name, definition.Description, schema)
+		if definition.Annotations != nil {
+			tool.Annotations = *definition.Annotations
+		}
+		tools = append(tools, ServerTool{Tool: tool, Handler: handler})
+	}
+
+	s.AddTools(tools...)
+	return nil
+}
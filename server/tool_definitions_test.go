@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_AddToolsFromJSON(t *testing.T) {
+	definitions := `[
+		{
+			"name": "get_weather",
+			"description": "Get the weather for a city",
+			"inputSchema": {"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]},
+			"annotations": {"readOnlyHint": true}
+		},
+		{
+			"name": "get_time",
+			"inputSchema": {"type": "object"}
+		}
+	]`
+	handlers := map[string]ToolHandlerFunc{
+		"get_weather": func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("sunny in " + request.Params.Arguments["city"].(string)), nil
+		},
+		"get_time": func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("noon"), nil
+		},
+	}
+	resolve := func(name string) ToolHandlerFunc { return handlers[name] }
+
+	t.Run("Registers all tools", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0")
+		require.NoError(t, server.AddToolsFromJSON(strings.NewReader(definitions), resolve))
+
+		response := server.HandleMessage(context.Background(), []byte(`{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		listed, err := json.Marshal(resp.Result)
+		require.NoError(t, err)
+
+		var result struct {
+			Tools []struct {
+				Name        string          `json:"name"`
+				Description string          `json:"description"`
+				InputSchema json.RawMessage `json:"inputSchema"`
+				Annotations map[string]any  `json:"annotations"`
+			} `json:"tools"`
+		}
+		require.NoError(t, json.Unmarshal(listed, &result))
+		require.Len(t, result.Tools, 2)
+		assert.Equal(t, "get_time", result.Tools[0].Name)
+		assert.Equal(t, "get_weather", result.Tools[1].Name)
+		assert.Equal(t, "Get the weather for a city", result.Tools[1].Description)
+		assert.JSONEq(t,
+			`{"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]}`,
+			string(result.Tools[1].InputSchema))
+		assert.Equal(t, true, result.Tools[1].Annotations["readOnlyHint"])
+
+		response = server.HandleMessage(context.Background(), []byte(
+			`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "get_weather", "arguments": {"city": "Seattle"}}}`,
+		))
+		resp, ok = response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+		callResult, ok := resp.Result.(mcp.CallToolResult)
+		require.True(t, ok)
+		assert.Equal(t, "sunny in Seattle", callResult.Content[0].(mcp.TextContent).Text)
+	})
+
+	invalid := []struct {
+		name        string
+		definitions string
+		wantErr     string
+	}{
+		{name: "Malformed JSON", definitions: `[{"name": `},
+		{name: "Not an array", definitions: `{"name": "get_time"}`},
+		{name: "Missing name", definitions: `[{"inputSchema": {"type": "object"}}]`, wantErr: "tool 0 has no name"},
+		{name: "Missing input schema", definitions: `[{"name": "get_time"}]`, wantErr: "inputSchema must be a JSON object"},
+		{
+			name:        "Duplicate name",
+			definitions: `[{"name": "get_time", "inputSchema": {}}, {"name": "get_time", "inputSchema": {}}]`,
+			wantErr:     "defined more than once",
+		},
+		{
+			name:        "Unresolved handler",
+			definitions: `[{"name": "get_time", "inputSchema": {}}, {"name": "get_date", "inputSchema": {}}]`,
+			wantErr:     "tool get_date: no handler",
+		},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewMCPServer("test-server", "1.0.0")
+			err := server.AddToolsFromJSON(strings.NewReader(tt.definitions), resolve)
+			require.ErrorIs(t, err, ErrInvalidToolDefinition)
+			if tt.wantErr != "" {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+			assert.Empty(t, server.tools, "no tools should be registered")
+		})
+	}
+}
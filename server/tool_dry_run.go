@@ -0,0 +1,173 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// ToolDryRunResult describes a tool call that was validated but not
+// executed. It is returned as JSON text content of the tools/call result when
+// the request sets "_meta": {"dryRun": true} and the server was created with
+// WithToolDryRun.
+type ToolDryRunResult struct {
+	// Arguments are the call's arguments with schema defaults applied to
+	// the ones the caller omitted.
+	Arguments map[string]any `json:"arguments"`
+	// Valid reports whether the arguments passed schema validation.
+	Valid bool `json:"valid"`
+	// Warnings lists each validation problem found in the arguments.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// WithToolDryRun lets clients preview tool calls. A tools/call request whose
+// "_meta" has "dryRun" set to true is authorized as usual, then its
+// arguments are resolved against the tool's input schema and returned as a
+// ToolDryRunResult instead of calling the handler. Without this option the
+// flag is ignored.
+func WithToolDryRun() ServerOption {
+	return func(s *MCPServer) {
+		s.toolDryRun = true
+	}
+}
+
+// isDryRun reports whether the request asks for a dry run.
+func isDryRun(meta *mcp.Meta) bool {
+	if meta == nil {
+		return false
+	}
+	dryRun, _ := meta.AdditionalFields["dryRun"].(bool)
+	return dryRun
+}
+
+// dryRunTool resolves the request's arguments against the tool's input
+// schema without calling its handler.
+func dryRunTool(tool mcp.Tool, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	arguments, warnings := resolveToolArguments(tool, request.Params.Arguments)
+	text, err := json.Marshal(ToolDryRunResult{
+		Arguments: arguments,
+		Valid:     len(warnings) == 0,
+		Warnings:  warnings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := mcp.NewToolResultText(string(text))
+	result.Meta = map[string]any{"dryRun": true}
+	return result, nil
+}
+
+// resolveToolArguments returns a copy of arguments with property defaults
+// from the tool's input schema filled in, and a description of each missing
+// required argument, undeclared argument, type mismatch and value outside a
+// property's enum.
+func resolveToolArguments(tool mcp.Tool, arguments map[string]any) (map[string]any, []string) {
+	properties, required := tool.InputSchema.Properties, tool.InputSchema.Required
+	if tool.RawInputSchema != nil {
+		var schema struct {
+			Properties map[string]any `json:"properties"`
+			Required   []string       `json:"required"`
+		}
+		if err := json.Unmarshal(tool.RawInputSchema, &schema); err != nil {
+			return arguments, []string{fmt.Sprintf("invalid input schema: %v", err)}
+		}
+		properties, required = schema.Properties, schema.Required
+	}
+
+	resolved := make(map[string]any, len(arguments)+len(properties))
+	for name, value := range arguments {
+		resolved[name] = value
+	}
+	for name, property := range properties {
+		if _, ok := resolved[name]; ok {
+			continue
+		}
+		if property, ok := property.(map[string]any); ok {
+			if value, ok := property["default"]; ok {
+				resolved[name] = value
+			}
+		}
+	}
+
+	var warnings []string
+	for _, name := range required {
+		if _, ok := resolved[name]; !ok {
+			warnings = append(warnings, fmt.Sprintf("missing required argument %q", name))
+		}
+	}
+
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, declared := properties[name]
+		if !declared {
+			if len(properties) > 0 {
+				warnings = append(warnings, fmt.Sprintf("unknown argument %q", name))
+			}
+			continue
+		}
+		schema, ok := property.(map[string]any)
+		if !ok {
+			continue
+		}
+		value := arguments[name]
+		if want, ok := schema["type"].(string); ok && !jsonTypeMatches(want, value) {
+			warnings = append(warnings, fmt.Sprintf("argument %q should be of type %s, got %T", name, want, value))
+		}
+		if enum, ok := schema["enum"]; ok && !enumContains(enum, value) {
+			warnings = append(warnings, fmt.Sprintf("argument %q is not one of the allowed values %v", name, enum))
+		}
+	}
+	return resolved, warnings
+}
+
+// jsonTypeMatches reports whether value, as decoded by encoding/json, is of
+// the given JSON Schema type. Unknown types always match.
+func jsonTypeMatches(schemaType string, value any) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		return true
+	}
+}
+
+// enumContains reports whether value is one of the enum values. The enum is
+// a []string when set with mcp.Enum and a []any when decoded from a raw
+// schema; anything that is not a slice allows every value.
+func enumContains(enum any, value any) bool {
+	values := reflect.ValueOf(enum)
+	if values.Kind() != reflect.Slice {
+		return true
+	}
+	for i := 0; i < values.Len(); i++ {
+		if reflect.DeepEqual(values.Index(i).Interface(), value) {
+			return true
+		}
+	}
+	return false
+}
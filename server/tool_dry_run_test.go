@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_ToolDryRun(t *testing.T) {
+	called := false
+	tool := mcp.NewTool("deploy",
+		mcp.WithString("service", mcp.Required()),
+		mcp.WithString("env", mcp.Enum("staging", "production"), mcp.DefaultString("staging")),
+		mcp.WithNumber("replicas", mcp.DefaultNumber(1)),
+	)
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		return mcp.NewToolResultText("deployed"), nil
+	}
+	callTool := func(server *MCPServer, params string) mcp.CallToolResult {
+		t.Helper()
+		response := server.HandleMessage(context.Background(), []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": `+params+`
+		}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected response, got %#v", response)
+		result, ok := resp.Result.(mcp.CallToolResult)
+		require.True(t, ok)
+		return result
+	}
+	dryRunResult := func(result mcp.CallToolResult) ToolDryRunResult {
+		t.Helper()
+		require.Len(t, result.Content, 1)
+		var dryRun ToolDryRunResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &dryRun))
+		return dryRun
+	}
+
+	server := NewMCPServer("test-server", "1.0.0", WithToolDryRun())
+	server.AddTool(tool, handler)
+
+	t.Run("resolves defaults without calling the handler", func(t *testing.T) {
+		result := callTool(server, `{"name": "deploy", "arguments": {"service": "api"}, "_meta": {"dryRun": true}}`)
+		assert.False(t, called)
+		assert.Equal(t, true, result.Meta["dryRun"])
+
+		dryRun := dryRunResult(result)
+		assert.True(t, dryRun.Valid)
+		assert.Empty(t, dryRun.Warnings)
+		assert.Equal(t, map[string]any{
+			"service":  "api",
+			"env":      "staging",
+			"replicas": float64(1),
+		}, dryRun.Arguments)
+	})
+
+	t.Run("reports validation warnings", func(t *testing.T) {
+		result := callTool(server, `{"name": "deploy", "arguments": {"env": "qa", "replicas": "two", "force": true}, "_meta": {"dryRun": true}}`)
+		assert.False(t, called)
+
+		dryRun := dryRunResult(result)
+		assert.False(t, dryRun.Valid)
+		assert.Equal(t, []string{
+			`missing required argument "service"`,
+			`argument "env" is not one of the allowed values [staging production]`,
+			`unknown argument "force"`,
+			`argument "replicas" should be of type number, got string`,
+		}, dryRun.Warnings)
+	})
+
+	t.Run("ignored unless enabled", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0")
+		server.AddTool(tool, handler)
+		result := callTool(server, `{"name": "deploy", "arguments": {"service": "api"}, "_meta": {"dryRun": true}}`)
+		assert.True(t, called)
+		assert.Equal(t, "deployed", result.Content[0].(mcp.TextContent).Text)
+	})
+}
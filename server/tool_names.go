@@ -0,0 +1,30 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// WithStrictToolNames makes AddTool and AddTools panic when a tool name is
+// not valid according to mcp.IsValidToolName, e.g. because it contains a
+// space, so the mistake is caught at startup. AddToolsFromJSON returns an
+// error instead. Without this option, invalid names are accepted and only
+// reported by WithToolSchemaLint.
+func WithStrictToolNames() ServerOption {
+	return func(s *MCPServer) {
+		s.strictToolNames = true
+	}
+}
+
+// checkToolName returns an error wrapping ErrInvalidToolDefinition if name
+// is not a valid tool name.
+func checkToolName(name string) error {
+	if mcp.IsValidToolName(name) {
+		return nil
+	}
+	return fmt.Errorf(
+		"%w: tool name %q must be 1 to %d letters, digits, underscores, dots or hyphens",
+		ErrInvalidToolDefinition, name, mcp.MaxToolNameLength,
+	)
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_WithStrictToolNames(t *testing.T) {
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	server := NewMCPServer("test-server", "1.0.0", WithStrictToolNames())
+
+	assert.NotPanics(t, func() {
+		server.AddTool(mcp.NewTool("files.read_v2-beta"), handler)
+	})
+	assert.PanicsWithError(t,
+		`invalid tool definition: tool name "read file" must be 1 to 128 letters, digits, underscores, dots or hyphens`,
+		func() { server.AddTool(mcp.NewTool("read file"), handler) },
+	)
+
+	err := server.AddToolsFromJSON(strings.NewReader(`[{"name": "list/files", "inputSchema": {"type": "object"}}]`),
+		func(name string) ToolHandlerFunc { return handler })
+	assert.ErrorIs(t, err, ErrInvalidToolDefinition)
+
+	assert.Equal(t, []string{"files.read_v2-beta"}, toolNames(server.Tools()))
+
+	// Without the option invalid names are accepted
+	lenient := NewMCPServer("test-server", "1.0.0")
+	assert.NotPanics(t, func() {
+		lenient.AddTool(mcp.NewTool("read file"), handler)
+	})
+}
+
+func toolNames(tools []mcp.Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	return names
+}
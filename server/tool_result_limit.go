@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// truncatedMarker is appended to text content that was cut to fit the
+// configured tool result size limit.
+const truncatedMarker = "[truncated]"
+
+// WithMaxToolResultBytes caps the serialized size of a tool call result.
+// Results larger than n bytes have their text content truncated, starting
+// with the last text item, and a "[truncated]" marker appended. Use
+// WithToolResultOverflowError to reject oversized results instead.
+// A limit of zero or less disables the check.
+func WithMaxToolResultBytes(n int) ServerOption {
+	return func(s *MCPServer) {
+		s.maxToolResultBytes = n
+	}
+}
+
+// WithToolResultOverflowError makes the server answer tool calls whose result
+// exceeds the limit set by WithMaxToolResultBytes with an error wrapping
+// ErrToolResultTooLarge, instead of truncating the result.
+func WithToolResultOverflowError() ServerOption {
+	return func(s *MCPServer) {
+		s.toolResultOverflowErr = true
+	}
+}
+
+// limitToolResult enforces the configured tool result size limit. The result
+// passed in is never modified; a truncated copy is returned instead.
+func (s *MCPServer) limitToolResult(
+	toolName string,
+	result *mcp.CallToolResult,
+) (*mcp.CallToolResult, error) {
+	size, err := toolResultSize(result)
+	if err != nil {
+		return nil, err
+	}
+	if size <= s.maxToolResultBytes {
+		return result, nil
+	}
+
+	tooLarge := fmt.Errorf(
+		"result of tool %s is %d bytes, limit is %d: %w",
+		toolName, size, s.maxToolResultBytes, ErrToolResultTooLarge,
+	)
+	if s.toolResultOverflowErr {
+		return nil, tooLarge
+	}
+
+	truncated := *result
+	truncated.Content = append([]mcp.Content(nil), result.Content...)
+
+	for i := len(truncated.Content) - 1; i >= 0; i-- {
+		textContent, ok := truncated.Content[i].(mcp.TextContent)
+		if !ok {
+			continue
+		}
+
+		for {
+			if size <= s.maxToolResultBytes {
+				return &truncated, nil
+			}
+
+			text := strings.TrimSuffix(textContent.Text, truncatedMarker)
+			if text == "" {
+				break
+			}
+
+			// Cut the excess plus room for the marker. JSON escaping can make
+			// the text larger once serialized, so re-measure after each cut.
+			cut := size - s.maxToolResultBytes
+			if text == textContent.Text {
+				cut += len(truncatedMarker)
+			}
+			keep := max(len(text)-cut, 0)
+			for keep > 0 && !utf8.RuneStart(text[keep]) {
+				keep--
+			}
+
+			textContent.Text = text[:keep] + truncatedMarker
+			truncated.Content[i] = textContent
+
+			size, err = toolResultSize(&truncated)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if size <= s.maxToolResultBytes {
+		return &truncated, nil
+	}
+
+	// Truncating every text item was not enough, e.g. the result is made of
+	// images or embedded resources.
+	return nil, tooLarge
+}
+
+// toolResultSize returns the size of the result once serialized to JSON.
+func toolResultSize(result *mcp.CallToolResult) (int, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal tool result: %w", err)
+	}
+	return len(data), nil
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_WithMaxToolResultBytes(t *testing.T) {
+	hugeText := strings.Repeat("all work and no play ", 10000)
+	hugeHandler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(hugeText), nil
+	}
+	callMessage := []byte(`{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "tools/call",
+		"params": {
+			"name": "huge-tool"
+		}
+	}`)
+
+	t.Run("truncates text content", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0", WithMaxToolResultBytes(1024))
+		server.AddTool(mcp.NewTool("huge-tool"), hugeHandler)
+
+		response := server.HandleMessage(context.Background(), callMessage)
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+
+		result, ok := resp.Result.(mcp.CallToolResult)
+		require.True(t, ok)
+		require.Len(t, result.Content, 1)
+
+		textContent, ok := result.Content[0].(mcp.TextContent)
+		require.True(t, ok)
+		assert.True(t, strings.HasSuffix(textContent.Text, "[truncated]"))
+		assert.True(t, strings.HasPrefix(hugeText, strings.TrimSuffix(textContent.Text, "[truncated]")))
+
+		data, err := json.Marshal(result)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(data), 1024)
+	})
+
+	t.Run("leaves small results untouched", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0", WithMaxToolResultBytes(1024))
+		server.AddTool(mcp.NewTool("small-tool"), func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("small"), nil
+		})
+
+		response := server.HandleMessage(context.Background(), []byte(`{
+			"jsonrpc": "2.0",
+			"id": 1,
+			"method": "tools/call",
+			"params": {
+				"name": "small-tool"
+			}
+		}`))
+		resp, ok := response.(mcp.JSONRPCResponse)
+		require.True(t, ok)
+
+		result, ok := resp.Result.(mcp.CallToolResult)
+		require.True(t, ok)
+		textContent, ok := result.Content[0].(mcp.TextContent)
+		require.True(t, ok)
+		assert.Equal(t, "small", textContent.Text)
+	})
+
+	t.Run("returns an error when configured", func(t *testing.T) {
+		var hookErr error
+		hooks := &Hooks{}
+		hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+			hookErr = err
+		})
+
+		server := NewMCPServer("test-server", "1.0.0",
+			WithMaxToolResultBytes(1024),
+			WithToolResultOverflowError(),
+			WithHooks(hooks),
+		)
+		server.AddTool(mcp.NewTool("huge-tool"), hugeHandler)
+
+		response := server.HandleMessage(context.Background(), callMessage)
+		errorResponse, ok := response.(mcp.JSONRPCError)
+		require.True(t, ok)
+		assert.Equal(t, mcp.INTERNAL_ERROR, errorResponse.Error.Code)
+		assert.True(t, errors.Is(hookErr, ErrToolResultTooLarge))
+	})
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// WithToolSchemaLint makes AddTool and AddTools check each tool's input
+// schema and log problems with the standard logger, so they are caught at
+// startup rather than when a client lists the tools. Schema conflicts that
+// would make tools/list fail and names mcp.IsValidToolName rejects are logged
+// as errors; tools without any input properties, or with required arguments
+// that are not declared, are logged as warnings. Tools are registered either
+// way.
+func WithToolSchemaLint() ServerOption {
+	return func(s *MCPServer) {
+		s.toolSchemaLintf = log.Printf
+	}
+}
+
+// lintToolSchema logs the problems found in the tool's input schema.
+func (s *MCPServer) lintToolSchema(tool mcp.Tool) {
+	for _, problem := range toolSchemaProblems(tool) {
+		s.toolSchemaLintf("%s", problem)
+	}
+}
+
+// toolSchemaProblems returns a description of each problem with the tool's
+// name and input schema.
+func toolSchemaProblems(tool mcp.Tool) []string {
+	var problems []string
+	if err := checkToolName(tool.Name); err != nil {
+		problems = append(problems, fmt.Sprintf("error: %v", err))
+	}
+	if err := tool.ValidateSchema(); err != nil {
+		return append(problems, fmt.Sprintf("error: %v", err))
+	}
+	if tool.RawInputSchema != nil {
+		return problems
+	}
+
+	if tool.InputSchema.Type == "" {
+		problems = append(problems, fmt.Sprintf("error: tool %s has no input schema type", tool.Name))
+	}
+	if len(tool.InputSchema.Properties) == 0 {
+		problems = append(problems, fmt.Sprintf(
+			"warning: tool %s declares no input properties; declare any arguments its handler reads", tool.Name))
+	}
+
+	var undeclared []string
+	for _, name := range tool.InputSchema.Required {
+		if _, ok := tool.InputSchema.Properties[name]; !ok {
+			undeclared = append(undeclared, name)
+		}
+	}
+	sort.Strings(undeclared)
+	for _, name := range undeclared {
+		problems = append(problems, fmt.Sprintf(
+			"warning: tool %s requires argument %s which is not a declared property", tool.Name, name))
+	}
+
+	return problems
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_WithToolSchemaLint(t *testing.T) {
+	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+
+	conflicting := mcp.NewTool("conflicting-tool", mcp.WithString("query"))
+	conflicting.RawInputSchema = []byte(`{"type": "object"}`)
+
+	undeclared := mcp.NewTool("undeclared-tool", mcp.WithString("query"))
+	undeclared.InputSchema.Required = []string{"limit"}
+
+	tests := []struct {
+		name string
+		tool mcp.Tool
+		want []string
+	}{
+		{
+			name: "valid tool",
+			tool: mcp.NewTool("valid-tool", mcp.WithString("query", mcp.Required())),
+			want: nil,
+		},
+		{
+			name: "raw schema",
+			tool: mcp.NewToolWithRawSchema("raw-tool", "", []byte(`{"type": "object"}`)),
+			want: nil,
+		},
+		{
+			name: "schema conflict",
+			tool: conflicting,
+			want: []string{"error: tool conflicting-tool has both InputSchema and RawInputSchema set: provide either InputSchema or RawInputSchema, not both"},
+		},
+		{
+			name: "no properties",
+			tool: mcp.NewTool("empty-tool"),
+			want: []string{"warning: tool empty-tool declares no input properties; declare any arguments its handler reads"},
+		},
+		{
+			name: "invalid name",
+			tool: mcp.NewTool("search docs", mcp.WithString("query")),
+			want: []string{`error: invalid tool definition: tool name "search docs" must be 1 to 128 letters, digits, underscores, dots or hyphens`},
+		},
+		{
+			name: "undeclared required argument",
+			tool: undeclared,
+			want: []string{"warning: tool undeclared-tool requires argument limit which is not a declared property"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged []string
+			server := NewMCPServer("test-server", "1.0.0", WithToolSchemaLint())
+			server.toolSchemaLintf = func(format string, v ...any) {
+				logged = append(logged, fmt.Sprintf(format, v...))
+			}
+
+			server.AddTool(tt.tool, handler)
+			assert.Equal(t, tt.want, logged)
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		server := NewMCPServer("test-server", "1.0.0")
+		assert.Nil(t, server.toolSchemaLintf)
+		server.AddTool(conflicting, handler)
+	})
+}
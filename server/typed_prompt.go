@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zillow/mcp-go/mcp"
+)
+
+// TypedPromptHandlerFunc handles prompt requests with arguments bound to T.
+type TypedPromptHandlerFunc[T any] func(ctx context.Context, request mcp.GetPromptRequest, args T) (*mcp.GetPromptResult, error)
+
+// NewTypedPrompt returns prompt along with a PromptHandlerFunc that binds the
+// prompts/get arguments into a T before calling handler, so both can be
+// passed straight to AddPrompt:
+//
+//	type styleArgs struct {
+//	    Temperature float64 `json:"temperature" mcp:"required"`
+//	    Style       string  `json:"style"`
+//	}
+//
+//	s.AddPrompt(server.NewTypedPrompt(prompt,
+//	    func(ctx context.Context, request mcp.GetPromptRequest, args styleArgs) (*mcp.GetPromptResult, error) {
+//	        ...
+//	    }))
+//
+// T must be a struct. Arguments are matched to exported fields by their json
+// tag name, or the field name if untagged, and converted from strings to
+// strings, bools, integers, floats or any type implementing
+// encoding.TextUnmarshaler. A field is required if it is tagged
+// `mcp:"required"` or the prompt declares the argument as required.
+// Missing required arguments and values that cannot be converted are
+// reported to the client as INVALID_PARAMS.
+func NewTypedPrompt[T any](prompt mcp.Prompt, handler TypedPromptHandlerFunc[T]) (mcp.Prompt, PromptHandlerFunc) {
+	return prompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		var args T
+		if err := bindPromptArguments(prompt, request.Params.Arguments, &args); err != nil {
+			return nil, err
+		}
+		return handler(ctx, request, args)
+	}
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// bindPromptArguments sets the fields of the struct target points to from
+// the string arguments of a prompts/get request.
+func bindPromptArguments(prompt mcp.Prompt, arguments map[string]string, target any) error {
+	v := reflect.ValueOf(target).Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("prompt %s: arguments must bind to a struct, got %s", prompt.Name, v.Type())
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		value, ok := arguments[name]
+		if !ok {
+			if slices.Contains(strings.Split(field.Tag.Get("mcp"), ","), "required") ||
+				promptArgumentRequired(prompt, name) {
+				return fmt.Errorf("%w: prompt %s: missing required argument %q", ErrInvalidPromptArguments, prompt.Name, name)
+			}
+			continue
+		}
+
+		if err := setPromptArgument(v.Field(i), value); err != nil {
+			return fmt.Errorf("%w: prompt %s: argument %q: %w", ErrInvalidPromptArguments, prompt.Name, name, err)
+		}
+	}
+
+	return nil
+}
+
+// promptArgumentRequired reports whether the prompt declares the argument
+// with the given name as required.
+func promptArgumentRequired(prompt mcp.Prompt, name string) bool {
+	for _, arg := range prompt.Arguments {
+		if arg.Name == name {
+			return arg.Required
+		}
+	}
+	return false
+}
+
+// setPromptArgument converts value to the type of field and stores it.
+func setPromptArgument(field reflect.Value, value string) error {
+	if reflect.PointerTo(field.Type()).Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zillow/mcp-go/mcp"
+)
+
+func TestMCPServer_TypedPrompt(t *testing.T) {
+	type styleArgs struct {
+		Temperature float64 `json:"temperature" mcp:"required"`
+		Style       string  `json:"style"`
+		MaxWords    int     `json:"max_words"`
+		Formal      bool
+	}
+
+	server := NewMCPServer("test-server", "1.0.0", WithPromptCapabilities(true))
+
+	var bound styleArgs
+	server.AddPrompt(NewTypedPrompt(
+		mcp.NewPrompt("styled",
+			mcp.WithArgument("temperature"),
+			mcp.WithArgument("style", mcp.RequiredArgument()),
+		),
+		func(ctx context.Context, request mcp.GetPromptRequest, args styleArgs) (*mcp.GetPromptResult, error) {
+			bound = args
+			return mcp.NewGetPromptResult("styled", []mcp.PromptMessage{
+				mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(
+					fmt.Sprintf("temperature=%v style=%s", args.Temperature, args.Style),
+				)),
+			}), nil
+		},
+	))
+
+	tests := []struct {
+		name      string
+		arguments string
+		validate  func(t *testing.T, response mcp.JSONRPCMessage)
+	}{
+		{
+			name:      "Arguments are converted to field types",
+			arguments: `{"temperature": "0.7", "style": "terse", "max_words": "120", "Formal": "true"}`,
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				resp, ok := response.(mcp.JSONRPCResponse)
+				require.True(t, ok)
+
+				result, ok := resp.Result.(mcp.GetPromptResult)
+				require.True(t, ok)
+				require.Len(t, result.Messages, 1)
+				assert.Equal(t, "temperature=0.7 style=terse", result.Messages[0].Content.(mcp.TextContent).Text)
+				assert.Equal(t, styleArgs{Temperature: 0.7, Style: "terse", MaxWords: 120, Formal: true}, bound)
+			},
+		},
+		{
+			name:      "Missing argument required by tag",
+			arguments: `{"style": "terse"}`,
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				resp, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok)
+				assert.Equal(t, mcp.INVALID_PARAMS, resp.Error.Code)
+				assert.Contains(t, resp.Error.Message, `"temperature"`)
+			},
+		},
+		{
+			name:      "Missing argument required by prompt",
+			arguments: `{"temperature": "0.7"}`,
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				resp, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok)
+				assert.Equal(t, mcp.INVALID_PARAMS, resp.Error.Code)
+				assert.Contains(t, resp.Error.Message, `"style"`)
+			},
+		},
+		{
+			name:      "Argument that cannot be converted",
+			arguments: `{"temperature": "warm", "style": "terse"}`,
+			validate: func(t *testing.T, response mcp.JSONRPCMessage) {
+				resp, ok := response.(mcp.JSONRPCError)
+				require.True(t, ok)
+				assert.Equal(t, mcp.INVALID_PARAMS, resp.Error.Code)
+				assert.Contains(t, resp.Error.Message, `"temperature"`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := fmt.Sprintf(
+				`{"jsonrpc": "2.0", "id": 1, "method": "prompts/get", "params": {"name": "styled", "arguments": %s}}`,
+				tt.arguments,
+			)
+			response := server.HandleMessage(context.Background(), []byte(message))
+			tt.validate(t, response)
+		})
+	}
+}
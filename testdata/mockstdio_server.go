@@ -25,6 +25,9 @@ type JSONRPCResponse struct {
 	} `json:"error,omitempty"`
 }
 
+// initialized records whether this process received an initialize request.
+var initialized bool
+
 func main() {
 	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{}))
 	logger.Info("launch successful")
@@ -49,6 +52,7 @@ func handleRequest(request JSONRPCRequest) JSONRPCResponse {
 
 	switch request.Method {
 	case "initialize":
+		initialized = true
 		response.Result = map[string]any{
 			"protocolVersion": "1.0",
 			"serverInfo": map[string]any{
@@ -153,6 +157,10 @@ func handleRequest(request JSONRPCRequest) JSONRPCResponse {
 		})
 		fmt.Fprintf(os.Stdout, "%s\n", responseBytes)
 
+	case "debug/initialized":
+		response.Result = map[string]any{"initialized": initialized}
+	case "debug/exit":
+		os.Exit(1)
 	case "debug/echo_error_string":
 		all, _ := json.Marshal(request)
 		response.Error = &struct {